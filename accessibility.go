@@ -0,0 +1,237 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Set by --screen-reader or screen_reader in the config file; lays the
+// screen out as plain labeled lines and announces what changes
+var screenReader bool
+
+// editorPrompt returns the marker drawn before each editor line, which
+// screen readers would otherwise read out on every line
+func editorPrompt() string {
+	if screenReader {
+		return ""
+	}
+	return "┃ "
+}
+
+// announce prints a line describing what a message changed, so in
+// screen-reader mode it is spoken without rereading the screen
+func announce(before, after model) tea.Cmd {
+	if !screenReader {
+		return nil
+	}
+	lines := announcements(before, after)
+	if len(lines) == 0 {
+		return nil
+	}
+	return tea.Println(strings.Join(lines, "\n"))
+}
+
+// describeMode says in words what the screen is showing
+func (m model) describeMode() string {
+	switch {
+	case m.qrCode != "":
+		return tr("QR code, press any key to close")
+	case m.popup != "":
+		return tr("Details")
+	case m.mode == "new":
+		return tr("New note")
+	case m.mode == "edit" && m.selectedNote != nil:
+		return trf("Editing %s", m.selectedNote.title)
+	case m.mode == "pick":
+		return trf("Choosing from %s", m.picker.Title)
+	case m.mode == "scratch":
+		return tr("Scratchpad")
+	case m.mode == "diff":
+		return tr("Comparing notes")
+	case m.mode == "stats":
+		return tr("Statistics")
+	case m.mode == "review":
+		return tr("Reviewing flashcards")
+	case m.mode == "quiz":
+		return tr("Quiz")
+	case m.mode == "replace":
+		return tr("Find and replace")
+	case m.mode == "merge":
+		return trf("Merging %s", m.textInput.Value())
+	case m.mode == "prompt":
+		return strings.TrimSuffix(m.prompt.Prompt, ": ")
+	case m.contentFocus && m.selectedNote != nil:
+		return trf("Reading %s", m.selectedNote.title)
+	case m.timeline:
+		return tr("Timeline")
+	}
+	return tr("Notes list")
+}
+
+// announcements lists the changes between two states worth speaking: a new
+// screen, a new selection and a new status message
+func announcements(before, after model) []string {
+	var lines []string
+	if mode := after.describeMode(); mode != before.describeMode() {
+		lines = append(lines, mode)
+	}
+
+	switch after.mode {
+	case "list":
+		if after.checklistActive() && (!before.checklistActive() || before.checkCursor != after.checkCursor ||
+			before.textarea.Value() != after.textarea.Value()) {
+			if item := after.checklistItemText(); item != "" {
+				lines = append(lines, item)
+			}
+		}
+		if after.selectedNote != nil && !after.contentFocus &&
+			(before.selectedNote == nil || before.selectedNote.path != after.selectedNote.path) {
+			lines = append(lines, trf("%s, %d of %d", after.selectedNote.title, after.list.Index()+1, len(after.list.VisibleItems())))
+		}
+	case "pick":
+		if item, ok := after.picker.SelectedItem().(pickerItem); ok &&
+			(before.mode != "pick" || before.picker.Index() != after.picker.Index()) {
+			lines = append(lines, trf("%s, %d of %d", item.title, after.picker.Index()+1, len(after.picker.VisibleItems())))
+		}
+	}
+
+	if after.status != "" && after.status != before.status {
+		lines = append(lines, after.status)
+	}
+	if after.toast.text != "" && after.toast.id != before.toast.id {
+		lines = append(lines, after.toast.text)
+	}
+	return lines
+}
+
+// linearWindow keeps the lines around the cursor that fit in height
+func linearWindow(lines []string, cursor, height int) []string {
+	height = max(1, height)
+	top := max(0, min(cursor-height/2, len(lines)-height))
+	return lines[top:min(len(lines), top+height)]
+}
+
+// linearView renders the screen as labeled lines without borders or
+// columns, in the order a screen reader should read them
+func (m model) linearView() string {
+	lines := []string{m.describeMode()}
+	height := m.height - 6
+
+	switch {
+	case m.qrCode != "":
+		lines = append(lines, m.qrCode)
+
+	case m.popup != "":
+		lines = append(lines, m.popup)
+
+	case m.mode == "pick":
+		var items []string
+		for i, item := range m.picker.VisibleItems() {
+			p := item.(pickerItem)
+			line := fmt.Sprintf("%d. %s", i+1, p.title)
+			if p.desc != "" {
+				line += " - " + p.desc
+			}
+			if i == m.picker.Index() {
+				line = "> " + line
+			}
+			items = append(items, line)
+		}
+		lines = append(lines, linearWindow(items, m.picker.Index(), height)...)
+
+	case m.mode == "new" || m.mode == "edit":
+		lines = append(lines, tr("Title:")+" "+m.textInput.View(), tr("Content:"), m.textarea.View())
+
+	case m.mode == "scratch":
+		lines = append(lines, m.scratch.View())
+
+	case m.mode == "diff":
+		lines = append(lines, m.diffTitle, m.diff.View())
+
+	case m.mode == "stats":
+		lines = append(lines, m.statsText)
+
+	case m.mode == "review":
+		lines = append(lines, m.reviewText(m.width), m.reviewHelp())
+
+	case m.mode == "quiz":
+		lines = append(lines, m.quizText(m.width), m.quizHelp())
+
+	case m.mode == "replace":
+		matches, cursor := m.replaceLines(m.width)
+		lines = append(lines, m.replaceSummary())
+		lines = append(lines, linearWindow(matches, cursor, height)...)
+		lines = append(lines, replaceHelp())
+
+	case m.mode == "merge":
+		text, cursor := m.mergeLines(m.width)
+		lines = append(lines, m.mergeSummary())
+		lines = append(lines, linearWindow(text, cursor, height)...)
+		lines = append(lines, mergeHelp())
+
+	case m.checklistActive():
+		items, cursor := m.checklistLines(m.width)
+		lines = append(lines, linearWindow(items, cursor, height)...)
+
+	case m.contentFocus:
+		lines = append(lines, m.textarea.View())
+
+	case m.timeline:
+		var rows []string
+		for i, row := range m.timelineRows() {
+			line := fmt.Sprintf("%s (%d)", row.group, row.count)
+			if row.note != nil {
+				line = "  " + row.note.title
+			}
+			if i == m.timelineCursor {
+				line = "> " + line
+			}
+			rows = append(rows, line)
+		}
+		lines = append(lines, linearWindow(rows, m.timelineCursor, height)...)
+
+	default:
+		var items []string
+		for i, item := range m.list.VisibleItems() {
+			n := item.(note)
+			line := fmt.Sprintf("%d. %s", i+1, n.title)
+			if n.readErr != nil {
+				line += ", " + tr("unreadable")
+			}
+			if n.label != "" {
+				line += " (" + tr(n.label) + ")"
+			}
+			if n.status != "" {
+				line += ", " + tr(n.status)
+			}
+			if n.priority > 0 {
+				line += ", " + trf("priority %s", priorityLabel(n.priority))
+			}
+			if i == m.list.Index() {
+				line = "> " + line
+			}
+			items = append(items, line)
+		}
+		if len(items) == 0 {
+			items = append(items, tr("No notes."))
+		}
+		if m.list.FilterState() != list.Unfiltered {
+			lines = append(lines, tr("Search:")+" "+m.list.FilterInput.View())
+		}
+		lines = append(lines, linearWindow(items, m.list.Index(), height)...)
+	}
+
+	if m.mode == "prompt" {
+		lines = append(lines, m.prompt.View())
+	}
+	if m.toast.text != "" {
+		lines = append(lines, tr("Status:")+" "+m.toast.text)
+	} else if m.status != "" {
+		lines = append(lines, tr("Status:")+" "+m.status)
+	}
+	lines = append(lines, helpText())
+	return strings.Join(lines, "\n")
+}
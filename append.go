@@ -0,0 +1,96 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// appendEntry adds text to the end of a note, optionally under a heading
+// with the current time
+func appendEntry(content, text string, stamp bool) string {
+	if content != "" && !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	if stamp {
+		if content != "" {
+			content += "\n"
+		}
+		content += "### " + localNow().Format("2006-01-02 15:04") + "\n"
+	}
+	return content + strings.TrimRight(text, "\n") + "\n"
+}
+
+// writeNoteFile replaces a note's content, running the save hooks around it
+func writeNoteFile(path, content string) error {
+	if err := runHooks(hookPreSave, path, content); err != nil {
+		return err
+	}
+	action := auditEdit
+	previous, err := readNoteFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		action = auditCreate
+	}
+	if err := storeNoteFile(path, []byte(content)); err != nil {
+		return err
+	}
+	recordWords(string(previous), content)
+	recordChange(action, path, string(previous), content)
+	return runHooks(hookPostSave, path, content)
+}
+
+// runAppend implements `gleaner append <title> [text]`, reading the text
+// from stdin when it is not given as arguments
+func runAppend(args []string) error {
+	flags := flag.NewFlagSet("append", flag.ContinueOnError)
+	stamp := flags.Bool("timestamp", false, "add a heading with the current date and time")
+	create := flags.Bool("create", false, "create the note if it does not exist")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() < 1 {
+		return fmt.Errorf("usage: gleaner append [--timestamp] [--create] <title|id> [text]")
+	}
+	if readOnly {
+		return errReadOnly
+	}
+
+	text := strings.Join(flags.Args()[1:], " ")
+	if text == "" || text == "-" {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return err
+		}
+		text = string(data)
+	}
+	if strings.TrimSpace(text) == "" {
+		return errors.New("nothing to append")
+	}
+
+	query := flags.Arg(0)
+	path := ""
+	notes, err := notesForCommand()
+	if err != nil {
+		return err
+	}
+	n, err := findNote(notes, query)
+	switch {
+	case err == nil:
+		path = n.path
+	case *create && errors.Is(err, errNoNote):
+		path = filepath.Join(notesDir, fmt.Sprintf("%d-%s%s", time.Now().Unix(), sanitizeFileName(query), newNoteExt()))
+	default:
+		return err
+	}
+
+	existing, err := readNoteFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return writeNoteFile(path, appendEntry(string(existing), text, *stamp))
+}
@@ -0,0 +1,321 @@
+package main
+
+import (
+	"archive/zip"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Folders in the notes directory looked in for attachments when
+// attachments_dir is not set
+var defaultAttachmentDirs = []string{"attachments", "assets"}
+
+// attachment is a file kept alongside the notes
+type attachment struct {
+	path     string // Location on disk
+	rel      string // Path within the vault
+	size     int64
+	modified time.Time
+}
+
+// attachmentDirs returns the folders holding attachments
+func attachmentDirs() []string {
+	if cfg.AttachmentsDir != "" {
+		dir := expandHome(cfg.AttachmentsDir)
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(notesDir, dir)
+		}
+		return []string{dir}
+	}
+	var dirs []string
+	for _, name := range defaultAttachmentDirs {
+		dirs = append(dirs, filepath.Join(notesDir, name))
+	}
+	return dirs
+}
+
+// unusedAttachments returns the attachments neither the notes nor the
+// trashed notes mention by file name, largest first, so a note restored
+// from the trash finds its attachments still there. Hidden files and notes
+// kept in the folders are skipped.
+func unusedAttachments(notes []note) ([]attachment, error) {
+	trashed, err := listTrash()
+	if err != nil {
+		return nil, err
+	}
+	paths := make([]string, 0, len(notes)+len(trashed))
+	for _, n := range notes {
+		paths = append(paths, n.path)
+	}
+	for _, t := range trashed {
+		paths = append(paths, t.path)
+	}
+	var contents []string
+	for _, path := range paths {
+		if content, err := readNoteFile(path); err == nil {
+			contents = append(contents, string(content))
+		}
+	}
+	mentioned := func(name string) bool {
+		escaped := url.PathEscape(name)
+		for _, content := range contents {
+			if strings.Contains(content, name) || strings.Contains(content, escaped) {
+				return true
+			}
+		}
+		return false
+	}
+
+	var unused []attachment
+	for _, dir := range attachmentDirs() {
+		err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if errors.Is(err, fs.ErrNotExist) && path == dir {
+				return filepath.SkipDir
+			}
+			if err != nil {
+				return err
+			}
+			if strings.HasPrefix(d.Name(), ".") && path != dir {
+				if d.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if d.IsDir() || isNoteExt(filepath.Ext(path)) || mentioned(d.Name()) {
+				return nil
+			}
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			rel, _ := filepath.Rel(notesDir, path)
+			unused = append(unused, attachment{path: path, rel: filepath.ToSlash(rel), size: info.Size(), modified: info.ModTime()})
+			return nil
+		})
+		if err != nil {
+			return unused, err
+		}
+	}
+	sort.Slice(unused, func(i, j int) bool { return unused[i].size > unused[j].size })
+	return unused, nil
+}
+
+// attachmentsSize adds up the size of attachments
+func attachmentsSize(files []attachment) int64 {
+	var total int64
+	for _, f := range files {
+		total += f.size
+	}
+	return total
+}
+
+// deleteAttachments removes the files, returning the bytes reclaimed
+func deleteAttachments(files []attachment) (int64, error) {
+	var reclaimed int64
+	for _, f := range files {
+		if err := os.Remove(f.path); err != nil {
+			return reclaimed, err
+		}
+		reclaimed += f.size
+	}
+	return reclaimed, nil
+}
+
+// archiveAttachments moves the files into a zip archive in the backup
+// directory, returning its path and the bytes reclaimed in the vault
+func archiveAttachments(files []attachment) (string, int64, error) {
+	dir := backupDir()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", 0, err
+	}
+	path := filepath.Join(dir, fmt.Sprintf("attachments-%s.zip", time.Now().Format("20060102-150405")))
+	out, err := os.Create(path)
+	if err != nil {
+		return "", 0, err
+	}
+
+	zw := zip.NewWriter(out)
+	for _, f := range files {
+		w, err := zw.CreateHeader(&zip.FileHeader{Name: f.rel, Method: zip.Deflate, Modified: f.modified})
+		if err == nil {
+			err = copyFile(w, f.path)
+		}
+		if err != nil {
+			zw.Close()
+			out.Close()
+			os.Remove(path)
+			return "", 0, err
+		}
+	}
+	err = zw.Close()
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+		return "", 0, err
+	}
+
+	reclaimed, err := deleteAttachments(files)
+	return path, reclaimed, err
+}
+
+// vaultAttachments returns the unused attachments, looking through every
+// note in the vault rather than those the list is filtered to
+func vaultAttachments() ([]attachment, error) {
+	notes, err := scanNotes()
+	if err != nil && len(notes) == 0 {
+		return nil, err
+	}
+	return unusedAttachments(notes)
+}
+
+// openAttachments lists the attachments no note uses, with entries to
+// archive or delete them all; choosing a file offers the same for it alone.
+// Deleting them all comes last and asks for "yes" first.
+func (m model) openAttachments() model {
+	unused, err := vaultAttachments()
+	if err != nil {
+		m.status = err.Error()
+		return m
+	}
+	if len(unused) == 0 {
+		m.status = tr("Every attachment is used")
+		return m
+	}
+
+	size := formatSize(attachmentsSize(unused))
+	var items []pickerItem
+	if !readOnly {
+		items = append(items, pickerItem{title: tr("Archive all"), desc: trf("Move them into a zip in %s", backupDir()), value: "archive\n"})
+	}
+	for _, f := range unused {
+		items = append(items, pickerItem{title: f.rel, desc: formatSize(f.size), value: "file\n" + f.rel})
+	}
+	if !readOnly {
+		items = append(items, pickerItem{title: tr("Delete all"), desc: trf("Free %s", size), value: "delete-all\n"})
+	}
+	return m.openPicker("attachments", trf("%d unused attachments (%s)", len(unused), size), items)
+}
+
+// attachmentChosen deletes or archives unused attachments, or offers to
+// when a single file was chosen
+func (m model) attachmentChosen(value string) (tea.Model, tea.Cmd) {
+	action, rel, _ := strings.Cut(value, "\n")
+	if action == "file" {
+		if readOnly {
+			return m, nil
+		}
+		return m.openPicker("attachments", rel, []pickerItem{
+			{title: tr("Delete"), value: "delete\n" + rel},
+			{title: tr("Archive"), desc: trf("Move it into a zip in %s", backupDir()), value: "archive\n" + rel},
+		}), nil
+	}
+	if readOnly {
+		m.status = errReadOnly.Error()
+		return m, nil
+	}
+	if action == "delete-all" {
+		return m.openPrompt("confirm-attachments", tr("Type yes to delete every unused attachment for good"), "")
+	}
+
+	files, err := vaultAttachments()
+	if err != nil {
+		m.status = err.Error()
+		return m, nil
+	}
+	if rel != "" {
+		files = slices.DeleteFunc(files, func(f attachment) bool { return f.rel != rel })
+	}
+	if len(files) == 0 {
+		return m, nil
+	}
+
+	var reclaimed int64
+	if action == "archive" {
+		var path string
+		path, reclaimed, err = archiveAttachments(files)
+		if err == nil {
+			m.status = trf("Archived %d files to %s, freeing %s", len(files), path, formatSize(reclaimed))
+		}
+	} else {
+		reclaimed, err = deleteAttachments(files)
+		if err == nil {
+			m.status = trf("Deleted %d files, freeing %s", len(files), formatSize(reclaimed))
+		}
+	}
+	if err != nil {
+		m.status = err.Error()
+	}
+	return m, nil
+}
+
+// confirmDeleteAttachments deletes every unused attachment when the answer
+// is yes
+func (m model) confirmDeleteAttachments(answer string) (tea.Model, tea.Cmd) {
+	if !answeredYes(answer) {
+		m.status = tr("Nothing deleted")
+		return m, nil
+	}
+	return m.attachmentChosen("delete\n")
+}
+
+// runAttachments implements `gleaner attachments [--delete|--archive]`,
+// listing the attachments no note uses, or deleting or archiving them
+func runAttachments(args []string) error {
+	flags := flag.NewFlagSet("attachments", flag.ContinueOnError)
+	remove := flags.Bool("delete", false, "delete the unused attachments")
+	archive := flags.Bool("archive", false, "move the unused attachments into a zip in the backup directory")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() > 0 || *remove && *archive {
+		return errors.New("usage: gleaner attachments [--delete|--archive]")
+	}
+	if (*remove || *archive) && readOnly {
+		return errReadOnly
+	}
+
+	notes, err := notesForCommand()
+	if err != nil {
+		return err
+	}
+	unused, err := unusedAttachments(notes)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case len(unused) == 0:
+		fmt.Println("Every attachment is used")
+	case *remove:
+		reclaimed, err := deleteAttachments(unused)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Deleted %d files, freeing %s\n", len(unused), formatSize(reclaimed))
+	case *archive:
+		path, reclaimed, err := archiveAttachments(unused)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Archived %d files to %s, freeing %s\n", len(unused), path, formatSize(reclaimed))
+	default:
+		for _, f := range unused {
+			fmt.Printf("%8s  %s\n", formatSize(f.size), f.rel)
+		}
+		fmt.Printf("%d unused attachments, %s\n", len(unused), formatSize(attachmentsSize(unused)))
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestUnusedAttachments(t *testing.T) {
+	oldDir, oldCfg := notesDir, cfg
+	t.Cleanup(func() { notesDir, cfg = oldDir, oldCfg })
+	notesDir, cfg = t.TempDir(), config{BackupDir: t.TempDir()}
+	for rel, content := range map[string]string{
+		"1700000000-Trip.md":            "![](attachments/map.png) ![](assets/my%20photo.jpg)\n",
+		trashDir + "/1700000001-Old.md": "![](attachments/kept.png)\n",
+		"attachments/map.png":           "used",
+		"attachments/kept.png":          "used by a trashed note",
+		"attachments/big.pdf":           "unused and large",
+		"attachments/sub/small.txt":     "unused",
+		"attachments/.DS_Store":         "hidden",
+		"attachments/1700000002-In.md":  "a note kept in the folder",
+		"assets/my photo.jpg":           "used",
+	} {
+		path := filepath.Join(notesDir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	unused, err := vaultAttachments()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var rels []string
+	for _, a := range unused {
+		rels = append(rels, a.rel)
+	}
+	if want := []string{"attachments/big.pdf", "attachments/sub/small.txt"}; !slices.Equal(rels, want) {
+		t.Fatalf("unused attachments %q, want %q", rels, want)
+	}
+	if got := attachmentsSize(unused); got != 22 {
+		t.Errorf("attachmentsSize = %d, want 22", got)
+	}
+
+	path, reclaimed, err := archiveAttachments(unused)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reclaimed != 22 {
+		t.Errorf("reclaimed %d bytes, want 22", reclaimed)
+	}
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer zr.Close()
+	var archived []string
+	for _, f := range zr.File {
+		archived = append(archived, f.Name)
+	}
+	if !slices.Equal(archived, rels) {
+		t.Errorf("archive holds %q, want %q", archived, rels)
+	}
+	for _, a := range unused {
+		if _, err := os.Stat(a.path); !os.IsNotExist(err) {
+			t.Errorf("%s left in the vault: %v", a.rel, err)
+		}
+	}
+
+	cfg.AttachmentsDir = "assets"
+	if unused, err := vaultAttachments(); err != nil || len(unused) != 0 {
+		t.Errorf("with attachments_dir set: %v, %v", unused, err)
+	}
+}
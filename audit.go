@@ -0,0 +1,208 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// File in the notes directory that changes are logged to, one JSON object
+// per line. It lives in the vault so everyone sharing it sees the same log.
+const auditLogFile = ".gleaner-log"
+
+// Actions recorded in the change log
+const (
+	auditCreate  = "create"
+	auditEdit    = "edit"
+	auditDelete  = "delete"
+	auditRestore = "restore"
+	auditRename  = "rename"
+)
+
+// auditEntry is one change in the log
+type auditEntry struct {
+	Time   time.Time `json:"time"`
+	User   string    `json:"user"`
+	Action string    `json:"action"`
+	ID     int64     `json:"id"`
+	Title  string    `json:"title"`
+	Words  int       `json:"words"` // Words added, negative when removed
+}
+
+// Serializes appends to the change log from concurrent saves
+var auditLogMu sync.Mutex
+
+// auditUser names whoever is making changes
+func auditUser() string {
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return u.Username
+	}
+	if name := os.Getenv("USER"); name != "" {
+		return name
+	}
+	return "unknown"
+}
+
+// recordChange appends a change to the note at path to the vault's log.
+// The word delta compares the bodies before and after; logging never fails
+// the change itself.
+func recordChange(action, path, before, after string) {
+	title, id, ok := parseNoteFilename(filepath.Base(path))
+	if !ok {
+		return
+	}
+	_, beforeBody := parseFrontmatter(before)
+	_, afterBody := parseFrontmatter(after)
+	entry := auditEntry{
+		Time:   localNow(),
+		User:   auditUser(),
+		Action: action,
+		ID:     id,
+		Title:  title,
+		Words:  wordCount(afterBody) - wordCount(beforeBody),
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	if action == auditCreate {
+		countCreated()
+	}
+
+	auditLogMu.Lock()
+	defer auditLogMu.Unlock()
+	f, err := os.OpenFile(filepath.Join(notesDir, auditLogFile), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	f.Write(append(data, '\n'))
+}
+
+// loadAuditLog reads the vault's change log, oldest first, skipping lines
+// it cannot parse
+func loadAuditLog() ([]auditEntry, error) {
+	f, err := os.Open(filepath.Join(notesDir, auditLogFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []auditEntry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry auditEntry
+		if json.Unmarshal(scanner.Bytes(), &entry) == nil {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, scanner.Err()
+}
+
+// auditText describes an entry in a sentence
+func auditText(e auditEntry) string {
+	switch e.Action {
+	case auditCreate:
+		return trf("%s created %s", e.User, e.Title)
+	case auditDelete:
+		return trf("%s deleted %s", e.User, e.Title)
+	case auditRestore:
+		return trf("%s restored %s", e.User, e.Title)
+	case auditRename:
+		return trf("%s renamed a note to %s", e.User, e.Title)
+	default:
+		return trf("%s edited %s", e.User, e.Title)
+	}
+}
+
+// wordDelta formats a word delta with its sign
+func wordDelta(words int) string {
+	if words == 1 || words == -1 {
+		return fmt.Sprintf("%+d %s", words, tr("word"))
+	}
+	return fmt.Sprintf("%+d %s", words, tr("words"))
+}
+
+// openAuditLog shows the change log, newest first; choosing an entry
+// selects its note when it still exists
+func (m model) openAuditLog() model {
+	entries, err := loadAuditLog()
+	if err != nil {
+		m.status = err.Error()
+		return m
+	}
+	if len(entries) == 0 {
+		m.status = tr("No changes logged yet")
+		return m
+	}
+	items := make([]pickerItem, len(entries))
+	for i, e := range entries {
+		items[len(entries)-1-i] = pickerItem{
+			title: auditText(e),
+			desc:  formatTime(e.Time) + " · " + wordDelta(e.Words),
+			value: strconv.FormatInt(e.ID, 10),
+		}
+	}
+	return m.openPicker("audit", tr("Change log"), items)
+}
+
+// auditChosen selects the note of the chosen log entry
+func (m model) auditChosen(value string) (tea.Model, tea.Cmd) {
+	id, _ := strconv.ParseInt(value, 10, 64)
+	for _, n := range m.notes {
+		if n.createdAt == id {
+			return m.selectPath(n.path), nil
+		}
+	}
+	m.status = tr("That note no longer exists")
+	return m, nil
+}
+
+// runLog implements `gleaner log [--note title|id]`, printing the change
+// log oldest first
+func runLog(args []string) error {
+	flags := flag.NewFlagSet("log", flag.ContinueOnError)
+	only := flags.String("note", "", "only show changes to the note with this title or id")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() > 0 {
+		return errors.New("usage: gleaner log [--note title|id]")
+	}
+
+	entries, err := loadAuditLog()
+	if err != nil {
+		return err
+	}
+	var id int64
+	if *only != "" {
+		notes, err := notesForCommand()
+		if err != nil {
+			return err
+		}
+		n, err := findNote(notes, *only)
+		if err != nil {
+			return err
+		}
+		id = n.createdAt
+	}
+	for _, e := range entries {
+		if id == 0 || e.ID == id {
+			fmt.Printf("%s  %s  %s  %s  %s (%d)\n", e.Time.Format("2006-01-02 15:04:05"), e.User, e.Action, wordDelta(e.Words), e.Title, e.ID)
+		}
+	}
+	return nil
+}
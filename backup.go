@@ -0,0 +1,272 @@
+package main
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Supported backup archive formats
+const (
+	formatZip   = "zip"
+	formatTarGz = "tar.gz"
+)
+
+// backupMsg reports the outcome of a backup started from the TUI
+type backupMsg struct {
+	path      string
+	err       error
+	scheduled bool     // Snapshot was taken by the backup scheduler
+	secrets   []string // Notes holding credentials, when nothing was written
+}
+
+// backupDir returns the configured backup directory, defaulting to the
+// data directory
+func backupDir() string {
+	if cfg.BackupDir != "" {
+		return expandHome(cfg.BackupDir)
+	}
+	return defaultBackupDir()
+}
+
+// backupFormat returns the configured archive format, defaulting to zip
+func backupFormat() string {
+	if cfg.BackupFormat != "" {
+		return cfg.BackupFormat
+	}
+	return formatZip
+}
+
+// createBackup archives the whole notes directory into a timestamped file in
+// dir. With a password the archive is encrypted before it is written, and
+// gets an .enc suffix.
+func createBackup(dir, format, password string) (string, error) {
+	if format != formatZip && format != formatTarGz {
+		return "", fmt.Errorf("unsupported backup format %q (use %s or %s)", format, formatZip, formatTarGz)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+
+	name := fmt.Sprintf("gleaner-%s.%s", time.Now().Format("20060102-150405"), format)
+	if password != "" {
+		return createEncryptedBackup(filepath.Join(dir, name+encryptedSuffix), dir, format, password)
+	}
+	path := filepath.Join(dir, name)
+	out, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+
+	err = writeArchive(out, dir, format)
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+		return "", err
+	}
+	return path, nil
+}
+
+// createEncryptedBackup builds the archive in memory, so no plaintext copy
+// reaches the disk, and writes it encrypted with the password
+func createEncryptedBackup(path, skipDir, format, password string) (string, error) {
+	var buf bytes.Buffer
+	if err := writeArchive(&buf, skipDir, format); err != nil {
+		return "", err
+	}
+	data, err := encryptArchive(buf.Bytes(), password)
+	if err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		os.Remove(path)
+		return "", err
+	}
+	return path, nil
+}
+
+// writeArchive streams the vault into w in the given format
+func writeArchive(w io.Writer, skipDir, format string) error {
+	if format == formatZip {
+		return writeZip(w, skipDir)
+	}
+	return writeTarGz(w, skipDir)
+}
+
+// walkVault visits every regular file in the notes directory, skipping the
+// backup directory itself when it lives inside the vault
+func walkVault(skipDir string, fn func(path, rel string, info fs.FileInfo) error) error {
+	absSkip, _ := filepath.Abs(skipDir)
+	return filepath.Walk(notesDir, func(path string, info fs.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if abs, _ := filepath.Abs(path); abs == absSkip {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(notesDir, path)
+		if err != nil {
+			return err
+		}
+		return fn(path, filepath.ToSlash(rel), info)
+	})
+}
+
+// writeZip streams the vault into a zip archive
+func writeZip(w io.Writer, skipDir string) error {
+	zw := zip.NewWriter(w)
+	err := walkVault(skipDir, func(path, rel string, info fs.FileInfo) error {
+		header, err := zip.FileInfoHeader(info)
+		if err != nil {
+			return err
+		}
+		header.Name = rel
+		header.Method = zip.Deflate
+		dst, err := zw.CreateHeader(header)
+		if err != nil {
+			return err
+		}
+		return copyFile(dst, path)
+	})
+	if err != nil {
+		zw.Close()
+		return err
+	}
+	return zw.Close()
+}
+
+// writeTarGz streams the vault into a gzip-compressed tarball
+func writeTarGz(w io.Writer, skipDir string) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	err := walkVault(skipDir, func(path, rel string, info fs.FileInfo) error {
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		header.Name = rel
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		return copyFile(tw, path)
+	})
+	if err == nil {
+		err = tw.Close()
+	}
+	if closeErr := gz.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// copyFile copies the contents of the file at path into w
+func copyFile(w io.Writer, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = io.Copy(w, f)
+	return err
+}
+
+// backupVault creates a backup in the background for the TUI. Unless
+// confirmed is set, notes that look like they hold credentials stop it
+// before anything is written, so the TUI can ask first.
+func backupVault(confirmed bool) tea.Cmd {
+	return func() tea.Msg {
+		if !confirmed {
+			notes, _ := scanNotes()
+			if found := notesWithSecrets(notes); len(found) > 0 {
+				return backupMsg{secrets: found}
+			}
+		}
+		path, err := createBackup(backupDir(), backupFormat(), "")
+		return backupMsg{path: path, err: err}
+	}
+}
+
+// runBackup implements the `gleaner backup` command, also run as
+// `gleaner export`. When notes look like they hold credentials, it lists
+// them and asks before writing the archive, unless --yes is given; the
+// daemon's snapshots stay in the backup folder and are not checked.
+func runBackup(args []string) error {
+	flags := flag.NewFlagSet("backup", flag.ContinueOnError)
+	dir := flags.String("out", backupDir(), "directory to write the archive to")
+	format := flags.String("format", backupFormat(), "archive format: zip or tar.gz")
+	daemon := flags.Bool("daemon", false, "keep running and snapshot on an interval")
+	every := flags.Duration("interval", 24*time.Hour, "snapshot interval in daemon mode")
+	prune := flags.Bool("prune", false, "apply the retention policy after backing up")
+	encrypt := flags.Bool("encrypt", false, "protect the archive with a password")
+	yes := flags.Bool("yes", false, "back up without asking when notes look like they contain credentials")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if !*daemon && !*yes {
+		notes, err := notesForCommand()
+		if err != nil {
+			return err
+		}
+		if found := notesWithSecrets(notes); len(found) > 0 {
+			fmt.Println("These notes look like they contain credentials:")
+			for _, f := range found {
+				fmt.Println("  " + f)
+			}
+			if !confirm("Back them up anyway?") {
+				return errors.New("backup cancelled")
+			}
+		}
+	}
+
+	var password string
+	if *encrypt {
+		var err error
+		if password, err = archivePassword(true); err != nil {
+			return err
+		}
+	}
+
+	archiveFormat := strings.TrimPrefix(*format, ".")
+	if *daemon {
+		interval := *every
+		if configured, _ := backupInterval(); configured > 0 && !flagSet(flags, "interval") {
+			interval = configured
+		}
+		return runBackupDaemon(*dir, archiveFormat, password, interval)
+	}
+
+	path, err := createBackup(*dir, archiveFormat, password)
+	if err != nil {
+		return err
+	}
+	fmt.Println("Backup written to", path)
+
+	if *prune {
+		removed, err := pruneBackups(*dir, keepDaily(), keepWeekly())
+		for _, p := range removed {
+			fmt.Println("Pruned", p)
+		}
+		return err
+	}
+	return nil
+}
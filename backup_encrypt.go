@@ -0,0 +1,134 @@
+package main
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/charmbracelet/x/term"
+	"golang.org/x/crypto/pbkdf2"
+)
+
+// Marks a password-protected archive: this header, the PBKDF2 iteration
+// count, a random salt and nonce, then the archive encrypted with AES-256-GCM
+const encryptedArchiveHeader = "gleaner-archive-v1\n"
+
+// Suffix added to the name of a password-protected archive
+const encryptedSuffix = ".enc"
+
+// PBKDF2-HMAC-SHA256 rounds used for new archives, and the most an archive
+// may ask for, so a damaged or hostile header cannot keep a restore busy
+// deriving its key for hours
+const (
+	archiveIterations    = 600000
+	maxArchiveIterations = 10 * archiveIterations
+)
+
+// Salt length in bytes
+const archiveSaltSize = 16
+
+// archiveCipher returns AES-256-GCM keyed from the password and salt
+func archiveCipher(password string, salt []byte, iterations int) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(pbkdf2.Key([]byte(password), salt, iterations, 32, sha256.New))
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// encryptArchive protects archive data with a password
+func encryptArchive(data []byte, password string) ([]byte, error) {
+	salt := make([]byte, archiveSaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	gcm, err := archiveCipher(password, salt, archiveIterations)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	header := append([]byte(encryptedArchiveHeader), binary.BigEndian.AppendUint32(nil, archiveIterations)...)
+	header = append(header, salt...)
+	out := append(bytes.Clone(header), nonce...)
+	return gcm.Seal(out, nonce, data, header), nil
+}
+
+// decryptArchive opens an archive protected with encryptArchive
+func decryptArchive(data []byte, password string) ([]byte, error) {
+	rest, ok := bytes.CutPrefix(data, []byte(encryptedArchiveHeader))
+	if !ok || len(rest) < 4+archiveSaltSize {
+		return nil, errors.New("not a gleaner encrypted archive")
+	}
+	iterations := int(binary.BigEndian.Uint32(rest))
+	if iterations < 1 || iterations > maxArchiveIterations {
+		return nil, fmt.Errorf("encrypted archive asks for %d key rounds, outside 1 to %d", iterations, maxArchiveIterations)
+	}
+	salt := rest[4 : 4+archiveSaltSize]
+	header := data[:len(encryptedArchiveHeader)+4+archiveSaltSize]
+	rest = rest[4+archiveSaltSize:]
+
+	gcm, err := archiveCipher(password, salt, iterations)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) < gcm.NonceSize() {
+		return nil, errors.New("encrypted archive is truncated")
+	}
+	plain, err := gcm.Open(nil, rest[:gcm.NonceSize()], rest[gcm.NonceSize():], header)
+	if err != nil {
+		return nil, errors.New("wrong password, or the archive is damaged")
+	}
+	return plain, nil
+}
+
+// isEncryptedArchive reports whether file data is a password-protected archive
+func isEncryptedArchive(data []byte) bool {
+	return bytes.HasPrefix(data, []byte(encryptedArchiveHeader))
+}
+
+// archivePassword returns the password from GLEANER_EXPORT_PASSWORD, or asks
+// for it on the terminal; twice when confirm is set, for new archives
+func archivePassword(confirm bool) (string, error) {
+	if env := os.Getenv("GLEANER_EXPORT_PASSWORD"); env != "" {
+		return env, nil
+	}
+	if !term.IsTerminal(os.Stdin.Fd()) {
+		return "", errors.New("no terminal to ask for the archive password: set GLEANER_EXPORT_PASSWORD")
+	}
+
+	password, err := readPassword("Archive password: ")
+	if err != nil {
+		return "", err
+	}
+	if password == "" {
+		return "", errors.New("the archive password cannot be empty")
+	}
+	if confirm {
+		again, err := readPassword("Repeat password: ")
+		if err != nil {
+			return "", err
+		}
+		if again != password {
+			return "", errors.New("the passwords do not match")
+		}
+	}
+	return password, nil
+}
+
+// readPassword asks for a password without echoing it
+func readPassword(label string) (string, error) {
+	fmt.Fprint(os.Stderr, label)
+	password, err := term.ReadPassword(os.Stdin.Fd())
+	fmt.Fprintln(os.Stderr)
+	return string(password), err
+}
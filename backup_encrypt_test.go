@@ -0,0 +1,93 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestDecryptArchive(t *testing.T) {
+	plain := []byte("zip archive contents")
+	sealed, err := encryptArchive(plain, "correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	header := len(encryptedArchiveHeader)
+	withRounds := func(rounds uint32) []byte {
+		data := bytes.Clone(sealed)
+		binary.BigEndian.PutUint32(data[header:], rounds)
+		return data
+	}
+	tampered := bytes.Clone(sealed)
+	tampered[len(tampered)-1] ^= 1
+
+	tests := []struct {
+		name     string
+		data     []byte
+		password string
+		wantErr  bool
+	}{
+		{"right password", sealed, "correct horse", false},
+		{"wrong password", sealed, "battery staple", true},
+		{"changed data", tampered, "correct horse", true},
+		{"not encrypted", plain, "correct horse", true},
+		{"cut short", sealed[:header+2], "correct horse", true},
+		{"no key rounds", withRounds(0), "correct horse", true},
+		{"too many key rounds", withRounds(maxArchiveIterations + 1), "correct horse", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decryptArchive(tt.data, tt.password)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("decryptArchive error = %v, want error %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !bytes.Equal(got, plain) {
+				t.Errorf("decryptArchive = %q, want %q", got, plain)
+			}
+		})
+	}
+}
+
+func TestEncryptedBackupRoundTrip(t *testing.T) {
+	oldDir := notesDir
+	t.Cleanup(func() { notesDir = oldDir })
+	notesDir = t.TempDir()
+	if err := os.WriteFile(filepath.Join(notesDir, "1700000000-Private.md"), []byte("secret\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	path, err := createBackup(t.TempDir(), formatTarGz, "correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(path, "."+formatTarGz+encryptedSuffix) {
+		t.Errorf("encrypted backup named %s", filepath.Base(path))
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !isEncryptedArchive(data) || bytes.Contains(data, []byte("Private")) {
+		t.Error("backup written in the clear")
+	}
+	if info, _ := os.Stat(path); runtime.GOOS != "windows" && info.Mode().Perm() != 0600 {
+		t.Errorf("backup readable by others: %v", info.Mode().Perm())
+	}
+
+	t.Setenv("GLEANER_EXPORT_PASSWORD", "correct horse")
+	files, err := readArchive(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(files["1700000000-Private.md"]); got != "secret\n" || len(files) != 1 {
+		t.Errorf("archive holds %v", files)
+	}
+	t.Setenv("GLEANER_EXPORT_PASSWORD", "battery staple")
+	if _, err := readArchive(path); err == nil {
+		t.Error("archive opened with the wrong password")
+	}
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Retention defaults used when the config leaves them unset
+const (
+	defaultKeepDaily  = 7
+	defaultKeepWeekly = 4
+)
+
+// backupTickMsg fires when the next scheduled backup is due
+type backupTickMsg struct{}
+
+// backupArchive is a backup file found in the backup directory
+type backupArchive struct {
+	path    string
+	takenAt time.Time
+}
+
+// backupInterval returns the configured snapshot interval, or zero when disabled
+func backupInterval() (time.Duration, error) {
+	if cfg.BackupInterval == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(cfg.BackupInterval)
+	if err != nil {
+		return 0, fmt.Errorf("invalid backup_interval %q: %w", cfg.BackupInterval, err)
+	}
+	return d, nil
+}
+
+// scheduleBackup waits for the interval before asking for the next snapshot
+func scheduleBackup(interval time.Duration) tea.Cmd {
+	if interval <= 0 {
+		return nil
+	}
+	return tea.Tick(interval, func(time.Time) tea.Msg { return backupTickMsg{} })
+}
+
+// scheduledBackup snapshots the vault and applies the retention policy
+func scheduledBackup() tea.Msg {
+	dir := backupDir()
+	slog.Debug("scheduled backup", "dir", dir)
+	path, err := createBackup(dir, backupFormat(), "")
+	if err == nil {
+		var removed []string
+		removed, err = pruneBackups(dir, keepDaily(), keepWeekly())
+		for _, p := range removed {
+			slog.Debug("pruned backup", "path", p)
+		}
+	}
+	return backupMsg{path: path, err: err, scheduled: true}
+}
+
+// keepDaily returns how many daily snapshots to retain
+func keepDaily() int {
+	if cfg.BackupKeepDaily > 0 {
+		return cfg.BackupKeepDaily
+	}
+	return defaultKeepDaily
+}
+
+// keepWeekly returns how many weekly snapshots to retain
+func keepWeekly() int {
+	if cfg.BackupKeepWeekly > 0 {
+		return cfg.BackupKeepWeekly
+	}
+	return defaultKeepWeekly
+}
+
+// listBackups returns the archives in dir, newest first
+func listBackups(dir string) ([]backupArchive, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var archives []backupArchive
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasPrefix(name, "gleaner-") {
+			continue
+		}
+		stamp := strings.TrimSuffix(strings.TrimPrefix(name, "gleaner-"), encryptedSuffix)
+		stamp = strings.TrimSuffix(strings.TrimSuffix(stamp, "."+formatTarGz), "."+formatZip)
+		takenAt, err := time.ParseInLocation("20060102-150405", stamp, time.Local)
+		if err != nil {
+			continue
+		}
+		archives = append(archives, backupArchive{path: filepath.Join(dir, name), takenAt: takenAt})
+	}
+
+	sort.Slice(archives, func(i, j int) bool {
+		return archives[i].takenAt.After(archives[j].takenAt)
+	})
+	return archives, nil
+}
+
+// pruneBackups keeps the newest archive of each of the last `daily` days and
+// `weekly` weeks, deleting everything else. It returns the removed paths.
+func pruneBackups(dir string, daily, weekly int) ([]string, error) {
+	archives, err := listBackups(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	keep := make(map[string]bool)
+	days := make(map[string]bool)
+	weeks := make(map[string]bool)
+	for _, a := range archives {
+		day := a.takenAt.Format("2006-01-02")
+		if !days[day] && len(days) < daily {
+			days[day] = true
+			keep[a.path] = true
+		}
+		year, week := a.takenAt.ISOWeek()
+		weekKey := fmt.Sprintf("%d-%02d", year, week)
+		if !weeks[weekKey] && len(weeks) < weekly {
+			weeks[weekKey] = true
+			keep[a.path] = true
+		}
+	}
+
+	var removed []string
+	for _, a := range archives {
+		if keep[a.path] {
+			continue
+		}
+		if err := os.Remove(a.path); err != nil {
+			return removed, err
+		}
+		removed = append(removed, a.path)
+	}
+	return removed, nil
+}
+
+// runBackupDaemon snapshots the vault on an interval until interrupted,
+// encrypting each archive when a password is given
+func runBackupDaemon(dir, format, password string, interval time.Duration) error {
+	if interval <= 0 {
+		return fmt.Errorf("backup interval must be positive")
+	}
+	for {
+		path, err := createBackup(dir, format, password)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Backup failed: %v\n", err)
+		} else {
+			fmt.Println("Backup written to", path)
+			removed, err := pruneBackups(dir, keepDaily(), keepWeekly())
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Pruning failed: %v\n", err)
+			}
+			for _, p := range removed {
+				fmt.Println("Pruned", p)
+			}
+		}
+		time.Sleep(interval)
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestPruneBackups(t *testing.T) {
+	dir := t.TempDir()
+	names := []string{
+		"gleaner-20240301-120000.zip",        // Week 9
+		"gleaner-20240308-120000.zip",        // Week 10
+		"gleaner-20240313-120000.zip",        // Week 11 from here on
+		"gleaner-20240314-120000.tar.gz.enc", // Second day kept
+		"gleaner-20240315-090000.zip",
+		"gleaner-20240315-180000.zip", // Newest of the newest day
+		"gleaner-latest.zip",
+		"notes.txt",
+	}
+	for _, name := range names {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	archives, err := listBackups(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var listed []string
+	for _, a := range archives {
+		listed = append(listed, filepath.Base(a.path))
+	}
+	if want := []string{names[5], names[4], names[3], names[2], names[1], names[0]}; !slices.Equal(listed, want) {
+		t.Errorf("listBackups = %v, want %v", listed, want)
+	}
+
+	removed, err := pruneBackups(dir, 2, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var gone []string
+	for _, path := range removed {
+		gone = append(gone, filepath.Base(path))
+	}
+	slices.Sort(gone)
+	if want := []string{names[0], names[2], names[4]}; !slices.Equal(gone, want) {
+		t.Errorf("pruned %v, want %v", gone, want)
+	}
+	left, _ := filepath.Glob(filepath.Join(dir, "*"))
+	if len(left) != len(names)-3 {
+		t.Errorf("%d files left, want %d: %v", len(left), len(names)-3, left)
+	}
+}
+
+func TestBackupInterval(t *testing.T) {
+	oldCfg := cfg
+	t.Cleanup(func() { cfg = oldCfg })
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{"", "0s", false},
+		{"6h", "6h0m0s", false},
+		{"daily", "0s", true},
+	}
+	for _, tt := range tests {
+		cfg.BackupInterval = tt.in
+		got, err := backupInterval()
+		if (err != nil) != tt.wantErr || got.String() != tt.want {
+			t.Errorf("backupInterval(%q) = %v, %v", tt.in, got, err)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"archive/zip"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCreateBackup(t *testing.T) {
+	oldDir := notesDir
+	t.Cleanup(func() { notesDir = oldDir })
+	notesDir = t.TempDir()
+	files := map[string]string{
+		"1700000000-Plan.md":          "plan\n",
+		"projects/1700000001-Site.md": "site\n",
+		"attachments/logo.png":        "png",
+	}
+	for rel, content := range files {
+		path := filepath.Join(notesDir, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Backups kept inside the vault are left out of the next one
+	dir := filepath.Join(notesDir, "backups")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "gleaner-20240101-000000.zip"), []byte("old"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	path, err := createBackup(dir, formatZip, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filepath.Dir(path) != dir || !strings.HasPrefix(filepath.Base(path), "gleaner-") || !strings.HasSuffix(path, ".zip") {
+		t.Errorf("backup written to %s", path)
+	}
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer zr.Close()
+	got := map[string]string{}
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		got[f.Name] = string(data)
+	}
+	if !maps.Equal(got, files) {
+		t.Errorf("archive holds %v, want %v", got, files)
+	}
+
+	if _, err := createBackup(dir, "rar", ""); err == nil || !strings.Contains(err.Error(), "unsupported backup format") {
+		t.Errorf("rar backup: %v", err)
+	}
+}
@@ -0,0 +1,275 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// tagEdit is a change to one tag made across many notes at once: adding
+// it, removing it or renaming it
+type tagEdit struct {
+	op  string // "add", "remove" or "rename"
+	tag string
+	to  string // New name of a renamed tag
+}
+
+// parseTagEdit reads a tag change written as +tag to add a tag, -tag to
+// remove one and old>new to rename one
+func parseTagEdit(s string) (tagEdit, error) {
+	clean := func(tag string) string {
+		return strings.TrimPrefix(strings.TrimSpace(tag), "#")
+	}
+	s = strings.TrimSpace(s)
+	var e tagEdit
+	switch {
+	case strings.Contains(s, ">"):
+		old, to, _ := strings.Cut(s, ">")
+		e = tagEdit{op: "rename", tag: clean(old), to: clean(to)}
+	case strings.HasPrefix(s, "+"):
+		e = tagEdit{op: "add", tag: clean(s[1:])}
+	case strings.HasPrefix(s, "-"):
+		e = tagEdit{op: "remove", tag: clean(s[1:])}
+	default:
+		return e, errors.New("write the change as +tag, -tag or old>new")
+	}
+	for _, tag := range []string{e.tag, e.to} {
+		if strings.ContainsAny(tag, " \t,[]") {
+			return e, fmt.Errorf("%q is not a tag", tag)
+		}
+	}
+	if e.tag == "" || (e.op == "rename" && e.to == "") {
+		return e, errors.New("write the change as +tag, -tag or old>new")
+	}
+	return e, nil
+}
+
+// String describes the change for the summary
+func (e tagEdit) String() string {
+	switch e.op {
+	case "add":
+		return "+#" + e.tag
+	case "remove":
+		return "-#" + e.tag
+	default:
+		return "#" + e.tag + " → #" + e.to
+	}
+}
+
+// apply returns a note's tags after the change, and whether they changed.
+// Tags are compared ignoring case, as the tag filter does.
+func (e tagEdit) apply(tags []string) ([]string, bool) {
+	same := func(t string) bool { return strings.EqualFold(t, e.tag) }
+	has := slices.ContainsFunc(tags, same)
+	switch {
+	case e.op == "add" && !has:
+		return append(slices.Clone(tags), e.tag), true
+	case e.op == "remove" && has:
+		return slices.DeleteFunc(slices.Clone(tags), same), true
+	case e.op == "rename" && has:
+		var renamed []string
+		for _, t := range tags {
+			if same(t) {
+				t = e.to
+			}
+			if !slices.Contains(renamed, t) {
+				renamed = append(renamed, t)
+			}
+		}
+		return renamed, !slices.Equal(renamed, tags)
+	}
+	return tags, false
+}
+
+// tagEditNotes returns the notes among the given ones the change would alter
+func tagEditNotes(notes []note, e tagEdit) []note {
+	var changed []note
+	for _, n := range notes {
+		if _, ok := e.apply(n.tags); ok {
+			changed = append(changed, n)
+		}
+	}
+	return changed
+}
+
+// writeTags sets the tags field of the note at path, removing it when there
+// are no tags left
+func writeTags(path string, tags []string) error {
+	if len(tags) == 0 {
+		return writeField(path, "tags", "")
+	}
+	return writeField(path, "tags", "["+strings.Join(tags, ", ")+"]")
+}
+
+// applyTagEdit makes the change to the notes, reading each one's tags
+// again first, and returns how many notes were changed
+func applyTagEdit(notes []note, e tagEdit) (int, error) {
+	changed := 0
+	var errs []error
+	for _, n := range notes {
+		content, err := readNoteFile(n.path)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		fields, _ := parseFrontmatter(string(content))
+		tags, ok := e.apply(metaList(metaValue(fields, "tags")))
+		if !ok {
+			continue
+		}
+		if err := writeTags(n.path, tags); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		changed++
+	}
+	return changed, errors.Join(errs...)
+}
+
+// startTagEdit asks for the tag change to make across notes
+func (m model) startTagEdit() (model, tea.Cmd) {
+	return m.openPrompt("tag-edit", tr("Tag change (+tag adds, -tag removes, old>new renames)"), "")
+}
+
+// tagEditPrompted offers the notes to make the tag change to: all notes, the
+// notes the list shows or the selected note, with how many each would change
+func (m model) tagEditPrompted(answer string) (tea.Model, tea.Cmd) {
+	e, err := parseTagEdit(answer)
+	if err != nil {
+		m.status = err.Error()
+		return m, nil
+	}
+	m.tagEdit = &e
+	all, err := scanNotes()
+	if err != nil && len(all) == 0 {
+		m.tagEdit = nil
+		return m.notify(err.Error(), err)
+	}
+
+	var listed []note
+	for _, item := range m.list.VisibleItems() {
+		listed = append(listed, item.(note))
+	}
+	items := []pickerItem{
+		{title: trf("All %d notes", len(all)), desc: trf("Changes %d notes", len(tagEditNotes(all, e))), value: "all"},
+		{title: trf("%d notes in the list", len(listed)), desc: trf("Changes %d notes", len(tagEditNotes(listed, e))), value: "list"},
+	}
+	if m.selectedNote != nil {
+		items = append(items, pickerItem{title: trf("Selected note: %s", m.selectedNote.title), desc: trf("Changes %d notes", len(tagEditNotes([]note{*m.selectedNote}, e))), value: "selected"})
+	}
+	return m.openPicker("tag-scope", trf("Apply %s to", e), items), nil
+}
+
+// tagScopeChosen shows the notes the tag change would alter and their tags
+// before and after, to confirm before any file is written
+func (m model) tagScopeChosen(scope string) (tea.Model, tea.Cmd) {
+	if m.tagEdit == nil {
+		return m, nil
+	}
+	var notes []note
+	switch scope {
+	case "all":
+		// Every note in the vault, whatever the list is filtered to
+		all, err := scanNotes()
+		if err != nil && len(all) == 0 {
+			m.tagEdit = nil
+			return m.notify(err.Error(), err)
+		}
+		notes = all
+	case "list":
+		for _, item := range m.list.VisibleItems() {
+			notes = append(notes, item.(note))
+		}
+	case "selected":
+		if m.selectedNote != nil {
+			notes = []note{*m.selectedNote}
+		}
+	}
+	m.tagNotes = tagEditNotes(notes, *m.tagEdit)
+	if len(m.tagNotes) == 0 {
+		m.status = trf("%s changes none of these notes", m.tagEdit)
+		m.tagEdit = nil
+		return m, nil
+	}
+
+	items := []pickerItem{{title: trf("Change %d notes", len(m.tagNotes)), desc: m.tagEdit.String(), value: "apply"}}
+	for _, n := range m.tagNotes {
+		after, _ := m.tagEdit.apply(n.tags)
+		items = append(items, pickerItem{title: n.title, desc: tagsText(n.tags) + " → " + tagsText(after)})
+	}
+	return m.openPicker("tag-confirm", trf("Apply %s to", m.tagEdit), items), nil
+}
+
+// tagsText shows tags as #a #b, or a dash when there are none
+func tagsText(tags []string) string {
+	if len(tags) == 0 {
+		return "–"
+	}
+	return "#" + strings.Join(tags, " #")
+}
+
+// tagEditConfirmed writes the tag change to the notes shown
+func (m model) tagEditConfirmed(value string) (tea.Model, tea.Cmd) {
+	e, notes := m.tagEdit, m.tagNotes
+	m.tagEdit, m.tagNotes = nil, nil
+	if value != "apply" || e == nil {
+		m.status = tr("No tags changed")
+		return m, nil
+	}
+	changed, err := applyTagEdit(notes, *e)
+	next, cmd := m.notify(trf("%s in %d notes", e, changed), err)
+	return next, tea.Batch(cmd, loadNotes)
+}
+
+// runTag implements `gleaner tag <+tag|-tag|old>new>`, adding, removing or
+// renaming a tag in every note, or in those --tag or --filter pick out. It
+// lists the notes it changes, and with --dry-run changes nothing.
+func runTag(args []string) error {
+	flags := flag.NewFlagSet("tag", flag.ContinueOnError)
+	dryRun := flags.Bool("dry-run", false, "list the notes that would change without writing them")
+	withTagFlag := flags.String("tag", "", "only change notes with this tag")
+	filter := flags.String("filter", "", "only change notes whose title, tags or text contain this")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return errors.New("usage: gleaner tag [--dry-run] [--tag <tag>] [--filter <text>] <+tag|-tag|old>new>")
+	}
+	e, err := parseTagEdit(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	if readOnly && !*dryRun {
+		return errReadOnly
+	}
+
+	notes, err := notesForCommand()
+	if err != nil {
+		return err
+	}
+	notes = withTag(notes, *withTagFlag)
+	if *filter != "" {
+		query := strings.ToLower(*filter)
+		notes = slices.DeleteFunc(notes, func(n note) bool {
+			text := n.title + "\n" + strings.Join(n.tags, " ") + "\n" + n.body
+			return !strings.Contains(strings.ToLower(text), query)
+		})
+	}
+
+	notes = tagEditNotes(notes, e)
+	for _, n := range notes {
+		after, _ := e.apply(n.tags)
+		fmt.Printf("%s: %s → %s\n", n.title, tagsText(n.tags), tagsText(after))
+	}
+	if *dryRun {
+		fmt.Printf("%s would change %d notes\n", e, len(notes))
+		return nil
+	}
+	changed, err := applyTagEdit(notes, e)
+	fmt.Printf("%s in %d notes\n", e, changed)
+	return err
+}
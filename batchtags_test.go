@@ -0,0 +1,107 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestParseTagEdit(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    tagEdit
+		wantErr bool
+	}{
+		{"+work", tagEdit{op: "add", tag: "work"}, false},
+		{"  -#old ", tagEdit{op: "remove", tag: "old"}, false},
+		{"todo>done", tagEdit{op: "rename", tag: "todo", to: "done"}, false},
+		{"#todo > #done", tagEdit{op: "rename", tag: "todo", to: "done"}, false},
+		{"work", tagEdit{}, true},
+		{"+", tagEdit{}, true},
+		{"todo>", tagEdit{}, true},
+		{"+two words", tagEdit{}, true},
+		{"+a,b", tagEdit{}, true},
+	}
+	for _, tt := range tests {
+		got, err := parseTagEdit(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseTagEdit(%q) error = %v, want error %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && got != tt.want {
+			t.Errorf("parseTagEdit(%q) = %+v, want %+v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestTagEditApply(t *testing.T) {
+	tests := []struct {
+		name    string
+		edit    tagEdit
+		tags    []string
+		want    []string
+		changed bool
+	}{
+		{"add", tagEdit{op: "add", tag: "work"}, []string{"home"}, []string{"home", "work"}, true},
+		{"add present in another case", tagEdit{op: "add", tag: "work"}, []string{"Work"}, []string{"Work"}, false},
+		{"add to none", tagEdit{op: "add", tag: "work"}, nil, []string{"work"}, true},
+		{"remove", tagEdit{op: "remove", tag: "old"}, []string{"a", "OLD", "b"}, []string{"a", "b"}, true},
+		{"remove absent", tagEdit{op: "remove", tag: "old"}, []string{"a"}, []string{"a"}, false},
+		{"rename", tagEdit{op: "rename", tag: "todo", to: "done"}, []string{"x", "todo"}, []string{"x", "done"}, true},
+		{"rename onto a tag already there", tagEdit{op: "rename", tag: "todo", to: "done"}, []string{"done", "todo"}, []string{"done"}, true},
+		{"rename absent", tagEdit{op: "rename", tag: "todo", to: "done"}, []string{"x"}, []string{"x"}, false},
+		{"rename case only", tagEdit{op: "rename", tag: "todo", to: "todo"}, []string{"todo"}, []string{"todo"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := slices.Clone(tt.tags)
+			got, changed := tt.edit.apply(tt.tags)
+			if !slices.Equal(got, tt.want) || changed != tt.changed {
+				t.Errorf("apply(%v) = %v, %v; want %v, %v", tt.tags, got, changed, tt.want, tt.changed)
+			}
+			if !slices.Equal(tt.tags, before) {
+				t.Errorf("apply changed the tags it was given to %v", tt.tags)
+			}
+		})
+	}
+}
+
+func TestApplyTagEdit(t *testing.T) {
+	oldDir, oldCfg := notesDir, cfg
+	t.Cleanup(func() { notesDir, cfg = oldDir, oldCfg })
+	notesDir, cfg = t.TempDir(), config{}
+	files := map[string]string{
+		"1700000000-Chores.md": "---\ntags: [todo, home]\n---\nbody\n",
+		"1700000001-Call.md":   "---\ntags: [todo]\n---\nbody\n",
+		"1700000002-Idea.md":   "no tags\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(notesDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	notes, err := scanNotes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	edit := tagEdit{op: "remove", tag: "todo"}
+	if got := tagEditNotes(notes, edit); len(got) != 2 {
+		t.Errorf("change would alter %d notes, want 2", len(got))
+	}
+
+	changed, err := applyTagEdit(notes, edit)
+	if changed != 2 || err != nil {
+		t.Errorf("applyTagEdit changed %d notes, %v; want 2", changed, err)
+	}
+	want := map[string]string{
+		"1700000000-Chores.md": "---\ntags: [home]\n---\nbody\n",
+		"1700000001-Call.md":   "body\n",
+		"1700000002-Idea.md":   "no tags\n",
+	}
+	for name, content := range want {
+		if data, _ := os.ReadFile(filepath.Join(notesDir, name)); string(data) != content {
+			t.Errorf("%s is %q, want %q", name, data, content)
+		}
+	}
+}
@@ -0,0 +1,210 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Words synthetic notes are made of
+var benchWords = strings.Fields(`the a of and to in is it that for on with as was at by
+	project meeting idea draft review budget garden recipe travel book notes plan
+	release server client database cache query index search render latency vault
+	monday friday morning evening quickly slowly carefully later soon today`)
+
+// Terms searched for, from common to absent
+var benchTerms = []string{"plan", "database cache", "zzz"}
+
+// benchTiming is the measured latencies of one operation
+type benchTiming struct {
+	name  string
+	times []time.Duration
+}
+
+// benchNote writes a synthetic note: a few tags, paragraphs of random words,
+// a task list and a link to one of the notes written before it
+func benchNote(rng *rand.Rand, earlier []string, words int) (string, string) {
+	pick := func() string { return benchWords[rng.IntN(len(benchWords))] }
+	title := fmt.Sprintf("%s %s %d", pick(), pick(), len(earlier))
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "---\ntags: %s, %s\n---\n\n# %s\n\n", pick(), pick(), title)
+	for w := 0; w < words; w++ {
+		b.WriteString(pick())
+		switch {
+		case w%80 == 79:
+			b.WriteString("\n\n")
+		case w%12 == 11:
+			b.WriteString(".\n")
+		default:
+			b.WriteString(" ")
+		}
+	}
+	b.WriteString("\n\n- [ ] follow up\n- [x] write it down\n")
+	if len(earlier) > 0 {
+		fmt.Fprintf(&b, "\nSee [[%s]].\n", earlier[rng.IntN(len(earlier))])
+	}
+	return title, b.String()
+}
+
+// measure runs an operation the given number of times, returning its timings
+func measure(name string, runs int, op func() error) (benchTiming, error) {
+	t := benchTiming{name: name}
+	for range runs {
+		start := time.Now()
+		if err := op(); err != nil {
+			return t, fmt.Errorf("%s: %w", name, err)
+		}
+		t.times = append(t.times, time.Since(start))
+	}
+	slices.Sort(t.times)
+	return t, nil
+}
+
+// runBench implements `gleaner bench [--notes N] [--words N] [--runs N]
+// [--keep]`, generating a vault of synthetic notes and timing how long
+// loading, searching, rendering and saving take on it. The vault and the
+// configuration are throwaway ones, so hooks, plugins and the word log of
+// real vaults are left alone.
+func runBench(args []string) error {
+	flags := flag.NewFlagSet("bench", flag.ContinueOnError)
+	count := flags.Int("notes", 1000, "number of notes to generate")
+	words := flags.Int("words", 300, "words in each note")
+	runs := flags.Int("runs", 10, "times to repeat each measurement")
+	keep := flags.Bool("keep", false, "keep the generated vault instead of removing it")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() > 0 || *count < 1 || *words < 1 || *runs < 1 {
+		return errors.New("usage: gleaner bench [--notes N] [--words N] [--runs N] [--keep]")
+	}
+
+	root, err := os.MkdirTemp("", "gleaner-bench-")
+	if err != nil {
+		return err
+	}
+	if *keep {
+		defer fmt.Printf("Vault kept in %s\n", root)
+	} else {
+		defer os.RemoveAll(root)
+	}
+	notesDir = filepath.Join(root, "notes")
+	// State such as the word log goes under the temporary folder too
+	portableDir = ""
+	os.Setenv("XDG_CONFIG_HOME", filepath.Join(root, "config"))
+	os.Setenv("APPDATA", filepath.Join(root, "config"))
+	os.Setenv("HOME", root)
+	os.Setenv("USERPROFILE", root)
+	cfg = config{}
+	readOnly = false
+	if err := os.MkdirAll(notesDir, 0755); err != nil {
+		return err
+	}
+
+	rng := rand.New(rand.NewPCG(1, 2))
+	start := time.Now()
+	created := time.Now().Add(-time.Duration(*count) * time.Hour).Unix()
+	var titles []string
+	for i := range *count {
+		title, content := benchNote(rng, titles, *words)
+		titles = append(titles, title)
+		path := filepath.Join(notesDir, fmt.Sprintf("%d-%s.md", created+int64(i)*3600, sanitizeFileName(title)))
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			return err
+		}
+	}
+	fmt.Printf("Generated %d notes of %d words in %s\n\n", *count, *words, time.Since(start).Round(time.Millisecond))
+
+	var m tea.Model = initialModel()
+	m, _ = m.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	var notes []note
+	var timings []benchTiming
+	add := func(t benchTiming, err error) error {
+		timings = append(timings, t)
+		return err
+	}
+
+	// Loading reads every note and fills the list, as a refresh does
+	err = add(measure("load", *runs, func() error {
+		var err error
+		if notes, err = scanNotes(); err != nil {
+			return err
+		}
+		m, _ = m.Update(notes)
+		return nil
+	}))
+	if err != nil {
+		return err
+	}
+
+	// Searching filters the list in each scope it can search
+	for _, scope := range []int{scopeTitle, scopeContent} {
+		searchScope = scope
+		targets := make([]string, len(notes))
+		for i, n := range notes {
+			targets[i] = n.FilterValue()
+		}
+		err = add(measure("search "+scopeNames[scope], *runs, func() error {
+			for _, term := range benchTerms {
+				scopeFilter(term, targets)
+			}
+			return nil
+		}))
+		if err != nil {
+			return err
+		}
+	}
+	searchScope = scopeTitle
+
+	// Rendering draws the screen after moving through the list
+	err = add(measure("render", *runs, func() error {
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+		m.View()
+		return nil
+	}))
+	if err != nil {
+		return err
+	}
+
+	// Saving writes a note and reloads the list, as ctrl+s does
+	err = add(measure("save", *runs, func() error {
+		n := notes[rng.IntN(len(notes))]
+		content, err := readNoteFile(n.path)
+		if err != nil {
+			return err
+		}
+		msg := saveNote(n.title, string(content)+"\nMore text.\n", &n)()
+		switch msg := msg.(type) {
+		case hookErrMsg:
+			return msg.err
+		case errMsg:
+			return msg.err
+		case notesErrMsg:
+			return msg.err
+		case []note:
+			notes = msg
+		}
+		return nil
+	}))
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%-16s %10s %10s %10s\n", "operation", "min", "median", "max")
+	for _, t := range timings {
+		fmt.Printf("%-16s %10s %10s %10s\n", t.name,
+			t.times[0].Round(time.Microsecond),
+			t.times[len(t.times)/2].Round(time.Microsecond),
+			t.times[len(t.times)-1].Round(time.Microsecond))
+	}
+	fmt.Printf("\nSearch times cover %d terms; save times include reloading the list\n", len(benchTerms))
+	return nil
+}
@@ -0,0 +1,346 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"html"
+	"image"
+	"image/color"
+	_ "image/gif" // Favicon formats
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Note type, set with "type: bookmark" in the frontmatter, for notes that
+// save a web page; its address is in the "url" field
+const noteTypeBookmark = "bookmark"
+
+// Icon of bookmarks whose favicon could not be read
+const defaultBookmarkIcon = "🔖"
+
+// Most of a page or favicon that is read when fetching a bookmark
+const bookmarkReadLimit = 1 << 20
+
+// Largest favicon, in pixels each way, that is decoded; a small file can
+// declare a huge image, and decoding it would take as much memory
+const faviconMaxSize = 256
+
+// Badge showing a bookmark's site in the list
+var bookmarkBadge = badgeStyle.Foreground(lipgloss.Color("250")).Background(lipgloss.Color("238"))
+
+// Patterns for the parts of a page a bookmark keeps
+var (
+	htmlTitlePattern = regexp.MustCompile(`(?is)<title[^>]*>(.*?)</title>`)
+	htmlMetaPattern  = regexp.MustCompile(`(?is)<meta\s[^>]*>`)
+	htmlLinkPattern  = regexp.MustCompile(`(?is)<link\s[^>]*>`)
+	htmlAttrPattern  = regexp.MustCompile(`(?is)([a-z][a-z:-]*)\s*=\s*(?:"([^"]*)"|'([^']*)')`)
+)
+
+// bookmarkMsg reports a saved bookmark back to the TUI
+type bookmarkMsg struct {
+	title string
+	err   error // Why the page details are missing, or why nothing was saved
+	saved bool
+}
+
+// pageDetails is what a bookmark records about a page
+type pageDetails struct {
+	title       string
+	description string
+	icon        string
+}
+
+// htmlAttrs returns the attributes of an HTML tag with lower-case names
+func htmlAttrs(tag string) map[string]string {
+	attrs := map[string]string{}
+	for _, m := range htmlAttrPattern.FindAllStringSubmatch(tag, -1) {
+		attrs[strings.ToLower(m[1])] = html.UnescapeString(m[2] + m[3])
+	}
+	return attrs
+}
+
+// parsePage finds the title, description and favicon address of a page
+func parsePage(page string, base *url.URL) (title, description string, icon *url.URL) {
+	if m := htmlTitlePattern.FindStringSubmatch(page); m != nil {
+		title = strings.Join(strings.Fields(html.UnescapeString(m[1])), " ")
+	}
+	for _, tag := range htmlMetaPattern.FindAllString(page, -1) {
+		attrs := htmlAttrs(tag)
+		name := strings.ToLower(attrs["name"] + attrs["property"])
+		switch {
+		case name == "og:title" && title == "":
+			title = attrs["content"]
+		case (name == "description" || name == "og:description") && description == "":
+			description = strings.Join(strings.Fields(attrs["content"]), " ")
+		}
+	}
+
+	icon = base.ResolveReference(&url.URL{Path: "/favicon.ico"})
+	for _, tag := range htmlLinkPattern.FindAllString(page, -1) {
+		attrs := htmlAttrs(tag)
+		if strings.Contains(strings.ToLower(attrs["rel"]), "icon") && attrs["href"] != "" {
+			if href, err := base.Parse(attrs["href"]); err == nil {
+				icon = href
+				break
+			}
+		}
+	}
+	return title, description, icon
+}
+
+// fetchURL downloads at most bookmarkReadLimit bytes from an address
+func fetchURL(client *http.Client, address string) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, address, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "gleaner")
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("%s returned %s", address, resp.Status)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, bookmarkReadLimit))
+}
+
+// fetchPage reads the details of the page at u
+func fetchPage(u *url.URL) (pageDetails, error) {
+	client := &http.Client{Timeout: 15 * time.Second}
+	page, err := fetchURL(client, u.String())
+	if err != nil {
+		return pageDetails{icon: defaultBookmarkIcon}, err
+	}
+	title, description, iconURL := parsePage(string(page), u)
+	details := pageDetails{title: title, description: description, icon: defaultBookmarkIcon}
+	if data, err := fetchURL(client, iconURL.String()); err == nil {
+		if img := decodeFavicon(data); img != nil {
+			details.icon = colorEmoji(averageColor(img))
+		}
+	}
+	return details, nil
+}
+
+// decodeIcon decodes a PNG, GIF or JPEG image no larger than
+// faviconMaxSize each way, checking its size before decoding any pixels
+func decodeIcon(data []byte) (image.Image, error) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if config.Width > faviconMaxSize || config.Height > faviconMaxSize {
+		return nil, fmt.Errorf("icon is %dx%d pixels", config.Width, config.Height)
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	return img, err
+}
+
+// decodeFavicon decodes a PNG, GIF or JPEG favicon, or the largest PNG
+// image in an ICO file; it returns nil for anything else, and for images
+// larger than faviconMaxSize
+func decodeFavicon(data []byte) image.Image {
+	if img, err := decodeIcon(data); err == nil {
+		return img
+	}
+
+	// ICO files start with a directory of the images they hold
+	if len(data) < 6 || binary.LittleEndian.Uint16(data[2:]) != 1 {
+		return nil
+	}
+	var best image.Image
+	count := int(binary.LittleEndian.Uint16(data[4:]))
+	for i := 0; i < count && 6+16*(i+1) <= len(data); i++ {
+		entry := data[6+16*i:]
+		size := int(binary.LittleEndian.Uint32(entry[8:]))
+		offset := int(binary.LittleEndian.Uint32(entry[12:]))
+		if offset < 0 || size <= 0 || offset+size > len(data) {
+			continue
+		}
+		img, err := decodeIcon(data[offset : offset+size])
+		if err == nil && (best == nil || img.Bounds().Dx() > best.Bounds().Dx()) {
+			best = img
+		}
+	}
+	return best
+}
+
+// averageColor returns the average of an image's colourful pixels, or of
+// all its opaque pixels when few are colourful
+func averageColor(img image.Image) color.RGBA {
+	var all, vivid [4]uint64 // Sums of red, green and blue, and the count
+	bounds := img.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			r, g, b, a := img.At(x, y).RGBA()
+			if a < 0x8000 {
+				continue
+			}
+			r, g, b = r>>8, g>>8, b>>8
+			all = [4]uint64{all[0] + uint64(r), all[1] + uint64(g), all[2] + uint64(b), all[3] + 1}
+			if max(r, g, b)-min(r, g, b) > 64 {
+				vivid = [4]uint64{vivid[0] + uint64(r), vivid[1] + uint64(g), vivid[2] + uint64(b), vivid[3] + 1}
+			}
+		}
+	}
+	sums := all
+	if vivid[3] > 0 && vivid[3]*10 >= all[3] {
+		sums = vivid
+	}
+	if sums[3] == 0 {
+		return color.RGBA{255, 255, 255, 0}
+	}
+	return color.RGBA{uint8(sums[0] / sums[3]), uint8(sums[1] / sums[3]), uint8(sums[2] / sums[3]), 255}
+}
+
+// colorEmoji picks the coloured circle emoji closest to c
+func colorEmoji(c color.RGBA) string {
+	if c.A == 0 {
+		return defaultBookmarkIcon
+	}
+	r, g, b := float64(c.R)/255, float64(c.G)/255, float64(c.B)/255
+	high, low := max(r, g, b), min(r, g, b)
+	lightness := (high + low) / 2
+	if high-low < 0.15 {
+		if lightness < 0.5 {
+			return "⚫"
+		}
+		return "⚪"
+	}
+
+	var hue float64
+	switch high {
+	case r:
+		hue = 60 * (g - b) / (high - low)
+	case g:
+		hue = 60 * ((b-r)/(high-low) + 2)
+	default:
+		hue = 60 * ((r-g)/(high-low) + 4)
+	}
+	if hue < 0 {
+		hue += 360
+	}
+	switch {
+	case hue < 15 || hue >= 345:
+		return "🔴"
+	case hue < 45 && lightness < 0.35:
+		return "🟤"
+	case hue < 45:
+		return "🟠"
+	case hue < 70:
+		return "🟡"
+	case hue < 170:
+		return "🟢"
+	case hue < 260:
+		return "🔵"
+	default:
+		return "🟣"
+	}
+}
+
+// siteName returns the host of an address without a leading "www."
+func siteName(address string) string {
+	u, err := url.Parse(address)
+	if err != nil || u.Host == "" {
+		return address
+	}
+	return strings.TrimPrefix(u.Hostname(), "www.")
+}
+
+// saveBookmark fetches the page at address and saves it as a bookmark
+// note. When the page cannot be read the bookmark is still saved, named
+// after the site, and the error is reported.
+func saveBookmark(address string) tea.Cmd {
+	return func() tea.Msg {
+		address = strings.TrimSpace(address)
+		if !strings.Contains(address, "://") {
+			address = "https://" + address
+		}
+		u, err := url.Parse(address)
+		if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+			return bookmarkMsg{err: fmt.Errorf("not a web address: %s", address)}
+		}
+
+		details, fetchErr := fetchPage(u)
+		title := details.title
+		if title == "" {
+			title = siteName(address)
+		}
+
+		content := "# " + title + "\n"
+		if details.description != "" {
+			content += "\n" + details.description + "\n"
+		}
+		for _, field := range [][2]string{
+			{"type", noteTypeBookmark},
+			{"url", address},
+			{"description", details.description},
+			{"icon", details.icon},
+		} {
+			if field[1] != "" {
+				content = setFrontmatter(content, field[0], yamlString(field[1]))
+			}
+		}
+
+		path := filepath.Join(notesDir, fmt.Sprintf("%d-%s%s", time.Now().Unix(), sanitizeFileName(title), newNoteExt()))
+		if err := writeNoteFile(path, content); err != nil {
+			return bookmarkMsg{err: err}
+		}
+		return bookmarkMsg{title: title, err: fetchErr, saved: true}
+	}
+}
+
+// yamlString quotes a frontmatter value when it would otherwise be misread
+func yamlString(value string) string {
+	if strings.ContainsAny(value, ":#[]{},\"'") {
+		return `"` + strings.ReplaceAll(value, `"`, `'`) + `"`
+	}
+	return value
+}
+
+// openInBrowser opens an address with the system's default browser
+func openInBrowser(address string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", address)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", address)
+	default:
+		cmd = exec.Command("xdg-open", address)
+	}
+	cmd.Stdout, cmd.Stderr = nil, nil
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	go cmd.Wait() //nolint: errcheck
+	return nil
+}
+
+// openBookmark opens the selected bookmark's page in the browser
+func (m model) openBookmark() model {
+	if m.selectedNote.url == "" {
+		m.status = tr("This note has no web address")
+		return m
+	}
+	if err := openInBrowser(m.selectedNote.url); err != nil {
+		m.status = trf("Could not open the browser: %v", err)
+		return m
+	}
+	m.status = trf("Opened %s", m.selectedNote.url)
+	return m
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestParsePage(t *testing.T) {
+	base, _ := url.Parse("https://example.com/blog/post")
+	tests := []struct {
+		name, page         string
+		title, description string
+		icon               string
+	}{
+		{
+			"title and description",
+			`<title> A  &amp; B </title><meta name="description" content="First  one"><meta name="description" content="Second">`,
+			"A & B", "First one", "https://example.com/favicon.ico",
+		},
+		{
+			"open graph fallbacks",
+			`<meta property="og:title" content="OG title"><meta property="og:description" content="OG text"><meta name="description" content="Plain">`,
+			"OG title", "OG text", "https://example.com/favicon.ico",
+		},
+		{
+			"page title before open graph",
+			`<title>Page</title><meta property="og:title" content="OG">`,
+			"Page", "", "https://example.com/favicon.ico",
+		},
+		{
+			"icon link",
+			`<link rel="stylesheet" href="/s.css"><link rel="shortcut icon" href="img/icon.png">`,
+			"", "", "https://example.com/blog/img/icon.png",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			title, description, icon := parsePage(tt.page, base)
+			if title != tt.title || description != tt.description || icon.String() != tt.icon {
+				t.Errorf("got %q, %q, %s; want %q, %q, %s", title, description, icon, tt.title, tt.description, tt.icon)
+			}
+		})
+	}
+}
+
+// pngOf encodes a w by h image filled with c
+func pngOf(t *testing.T, w, h int, c color.Color) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, c)
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// icoOf wraps PNG images in an ICO directory
+func icoOf(images ...[]byte) []byte {
+	header := make([]byte, 6+16*len(images))
+	binary.LittleEndian.PutUint16(header[2:], 1)
+	binary.LittleEndian.PutUint16(header[4:], uint16(len(images)))
+	offset := len(header)
+	for i, img := range images {
+		entry := header[6+16*i:]
+		binary.LittleEndian.PutUint32(entry[8:], uint32(len(img)))
+		binary.LittleEndian.PutUint32(entry[12:], uint32(offset))
+		offset += len(img)
+	}
+	return append(header, bytes.Join(images, nil)...)
+}
+
+func TestDecodeFavicon(t *testing.T) {
+	red := color.RGBA{220, 20, 20, 255}
+	blue := color.RGBA{20, 20, 220, 255}
+	tests := []struct {
+		name  string
+		data  []byte
+		width int // 0 when nothing should be decoded
+	}{
+		{"png", pngOf(t, 16, 16, red), 16},
+		{"largest allowed", pngOf(t, faviconMaxSize, 1, red), faviconMaxSize},
+		{"too wide", pngOf(t, faviconMaxSize+1, 1, red), 0},
+		{"too tall", pngOf(t, 1, faviconMaxSize+1, red), 0},
+		{"ico picks the largest", icoOf(pngOf(t, 16, 16, red), pngOf(t, 32, 32, blue)), 32},
+		{"ico skips an oversized image", icoOf(pngOf(t, 16, 16, red), pngOf(t, 300, 300, blue)), 16},
+		{"ico entry out of range", append(icoOf(pngOf(t, 8, 8, red))[:6+16], 0), 0},
+		{"not an image", []byte("<html>"), 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			img := decodeFavicon(tt.data)
+			switch {
+			case tt.width == 0 && img != nil:
+				t.Errorf("decoded a %v image", img.Bounds())
+			case tt.width != 0 && (img == nil || img.Bounds().Dx() != tt.width):
+				t.Errorf("decoded %v, want an image %d wide", img != nil, tt.width)
+			}
+		})
+	}
+}
+
+func TestColorEmoji(t *testing.T) {
+	tests := []struct {
+		c    color.RGBA
+		want string
+	}{
+		{color.RGBA{0, 0, 0, 0}, defaultBookmarkIcon},
+		{color.RGBA{20, 20, 20, 255}, "⚫"},
+		{color.RGBA{240, 240, 240, 255}, "⚪"},
+		{color.RGBA{220, 20, 20, 255}, "🔴"},
+		{color.RGBA{240, 140, 20, 255}, "🟠"},
+		{color.RGBA{110, 60, 10, 255}, "🟤"},
+		{color.RGBA{230, 220, 20, 255}, "🟡"},
+		{color.RGBA{20, 200, 40, 255}, "🟢"},
+		{color.RGBA{20, 60, 220, 255}, "🔵"},
+		{color.RGBA{150, 20, 220, 255}, "🟣"},
+	}
+	for _, tt := range tests {
+		if got := colorEmoji(tt.c); got != tt.want {
+			t.Errorf("colorEmoji(%v) = %s, want %s", tt.c, got, tt.want)
+		}
+	}
+
+	// A grey icon with a coloured logo takes the logo's colour
+	img := image.NewRGBA(image.Rect(0, 0, 10, 10))
+	for i := range img.Pix {
+		img.Pix[i] = 128
+	}
+	for x := 0; x < 10; x++ {
+		img.Set(x, 0, color.RGBA{20, 200, 40, 255})
+	}
+	if got := colorEmoji(averageColor(img)); got != "🟢" {
+		t.Errorf("grey icon with a green stripe is %s", got)
+	}
+}
+
+func TestFetchPage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/":
+			w.Write([]byte(`<title>Home</title><meta name="description" content="About us">`))
+		case "/favicon.ico":
+			w.Write(pngOf(t, 16, 16, color.RGBA{20, 60, 220, 255}))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	u, _ := url.Parse(server.URL + "/")
+	details, err := fetchPage(u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (pageDetails{title: "Home", description: "About us", icon: "🔵"}); details != want {
+		t.Errorf("got %+v, want %+v", details, want)
+	}
+
+	u, _ = url.Parse(server.URL + "/missing")
+	if details, err := fetchPage(u); err == nil || details.icon != defaultBookmarkIcon {
+		t.Errorf("missing page gave %+v, %v", details, err)
+	}
+}
+
+func TestSiteName(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"https://www.example.com/a", "example.com"},
+		{"http://blog.example.com:8080/", "blog.example.com"},
+		{"not a url", "not a url"},
+	}
+	for _, tt := range tests {
+		if got := siteName(tt.in); got != tt.want {
+			t.Errorf("siteName(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
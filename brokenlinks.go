@@ -0,0 +1,322 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Most suggestions offered for fixing a broken link
+const maxLinkSuggestions = 3
+
+// brokenLink is a link in a note to a note that does not exist
+type brokenLink struct {
+	source note   // Note the link is in
+	line   int    // Line of the note the link is on, from 0
+	target string // Title, file name or gleaner:// link pointed to
+	wiki   bool   // [[Title]] rather than [label](target)
+}
+
+// linkBroken reports whether a link target names a note that is not there.
+// Links to web pages and anchors are not checked, and a title matching
+// several notes still counts as found.
+func linkBroken(notes []note, target string, wiki bool) bool {
+	if wiki {
+		_, err := findNote(notes, strings.TrimSpace(target))
+		return errors.Is(err, errNoNote)
+	}
+	if strings.Contains(target, "://") {
+		if !strings.HasPrefix(target, noteURIScheme+"://") {
+			return false
+		}
+	} else if path, _, _ := strings.Cut(target, "#"); !isNoteExt(filepath.Ext(path)) {
+		return false
+	}
+	_, ok := resolveLink(notes, target)
+	return !ok
+}
+
+// findBrokenLinks returns the links in every note that lead nowhere
+func findBrokenLinks(notes []note) []brokenLink {
+	var broken []brokenLink
+	for _, n := range notes {
+		content, err := readNoteFile(n.path)
+		if err != nil {
+			continue
+		}
+		for i, line := range strings.Split(string(content), "\n") {
+			for _, match := range wikiLinkPattern.FindAllStringSubmatch(line, -1) {
+				if linkBroken(notes, match[1], true) {
+					broken = append(broken, brokenLink{n, i, strings.TrimSpace(match[1]), true})
+				}
+			}
+			for _, match := range linkPattern.FindAllStringSubmatch(line, -1) {
+				if linkBroken(notes, match[2], false) {
+					broken = append(broken, brokenLink{n, i, match[2], false})
+				}
+			}
+		}
+	}
+	return broken
+}
+
+// editDistance counts the single-letter edits turning a into b, ignoring case
+func editDistance(a, b string) int {
+	ra, rb := []rune(strings.ToLower(a)), []rune(strings.ToLower(b))
+	prev := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur := make([]int, len(rb)+1)
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(rb)]
+}
+
+// linkSuggestions returns the notes whose title is closest to a broken
+// link's target, best first; titles differing in more than half their
+// letters are not suggested
+func linkSuggestions(notes []note, target string) []note {
+	if path, _, _ := strings.Cut(target, "#"); isNoteExt(filepath.Ext(path)) {
+		if title, _, ok := parseNoteFilename(filepath.Base(path)); ok {
+			target = title
+		} else {
+			target = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		}
+	}
+	type scored struct {
+		n        note
+		distance int
+	}
+	var near []scored
+	for _, n := range notes {
+		d := editDistance(n.title, target)
+		if strings.Contains(strings.ToLower(n.title), strings.ToLower(target)) {
+			d = min(d, len([]rune(n.title))-len([]rune(target)))
+		}
+		if d <= max(len([]rune(target)), len([]rune(n.title)))/2 {
+			near = append(near, scored{n, d})
+		}
+	}
+	sort.SliceStable(near, func(i, j int) bool { return near[i].distance < near[j].distance })
+
+	var suggestions []note
+	for _, c := range near[:min(len(near), maxLinkSuggestions)] {
+		suggestions = append(suggestions, c.n)
+	}
+	return suggestions
+}
+
+// linkText shows a link the way it is written
+func (l brokenLink) linkText() string {
+	if l.wiki {
+		return "[[" + l.target + "]]"
+	}
+	return "(" + l.target + ")"
+}
+
+// linkValue packs a broken link into a picker value
+func (l brokenLink) linkValue() string {
+	kind := "file"
+	if l.wiki {
+		kind = "wiki"
+	}
+	return strings.Join([]string{kind, strconv.Itoa(l.line), l.source.path, l.target}, "\n")
+}
+
+// parseLinkValue unpacks a picker value made by linkValue
+func parseLinkValue(notes []note, value string) (brokenLink, bool) {
+	parts := strings.SplitN(value, "\n", 4)
+	if len(parts) != 4 {
+		return brokenLink{}, false
+	}
+	line, _ := strconv.Atoi(parts[1])
+	for _, n := range notes {
+		if n.path == parts[2] {
+			return brokenLink{n, line, parts[3], parts[0] == "wiki"}, true
+		}
+	}
+	return brokenLink{}, false
+}
+
+// openBrokenLinks lists the links that lead to missing notes; choosing one
+// offers ways to mend it. Links are checked against every note in the
+// vault, so a note the list is filtered away from still counts as there.
+func (m model) openBrokenLinks() model {
+	notes, err := scanNotes()
+	if err != nil && len(notes) == 0 {
+		m.status = err.Error()
+		return m
+	}
+	broken := findBrokenLinks(notes)
+	if len(broken) == 0 {
+		m.status = tr("No broken links")
+		return m
+	}
+	items := make([]pickerItem, len(broken))
+	for i, l := range broken {
+		items[i] = pickerItem{
+			title: l.linkText(),
+			desc:  trf("In %s, line %d", l.source.title, l.line+1),
+			value: l.linkValue(),
+		}
+	}
+	return m.openPicker("broken-links", trf("%d broken links", len(broken)), items)
+}
+
+// brokenLinkChosen offers to create the missing note, point the link at a
+// note with a similar title, or open the note the link is in
+func (m model) brokenLinkChosen(value string) model {
+	notes, err := scanNotes()
+	if err != nil && len(notes) == 0 {
+		m.status = err.Error()
+		return m
+	}
+	l, ok := parseLinkValue(notes, value)
+	if !ok {
+		return m
+	}
+	var items []pickerItem
+	if !readOnly {
+		if l.wiki {
+			items = append(items, pickerItem{title: trf("Create %s", l.target), desc: tr("New empty note"), value: "create\n" + value})
+		}
+		for _, n := range linkSuggestions(notes, l.target) {
+			items = append(items, pickerItem{title: trf("Link to %s", n.title), desc: n.path, value: "fix\n" + n.path + "\n" + value})
+		}
+	}
+	items = append(items, pickerItem{title: trf("Open %s", l.source.title), desc: trf("Line %d", l.line+1), value: "open\n" + value})
+	return m.openPicker("broken-link-action", l.linkText(), items)
+}
+
+// brokenLinkAction mends or shows a broken link as chosen
+func (m model) brokenLinkAction(value string) (tea.Model, tea.Cmd) {
+	action, rest, _ := strings.Cut(value, "\n")
+	var fixed string
+	if action == "fix" {
+		fixed, rest, _ = strings.Cut(rest, "\n")
+	}
+	notes, err := scanNotes()
+	if err != nil && len(notes) == 0 {
+		m.status = err.Error()
+		return m, nil
+	}
+	l, ok := parseLinkValue(notes, rest)
+	if !ok {
+		return m, nil
+	}
+
+	switch action {
+	case "create":
+		m.status = trf("Created %s", l.target)
+		return m, tea.Batch(saveNote(l.target, "", nil), loadNotes)
+	case "fix":
+		var to note
+		for _, n := range notes {
+			if n.path == fixed {
+				to = n
+			}
+		}
+		if err := relinkNote(l, to); err != nil {
+			m.status = err.Error()
+			return m, nil
+		}
+		m.status = trf("Linked to %s", to.title)
+		return m, loadNotes
+	case "open":
+		m = m.selectPath(l.source.path)
+		if m.selectedNote == nil || m.selectedNote.path != l.source.path {
+			m.status = tr("The note is hidden by the current filter")
+			return m, nil
+		}
+		return m.jumpToLine(l.line), nil
+	}
+	return m, nil
+}
+
+// relinkNote points a broken link at another note: a wiki link at its
+// title, and any other link at its file name, keeping the folder and
+// heading anchor the link names, as rewriteLinks does
+func relinkNote(l brokenLink, to note) error {
+	content, err := readNoteFile(l.source.path)
+	if err != nil {
+		return err
+	}
+	lines := strings.Split(string(content), "\n")
+	if l.line >= len(lines) {
+		return errors.New("the note has changed since it was checked")
+	}
+
+	line := lines[l.line]
+	if l.wiki {
+		line = wikiLinkPattern.ReplaceAllStringFunc(line, func(link string) string {
+			target := wikiLinkPattern.FindStringSubmatch(link)[1]
+			if strings.TrimSpace(target) != l.target {
+				return link
+			}
+			return strings.Replace(link, target, to.title, 1)
+		})
+	} else {
+		target, anchor, _ := strings.Cut(l.target, "#")
+		updated := url.PathEscape(filepath.Base(to.path))
+		if i := strings.LastIndex(target, "/"); i >= 0 && !strings.Contains(target, "://") {
+			updated = target[:i+1] + updated
+		}
+		if anchor != "" {
+			updated += "#" + anchor
+		}
+		line = strings.ReplaceAll(line, "]("+l.target+")", "]("+updated+")")
+	}
+	if line == lines[l.line] {
+		return errors.New("the note has changed since it was checked")
+	}
+	lines[l.line] = line
+	return writeNoteFile(l.source.path, strings.Join(lines, "\n"))
+}
+
+// runBrokenLinks implements `gleaner broken-links`, printing each link to a
+// missing note with the closest titles
+func runBrokenLinks(args []string) error {
+	flags := flag.NewFlagSet("broken-links", flag.ContinueOnError)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() > 0 {
+		return errors.New("usage: gleaner broken-links")
+	}
+
+	notes, err := notesForCommand()
+	if err != nil {
+		return err
+	}
+	broken := findBrokenLinks(notes)
+	for _, l := range broken {
+		fmt.Printf("%s:%d: %s", l.source.path, l.line+1, l.linkText())
+		var titles []string
+		for _, n := range linkSuggestions(notes, l.target) {
+			titles = append(titles, n.title)
+		}
+		if len(titles) > 0 {
+			fmt.Printf(" (did you mean %s?)", strings.Join(titles, ", "))
+		}
+		fmt.Println()
+	}
+	fmt.Printf("%d broken links\n", len(broken))
+	return nil
+}
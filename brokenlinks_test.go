@@ -0,0 +1,96 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestBrokenLinks(t *testing.T) {
+	oldDir, oldCfg := notesDir, cfg
+	t.Cleanup(func() { notesDir, cfg = oldDir, oldCfg })
+	notesDir, cfg = t.TempDir(), config{}
+	for rel, content := range map[string]string{
+		"1700000000-Meeting-notes.md": "Agenda\n",
+		"1700000001-Roadmap.md":       "[[Meeting notes]] and [[Meeting nots]]\n[plan](projects/1700000009-Plan.md#q1) [site](https://example.com/x.md) [img](logo.png)\n",
+		"projects/1700000002-Site.md": "[[Roadmap]] [[Nothing like it]]\n[r](../1700000001-Roadmap.md)\n",
+	} {
+		path := filepath.Join(notesDir, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	notes, err := scanNotes()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	broken := findBrokenLinks(notes)
+	var found []string
+	for _, l := range broken {
+		found = append(found, l.source.title+" "+l.linkText())
+	}
+	slices.Sort(found)
+	want := []string{
+		"Roadmap (projects/1700000009-Plan.md#q1)",
+		"Roadmap [[Meeting nots]]",
+		"Site [[Nothing like it]]",
+	}
+	if !slices.Equal(found, want) {
+		t.Fatalf("broken links %q, want %q", found, want)
+	}
+
+	var titles []string
+	for _, n := range linkSuggestions(notes, "Meeting nots") {
+		titles = append(titles, n.title)
+	}
+	if !slices.Equal(titles, []string{"Meeting notes"}) {
+		t.Errorf("suggested %q for Meeting nots", titles)
+	}
+	if got := linkSuggestions(notes, "Nothing like it"); len(got) != 0 {
+		t.Errorf("suggested %d notes for a title like none", len(got))
+	}
+
+	var meeting note
+	for _, n := range notes {
+		if n.title == "Meeting notes" {
+			meeting = n
+		}
+	}
+	for _, l := range broken {
+		if l.source.title == "Roadmap" {
+			if err := relinkNote(l, meeting); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	data, err := os.ReadFile(filepath.Join(notesDir, "1700000001-Roadmap.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "[[Meeting notes]] and [[Meeting notes]]\n[plan](projects/1700000000-Meeting-notes.md#q1) [site](https://example.com/x.md) [img](logo.png)\n"; string(data) != want {
+		t.Errorf("relinked note is\n%s\nwant\n%s", data, want)
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"Plan", "plan", 0},
+		{"kitten", "sitting", 3},
+		{"Meeting nots", "Meeting notes", 1},
+		{"", "abc", 3},
+	}
+	for _, tt := range tests {
+		if got := editDistance(tt.a, tt.b); got != tt.want {
+			t.Errorf("editDistance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
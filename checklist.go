@@ -0,0 +1,234 @@
+package main
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Note type, set with "type: checklist" in the frontmatter, whose content
+// pane is a list of checkboxes to work through rather than text to read
+const noteTypeChecklist = "checklist"
+
+// Style of the checklist item under the cursor
+var checklistCursorStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("212"))
+
+// checklistActive reports whether the focused content pane shows a
+// checklist note
+func (m model) checklistActive() bool {
+	return m.mode == "list" && m.contentFocus && m.selectedNote != nil && m.selectedNote.kind == noteTypeChecklist
+}
+
+// checklistItems returns the line numbers of the checkboxes in a note
+func checklistItems(lines []string) []int {
+	var items []int
+	for i, line := range lines {
+		if match := listItemPattern.FindStringSubmatch(line); match != nil && match[6] != "" {
+			items = append(items, i)
+		}
+	}
+	return items
+}
+
+// setChecked rewrites a checkbox line as done or open
+func setChecked(line string, done bool) string {
+	match := listItemPattern.FindStringSubmatch(line)
+	if match == nil {
+		return line
+	}
+	box := "[ ] "
+	if done {
+		box = "[x] "
+	}
+	return match[1] + match[2] + match[5] + box + match[7]
+}
+
+// isChecked reports whether a checkbox line is done
+func isChecked(line string) bool {
+	match := listItemPattern.FindStringSubmatch(line)
+	return match != nil && strings.TrimSpace(match[6]) != "[ ]"
+}
+
+// updateChecklist handles keys in a checklist: j/k or the arrows move,
+// space toggles, a adds, e edits and d deletes an item, and shift with an
+// arrow moves it. It reports false for keys it leaves to the caller.
+func (m model) updateChecklist(msg tea.KeyMsg) (tea.Model, tea.Cmd, bool) {
+	lines := strings.Split(m.textarea.Value(), "\n")
+	items := checklistItems(lines)
+	m.checkCursor = max(0, min(m.checkCursor, len(items)-1))
+
+	switch msg.String() {
+	case "up", "k":
+		m.checkCursor = max(0, m.checkCursor-1)
+		return m, nil, true
+	case "down", "j":
+		m.checkCursor = max(0, min(len(items)-1, m.checkCursor+1))
+		return m, nil, true
+	case "home", "g":
+		m.checkCursor = 0
+		return m, nil, true
+	case "end", "G":
+		m.checkCursor = max(0, len(items)-1)
+		return m, nil, true
+	}
+
+	if readOnly {
+		return m, nil, false
+	}
+	switch msg.String() {
+	case " ", "x":
+		if len(items) == 0 {
+			return m, nil, true
+		}
+		row := items[m.checkCursor]
+		lines[row] = setChecked(lines[row], !isChecked(lines[row]))
+		next, cmd := m.writeChecklist(lines)
+		return next, cmd, true
+
+	case "a":
+		next, cmd := m.openPrompt("checklist-add", tr("New item"), "")
+		return next, cmd, true
+
+	case "e":
+		if len(items) == 0 {
+			return m, nil, true
+		}
+		match := listItemPattern.FindStringSubmatch(lines[items[m.checkCursor]])
+		next, cmd := m.openPrompt("checklist-edit", tr("Item"), match[7])
+		return next, cmd, true
+
+	case "d":
+		if len(items) == 0 {
+			return m, nil, true
+		}
+		row := items[m.checkCursor]
+		lines = append(lines[:row], lines[row+1:]...)
+		m.checkCursor = max(0, min(m.checkCursor, len(items)-2))
+		next, cmd := m.writeChecklist(lines)
+		return next, cmd, true
+
+	case "shift+up", "K", "shift+down", "J":
+		up := msg.String() == "shift+up" || msg.String() == "K"
+		other := m.checkCursor + 1
+		if up {
+			other = m.checkCursor - 1
+		}
+		if len(items) == 0 || other < 0 || other >= len(items) {
+			return m, nil, true
+		}
+		a, b := items[m.checkCursor], items[other]
+		lines[a], lines[b] = lines[b], lines[a]
+		m.checkCursor = other
+		next, cmd := m.writeChecklist(lines)
+		return next, cmd, true
+	}
+	return m, nil, false
+}
+
+// checklistPrompted adds a new item after the cursor, or renames the item
+// under it, with the text typed in the prompt
+func (m model) checklistPrompted(kind, text string) (tea.Model, tea.Cmd) {
+	text = strings.TrimSpace(text)
+	if text == "" || m.selectedNote == nil {
+		return m, nil
+	}
+	lines := strings.Split(m.textarea.Value(), "\n")
+	items := checklistItems(lines)
+
+	if kind == "checklist-edit" {
+		if m.checkCursor >= len(items) {
+			return m, nil
+		}
+		row := items[m.checkCursor]
+		match := listItemPattern.FindStringSubmatch(lines[row])
+		lines[row] = match[1] + match[2] + match[5] + match[6] + text
+		return m.writeChecklist(lines)
+	}
+
+	// New items take the indentation and marker of the one under the cursor
+	item := "- [ ] " + text
+	row := len(lines)
+	for row > 0 && strings.TrimSpace(lines[row-1]) == "" {
+		row--
+	}
+	if len(items) > 0 {
+		row = items[m.checkCursor] + 1
+		match := listItemPattern.FindStringSubmatch(lines[row-1])
+		item = match[1] + match[2] + match[5] + "[ ] " + text
+		m.checkCursor++
+	}
+	lines = append(lines[:row], append([]string{item}, lines[row:]...)...)
+	return m.writeChecklist(lines)
+}
+
+// writeChecklist saves the changed note and shows it straight away
+func (m model) writeChecklist(lines []string) (model, tea.Cmd) {
+	content := strings.Join(lines, "\n")
+	if err := writeNoteFile(m.selectedNote.path, content); err != nil {
+		m.status = err.Error()
+		return m, nil
+	}
+	m.textarea.SetValue(content)
+	return m, loadNotes
+}
+
+// checklistLines renders the body of a checklist note with checkboxes for
+// the items and the cursor on the current one
+func (m model) checklistLines(width int) ([]string, int) {
+	_, body := parseFrontmatter(m.textarea.Value())
+	lines := strings.Split(body, "\n")
+	items := checklistItems(lines)
+
+	var out []string
+	cursorLine := 0
+	for i, line := range lines {
+		item := -1
+		for k, row := range items {
+			if row == i {
+				item = k
+			}
+		}
+		if item < 0 {
+			out = append(out, truncateLine(renderLine(line, width), width))
+			continue
+		}
+
+		match := listItemPattern.FindStringSubmatch(line)
+		box, text := "☐ ", match[7]
+		if isChecked(line) {
+			box = "☑ "
+			text = previewDimStyle.Strikethrough(true).Render(text)
+		}
+		row := match[1] + box + text
+		if item == m.checkCursor {
+			cursorLine = len(out)
+			row = checklistCursorStyle.Render("› ") + row
+		} else {
+			row = "  " + row
+		}
+		out = append(out, truncateLine(row, width))
+	}
+	return out, cursorLine
+}
+
+// checklistItemText describes the item under the cursor for screen readers
+func (m model) checklistItemText() string {
+	lines := strings.Split(m.textarea.Value(), "\n")
+	items := checklistItems(lines)
+	if m.checkCursor >= len(items) {
+		return ""
+	}
+	line := lines[items[m.checkCursor]]
+	state := tr("open")
+	if isChecked(line) {
+		state = tr("done")
+	}
+	return trf("%s, %s, %d of %d", listItemPattern.FindStringSubmatch(line)[7], state, m.checkCursor+1, len(items))
+}
+
+// checklistView renders the checklist with the cursor kept in view
+func (m model) checklistView(width, height int) string {
+	lines, cursor := m.checklistLines(width)
+	return strings.Join(linearWindow(lines, cursor, height), "\n")
+}
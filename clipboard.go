@@ -0,0 +1,57 @@
+package main
+
+import (
+	"encoding/hex"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/atotto/clipboard"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// clipboardMsg reports the outcome of a copy to the clipboard
+type clipboardMsg struct {
+	rich bool // Copied as text/html rather than plain text
+	err  error
+}
+
+// htmlClipboardCommand returns a command that places HTML on the clipboard
+// with a rich-text type, or nil when the platform has no suitable tool
+func htmlClipboardCommand(fragment, plain string) *exec.Cmd {
+	switch runtime.GOOS {
+	case "darwin":
+		script := `set the clipboard to {«class HTML»:«data HTML` + strings.ToUpper(hex.EncodeToString([]byte(fragment))) +
+			`», string:"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(plain) + `"}`
+		return exec.Command("osascript", "-e", script)
+	case "linux", "freebsd", "openbsd", "netbsd":
+		if os.Getenv("WAYLAND_DISPLAY") != "" {
+			if _, err := exec.LookPath("wl-copy"); err == nil {
+				cmd := exec.Command("wl-copy", "--type", "text/html")
+				cmd.Stdin = strings.NewReader(fragment)
+				return cmd
+			}
+		}
+		if _, err := exec.LookPath("xclip"); err == nil {
+			cmd := exec.Command("xclip", "-selection", "clipboard", "-t", "text/html")
+			cmd.Stdin = strings.NewReader(fragment)
+			return cmd
+		}
+	}
+	return nil
+}
+
+// copyHTML converts a note to HTML and copies it to the clipboard, falling
+// back to the HTML source as plain text where rich copies are unsupported
+func copyHTML(content, ext string) tea.Cmd {
+	return func() tea.Msg {
+		fragment := noteToHTML(content, ext)
+		if cmd := htmlClipboardCommand(fragment, content); cmd != nil {
+			if err := cmd.Run(); err == nil {
+				return clipboardMsg{rich: true}
+			}
+		}
+		return clipboardMsg{err: clipboard.WriteAll(fragment)}
+	}
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// Zone that note times are shown and grouped into days in: "timezone" from
+// the config file, or the system zone ($TZ) when unset. Note files store
+// creation times as Unix seconds, which do not depend on any zone.
+var displayZone = time.Local
+
+// setTimezone loads the named IANA timezone, e.g. "Europe/Berlin", as the
+// display zone; an empty name keeps the system zone
+func setTimezone(name string) error {
+	displayZone = time.Local
+	if name == "" {
+		return nil
+	}
+	zone, err := time.LoadLocation(name)
+	if err != nil {
+		return fmt.Errorf("unknown timezone %q in config", name)
+	}
+	displayZone = zone
+	return nil
+}
+
+// localNow returns the current time in the display zone
+func localNow() time.Time {
+	return time.Now().In(displayZone)
+}
+
+// noteTime converts a stored Unix timestamp to the display zone
+func noteTime(stamp int64) time.Time {
+	return time.Unix(stamp, 0).In(displayZone)
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+)
+
+// errReadOnly is returned by commands that would modify a read-only vault
+var errReadOnly = errors.New("vault is read-only")
+
+// runCommand dispatches a gleaner subcommand given on the command line
+func runCommand(name string, args []string) error {
+	switch name {
+	case "backup", "export":
+		return runBackup(args)
+	case "restore":
+		return runRestore(args)
+	case "open":
+		return runOpen(args)
+	case "quick":
+		return runQuick(args)
+	case "append":
+		return runAppend(args)
+	case "stats":
+		return runStats(args)
+	case "random":
+		return runRandom(args)
+	case "encrypt":
+		return runEncrypt(args)
+	case "log":
+		return runLog(args)
+	case "trash":
+		return runTrash(args)
+	case "duplicates":
+		return runDuplicates(args)
+	case "broken-links":
+		return runBrokenLinks(args)
+	case "orphans":
+		return runOrphans(args)
+	case "attachments":
+		return runAttachments(args)
+	case "migrate":
+		return runMigrate(args)
+	case "doctor":
+		return runDoctor(args)
+	case "bench":
+		return runBench(args)
+	case "insights":
+		return runInsights(args)
+	case "init":
+		return runInit(args)
+	case "profile":
+		return runProfile(args)
+	case "rename":
+		return runRename(args)
+	case "tag":
+		return runTag(args)
+	default:
+		return fmt.Errorf("unknown command %q", name)
+	}
+}
+
+// flagSet reports whether the named flag was given explicitly
+func flagSet(flags *flag.FlagSet, name string) bool {
+	set := false
+	flags.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
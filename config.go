@@ -0,0 +1,158 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// config holds user settings read from the gleaner config file
+type config struct {
+	NotesDir     string `json:"notes_dir,omitempty"`     // Vault location used when no flag or env var is set
+	ReadOnly     bool   `json:"read_only,omitempty"`     // Disallow creating, editing and deleting notes
+	BackupDir    string `json:"backup_dir,omitempty"`    // Where backup archives are written
+	BackupFormat string `json:"backup_format,omitempty"` // Archive format: zip or tar.gz
+
+	BackupInterval   string `json:"backup_interval,omitempty"`    // How often to snapshot while running, e.g. "6h"
+	BackupKeepDaily  int    `json:"backup_keep_daily,omitempty"`  // Number of daily snapshots to retain
+	BackupKeepWeekly int    `json:"backup_keep_weekly,omitempty"` // Number of weekly snapshots to retain
+
+	TrashRetentionDays int    `json:"trash_retention_days,omitempty"` // Days deleted notes stay in the trash; negative keeps them until purged
+	AttachmentsDir     string `json:"attachments_dir,omitempty"`      // Folder of images and files notes link to; defaults to attachments/ and assets/
+
+	Vaults       map[string]vaultConfig `json:"vaults,omitempty"`        // Named vaults that can be switched between
+	DefaultVault string                 `json:"default_vault,omitempty"` // Vault opened when none is chosen explicitly
+
+	Hooks map[string][]string `json:"hooks,omitempty"` // Commands to run on note lifecycle events
+
+	ListDensity string  `json:"list_density,omitempty"` // "detailed" (default) or "compact"
+	SortBy      string  `json:"sort_by,omitempty"`      // Order and date the list by "created" (default) or "modified", or order by "priority"
+	SplitRatio  float64 `json:"split_ratio,omitempty"`  // Fraction of the width given to the list pane
+	ZenWidth    int     `json:"zen_width,omitempty"`    // Column width of the editor in zen mode
+
+	LineNumbers bool `json:"line_numbers,omitempty"` // Show line numbers in the editor
+	NoWrap      bool `json:"no_wrap,omitempty"`      // Cut long lines instead of wrapping them while reading
+	TabWidth    int  `json:"tab_width,omitempty"`    // Spaces inserted by the tab key in the editor
+
+	Snippets        map[string]string `json:"snippets,omitempty"`         // Abbreviations expanded in the editor
+	MeetingTemplate string            `json:"meeting_template,omitempty"` // Text of new meeting notes; see defaultMeetingTemplate
+
+	GistToken    string `json:"gist_token,omitempty"`    // GitHub token used to share notes as gists
+	PasteService string `json:"paste_service,omitempty"` // "0x0.st" (default), "paste.rs", or a URL to POST notes to
+
+	DailyWordGoal int `json:"daily_word_goal,omitempty"` // Words to write each day to keep a streak going
+
+	Locale        string `json:"locale,omitempty"`         // Language of the interface, e.g. "de"; defaults to $LANG
+	ScreenReader  bool   `json:"screen_reader,omitempty"`  // Plain labeled output and spoken changes for screen readers
+	Theme         string `json:"theme,omitempty"`          // "high-contrast" or "no-color"; the default palette otherwise
+	ImageProtocol string `json:"image_protocol,omitempty"` // "kitty", "iterm2", "sixel" or "none"; detected from the terminal by default
+
+	TimestampFormat string `json:"timestamp_format,omitempty"` // Go time layout for note dates, e.g. "02 Jan 2006 15:04"
+	Timezone        string `json:"timezone,omitempty"`         // IANA zone note times are shown in, e.g. "Europe/Berlin"
+	RelativeTimes   bool   `json:"relative_times,omitempty"`   // Show "3h ago" rather than the full time in the list
+	OnThisDay       bool   `json:"on_this_day,omitempty"`      // Show the notes written on this day in the past at startup
+
+	Insights bool `json:"insights,omitempty"` // Keep local usage counters for the statistics screen
+
+	Extensions []string `json:"extensions,omitempty"` // File extensions that count as notes; new notes use the first
+	Statuses   []string `json:"statuses,omitempty"`   // Workflow states alt+S cycles a note through, e.g. draft, review, published
+
+	VaultKeyFile string `json:"vault_key_file,omitempty"` // Key of encrypted vaults; defaults to vault.key in the config directory
+
+	Keymap map[string]string `json:"keymap,omitempty"` // Shortcuts moved to other keys, e.g. "alt+C": "ctrl+y"
+}
+
+var (
+	// Active configuration, populated by loadConfig at startup
+	cfg config
+
+	// Set by --read-only; forces read-only mode for every vault
+	readOnlyFlag bool
+
+	// Whether the open vault may be modified
+	readOnly bool
+)
+
+// resolveNotesDir picks the vault location from the --dir flag, then
+// GLEANER_DIR, then the config file, falling back to the data directory
+func resolveNotesDir(flagDir string) string {
+	for _, dir := range []string{flagDir, os.Getenv("GLEANER_DIR"), cfg.NotesDir} {
+		if dir != "" {
+			return expandHome(dir)
+		}
+	}
+	return defaultNotesDir()
+}
+
+// homeDir returns the user's home directory: $HOME on Unix and the user
+// profile folder on Windows, where HOME is usually unset
+func homeDir() string {
+	dir, _ := os.UserHomeDir()
+	return dir
+}
+
+// expandHome replaces a leading ~ with the user's home directory; on
+// Windows ~\ works as well as ~/
+func expandHome(path string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") || strings.HasPrefix(path, "~"+string(filepath.Separator)) {
+		return filepath.Join(homeDir(), path[1:])
+	}
+	return path
+}
+
+// configDir returns the directory holding gleaner's configuration
+func configDir() string {
+	if portableDir != "" {
+		return filepath.Join(portableDir, "config")
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		dir = filepath.Join(homeDir(), ".config")
+	}
+	return filepath.Join(dir, "gleaner")
+}
+
+// configPath returns the location of the config file
+func configPath() string {
+	return filepath.Join(configDir(), "config.json")
+}
+
+// loadConfig reads the config file, leaving defaults in place when it is missing
+func loadConfig() error {
+	data, err := os.ReadFile(configPath())
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return err
+	}
+	baseCfg = cfg
+	if problems := keymapProblems(cfg.Keymap); len(problems) > 0 {
+		return fmt.Errorf("keymap: %s", strings.Join(problems, "; "))
+	}
+	_, err = backupInterval()
+	return err
+}
+
+// saveConfig persists settings changed from within the app. It writes the
+// settings as loaded from disk, so vault overrides never leak into the file.
+func saveConfig() error {
+	data, err := json.MarshalIndent(baseCfg, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(configDir(), 0755); err != nil {
+		return err
+	}
+	// The file may hold the gist token, so only its owner may read it
+	if err := os.WriteFile(configPath(), append(data, '\n'), 0600); err != nil {
+		return err
+	}
+	return os.Chmod(configPath(), 0600)
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// dateRange limits the notes list to those created or modified in a period
+type dateRange struct {
+	modified bool      // Compare modification rather than creation times
+	from, to time.Time // Half-open interval; a zero bound is unbounded
+	label    string    // Describes the range in the list title, empty when off
+}
+
+// Named ranges offered in the date picker, in display order
+var datePresets = []string{"today", "yesterday", "this week", "last week", "this month", "last month", "this year"}
+
+// contains reports whether the note falls inside the range
+func (r dateRange) contains(n note) bool {
+	stamp := n.createdAt
+	if r.modified {
+		stamp = n.modifiedAt
+	}
+	t := noteTime(stamp)
+	return (r.from.IsZero() || !t.Before(r.from)) && (r.to.IsZero() || t.Before(r.to))
+}
+
+// filterByDate keeps the notes inside the range, or all notes when the
+// range is off
+func filterByDate(notes []note, r dateRange) []note {
+	if r.label == "" {
+		return notes
+	}
+	var kept []note
+	for _, n := range notes {
+		if r.contains(n) {
+			kept = append(kept, n)
+		}
+	}
+	return kept
+}
+
+// presetRange returns the interval a named range covers around now
+func presetRange(name string, now time.Time) (time.Time, time.Time) {
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	// Weeks start on Monday
+	week := day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+
+	switch name {
+	case "today":
+		return day, day.AddDate(0, 0, 1)
+	case "yesterday":
+		return day.AddDate(0, 0, -1), day
+	case "this week":
+		return week, week.AddDate(0, 0, 7)
+	case "last week":
+		return week.AddDate(0, 0, -7), week
+	case "this month":
+		return month, month.AddDate(0, 1, 0)
+	case "last month":
+		return month.AddDate(0, -1, 0), month
+	default: // this year
+		year := time.Date(now.Year(), 1, 1, 0, 0, 0, 0, now.Location())
+		return year, year.AddDate(1, 0, 0)
+	}
+}
+
+// parseDateBound reads a day, month or year and returns the period it covers
+func parseDateBound(text string) (time.Time, time.Time, error) {
+	for _, layout := range []struct {
+		format string
+		years  int
+		months int
+		days   int
+	}{
+		{"2006-01-02", 0, 0, 1},
+		{"2006-01", 0, 1, 0},
+		{"2006", 1, 0, 0},
+	} {
+		if t, err := time.ParseInLocation(layout.format, text, displayZone); err == nil {
+			return t, t.AddDate(layout.years, layout.months, layout.days), nil
+		}
+	}
+	return time.Time{}, time.Time{}, fmt.Errorf("%q is not a date (use YYYY-MM-DD, YYYY-MM or YYYY)", text)
+}
+
+// parseDateRange reads "FROM..TO", where either side may be left out, or a
+// single day, month or year
+func parseDateRange(text string) (time.Time, time.Time, error) {
+	text = strings.TrimSpace(text)
+	start, end, isRange := strings.Cut(text, "..")
+	if !isRange {
+		return parseDateBound(text)
+	}
+
+	var from, to time.Time
+	if start = strings.TrimSpace(start); start != "" {
+		var err error
+		if from, _, err = parseDateBound(start); err != nil {
+			return from, to, err
+		}
+	}
+	if end = strings.TrimSpace(end); end != "" {
+		var err error
+		if _, to, err = parseDateBound(end); err != nil {
+			return from, to, err
+		}
+	}
+	if from.IsZero() && to.IsZero() {
+		return from, to, fmt.Errorf("give at least one end of the range")
+	}
+	return from, to, nil
+}
+
+// dateLabel describes a period of creation or modification dates, e.g.
+// "created this week"
+func dateLabel(field, period string) string {
+	if field == "modified" {
+		return trf("modified %s", period)
+	}
+	return trf("created %s", period)
+}
+
+// capitalize upper-cases the first letter of text
+func capitalize(text string) string {
+	r, size := utf8.DecodeRuneInString(text)
+	return string(unicode.ToUpper(r)) + text[size:]
+}
+
+// openDatePicker offers the named ranges for creation and modification dates
+func (m model) openDatePicker() model {
+	items := []pickerItem{{title: tr("Any time"), desc: tr("Show all notes"), value: "off"}}
+	for _, field := range []string{"created", "modified"} {
+		for _, name := range datePresets {
+			items = append(items, pickerItem{
+				title: capitalize(dateLabel(field, tr(name))),
+				value: field + ":" + name,
+			})
+		}
+		items = append(items, pickerItem{
+			title: capitalize(dateLabel(field, tr("in a custom range…"))),
+			desc:  tr("e.g. 2024-03, 2024-03-01..2024-03-15 or 2024-01.."),
+			value: field + ":custom",
+		})
+	}
+	return m.openPicker("dates", tr("Filter by date"), items)
+}
+
+// applyDateChoice sets the date filter chosen in the picker
+func (m model) applyDateChoice(value string) (tea.Model, tea.Cmd) {
+	if value == "off" {
+		m.dateFilter = dateRange{}
+		m.status = tr("Showing notes from any time")
+		return m, loadNotes
+	}
+
+	field, name, _ := strings.Cut(value, ":")
+	if name == "custom" {
+		return m.openPrompt(field+"-range", capitalize(dateLabel(field, tr("between"))), "")
+	}
+	from, to := presetRange(name, localNow())
+	m.dateFilter = dateRange{modified: field == "modified", from: from, to: to, label: dateLabel(field, tr(name))}
+	m.status = trf("Showing notes %s", m.dateFilter.label)
+	return m, loadNotes
+}
+
+// setCustomDateRange sets the date filter from a typed range
+func (m model) setCustomDateRange(modified bool, text string) (tea.Model, tea.Cmd) {
+	from, to, err := parseDateRange(text)
+	if err != nil {
+		m.status = err.Error()
+		return m, nil
+	}
+	field := "created"
+	if modified {
+		field = "modified"
+	}
+	m.dateFilter = dateRange{modified: modified, from: from, to: to, label: dateLabel(field, strings.TrimSpace(text))}
+	m.status = trf("Showing notes %s", m.dateFilter.label)
+	return m, loadNotes
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Maximum length of the cached body excerpt shown under each title
+const excerptLength = 120
+
+// List densities
+const (
+	densityDetailed = "detailed"
+	densityCompact  = "compact"
+)
+
+// Fields the list can be ordered and dated by
+const (
+	sortCreated  = "created"
+	sortModified = "modified"
+	sortPriority = "priority" // Most important first, then newest created
+)
+
+// noteDelegate renders notes in the list with a body excerpt under the title.
+// It reuses the default delegate's styles so rows look like the rest of the list.
+type noteDelegate struct {
+	list.DefaultDelegate
+	compact bool // Render each note on a single line
+}
+
+// newNoteDelegate builds the delegate used for the notes list
+func newNoteDelegate(density string) noteDelegate {
+	d := noteDelegate{DefaultDelegate: themeDelegate(list.NewDefaultDelegate())}
+	d.ShowDescription = true // Show creation timestamps and excerpts
+	if density == densityCompact {
+		d.compact = true
+		d.SetHeight(1)
+		d.SetSpacing(0)
+	} else {
+		d.SetHeight(3)
+	}
+	return d
+}
+
+// Render draws a single note: title, timestamp and excerpt
+func (d noteDelegate) Render(w io.Writer, m list.Model, index int, item list.Item) {
+	n, ok := item.(note)
+	if !ok || m.Width() <= 0 {
+		return
+	}
+
+	s := &d.Styles
+	textwidth := m.Width() - s.NormalTitle.GetPaddingLeft() - s.NormalTitle.GetPaddingRight()
+	title := ansi.Truncate(n.Title(), textwidth, "…")
+	lines := []string{n.Description()}
+	switch {
+	case n.readErr != nil:
+		lines = append(lines, "⚠ "+n.readErr.Error())
+	case n.excerpt != "":
+		lines = append(lines, n.excerpt)
+	}
+
+	var (
+		isSelected  = index == m.Index()
+		emptyFilter = m.FilterState() == list.Filtering && m.FilterValue() == ""
+		isFiltered  = m.FilterState() == list.Filtering || m.FilterState() == list.FilterApplied
+		titleStyle  = s.NormalTitle
+		descStyle   = s.NormalDesc
+	)
+	switch {
+	case emptyFilter:
+		titleStyle, descStyle = s.DimmedTitle, s.DimmedDesc
+	case isSelected && m.FilterState() != list.Filtering:
+		titleStyle, descStyle = s.SelectedTitle, s.SelectedDesc
+	}
+
+	// Highlight the characters matched by the filter
+	if isFiltered && !emptyFilter && highlightMatches() {
+		unmatched := titleStyle.Inline(true)
+		matched := unmatched.Inherit(s.FilterMatch)
+		title = lipgloss.StyleRunes(title, m.MatchesForItem(index), matched, unmatched)
+	}
+
+	if d.compact {
+		row := titleStyle.Render(title) + lipgloss.NewStyle().Foreground(descStyle.GetForeground()).Render("  "+n.Description())
+		if badges := noteBadges(n); badges != "" {
+			row += " " + badges
+		}
+		fmt.Fprint(w, ansi.Truncate(row, m.Width(), "…")) //nolint: errcheck
+		return
+	}
+
+	for i, line := range lines {
+		lines[i] = descStyle.Render(ansi.Truncate(line, textwidth, "…"))
+	}
+	if badges := noteBadges(n); badges != "" {
+		lines[0] = ansi.Truncate(lines[0]+" "+badges, m.Width(), "…")
+	}
+	fmt.Fprintf(w, "%s\n%s", titleStyle.Render(title), strings.Join(lines, "\n")) //nolint: errcheck
+}
+
+// Badge styles for list rows
+var (
+	badgeStyle     = lipgloss.NewStyle().Padding(0, 1)
+	tagBadge       = badgeStyle.Foreground(lipgloss.Color("230")).Background(lipgloss.Color("63"))
+	pinnedBadge    = badgeStyle.Foreground(lipgloss.Color("0")).Background(lipgloss.Color("214"))
+	archivedBadge  = badgeStyle.Foreground(lipgloss.Color("250")).Background(lipgloss.Color("238"))
+	encryptedBadge = badgeStyle.Foreground(lipgloss.Color("230")).Background(lipgloss.Color("160"))
+	tasksBadge     = badgeStyle.Foreground(lipgloss.Color("0")).Background(lipgloss.Color("42"))
+	errorBadge     = badgeStyle.Foreground(lipgloss.Color("230")).Background(lipgloss.Color("203"))
+)
+
+// noteBadges renders compact colored markers for a note's state and tags
+func noteBadges(n note) string {
+	var badges []string
+	if n.readErr != nil {
+		badges = append(badges, errorBadge.Render("⚠ "+tr("unreadable")))
+	}
+	if n.label != "" {
+		badges = append(badges, labelMarker(n.label))
+	}
+	if n.priority > 0 {
+		badges = append(badges, renderPriority(n.priority))
+	}
+	if n.kind == noteTypeSnippet && n.language != "" {
+		badges = append(badges, languageBadge.Render(n.language))
+	}
+	if n.kind == noteTypeBookmark && n.url != "" {
+		icon := n.icon
+		if icon == "" {
+			icon = defaultBookmarkIcon
+		}
+		badges = append(badges, bookmarkBadge.Render(icon+" "+siteName(n.url)))
+	}
+	if n.status != "" {
+		badges = append(badges, statusBadge.Render(tr(n.status)))
+	}
+	if n.pinned {
+		badges = append(badges, pinnedBadge.Render(tr("pinned")))
+	}
+	if n.archived {
+		badges = append(badges, archivedBadge.Render(tr("archived")))
+	}
+	if n.encrypted {
+		badges = append(badges, encryptedBadge.Render(tr("locked")))
+	}
+	if total := n.tasksOpen + n.tasksDone; total > 0 {
+		badges = append(badges, tasksBadge.Render(taskProgress(n.tasksDone, total)))
+	}
+	for _, tag := range n.tags {
+		badges = append(badges, tagBadge.Render("#"+tag))
+	}
+	return strings.Join(badges, " ")
+}
+
+// Longest a task progress bar grows; notes with more tasks share segments
+const progressSegments = 10
+
+// taskProgress shows how many of a note's tasks are done, as a count and a
+// bar such as "3/7 ▰▰▰▱▱▱▱"
+func taskProgress(done, total int) string {
+	segments := min(total, progressSegments)
+	filled := done * segments / total
+	if done > 0 && filled == 0 {
+		filled = 1 // Some progress always shows
+	}
+	return fmt.Sprintf("%d/%d %s%s", done, total, strings.Repeat("▰", filled), strings.Repeat("▱", segments-filled))
+}
+
+// noteExcerpt returns the first meaningful line of a note body, stripped of
+// markdown heading and list markers
+func noteExcerpt(content string) string {
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		line = strings.TrimLeft(line, "#>-*+ ")
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if len([]rune(line)) > excerptLength {
+			line = string([]rune(line)[:excerptLength])
+		}
+		return line
+	}
+	return ""
+}
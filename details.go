@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Details popup styling
+var detailsLabelStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+
+// relativeTime describes how long ago t was, e.g. "3h ago" or "yesterday".
+// Times in the future, from a skewed clock, are shown in full.
+func relativeTime(t, now time.Time) string {
+	age := now.Sub(t)
+	today, _ := presetRange("today", now)
+	switch {
+	case age < 0:
+		return formatTime(t)
+	case age < time.Minute:
+		return tr("just now")
+	case age < time.Hour:
+		return trf("%dm ago", int(age.Minutes()))
+	case !t.Before(today) || age < 6*time.Hour:
+		return trf("%dh ago", int(age.Hours()))
+	case !t.Before(today.AddDate(0, 0, -1)):
+		return tr("yesterday")
+	}
+
+	// Count whole calendar days, which daylight saving time can shorten
+	days := 0
+	for day := today; t.Before(day); day = day.AddDate(0, 0, -1) {
+		days++
+	}
+	switch {
+	case days < 7:
+		return trf("%dd ago", days)
+	case days < 35:
+		return trf("%dw ago", days/7)
+	case days < 365:
+		return trf("%dmo ago", days/30)
+	default:
+		return trf("%dy ago", days/365)
+	}
+}
+
+// noteDate renders a note time for the list, relative to now when
+// relative_times is set
+func noteDate(stamp int64) string {
+	if cfg.RelativeTimes {
+		return relativeTime(noteTime(stamp), localNow())
+	}
+	return formatTime(noteTime(stamp))
+}
+
+// showDetails opens a popup with the exact times and size of the selected note
+func (m model) showDetails() model {
+	n := m.selectedNote
+	now := localNow()
+	stamp := func(unix int64) string {
+		return formatTime(noteTime(unix)) + detailsLabelStyle.Render(" ("+relativeTime(noteTime(unix), now)+")")
+	}
+
+	rows := [][2]string{
+		{tr("Created"), stamp(n.createdAt)},
+		{tr("Modified"), stamp(n.modifiedAt)},
+		{tr("Words"), fmt.Sprint(wordCount(n.body))},
+	}
+	if len(n.tags) > 0 {
+		rows = append(rows, [2]string{tr("Tags"), strings.Join(n.tags, ", ")})
+	}
+	if len(n.aliases) > 0 {
+		rows = append(rows, [2]string{tr("Aliases"), strings.Join(n.aliases, ", ")})
+	}
+	rows = append(rows, [2]string{tr("File"), n.path})
+
+	width := 0
+	for _, row := range rows {
+		width = max(width, lipgloss.Width(row[0]))
+	}
+	lines := []string{titleStyle.Render(n.title)}
+	for _, row := range rows {
+		lines = append(lines, detailsLabelStyle.Render(fmt.Sprintf("%-*s  ", width, row[0]))+row[1])
+	}
+	lines = append(lines, "", detailsLabelStyle.Render(tr("any key to close")))
+	m.popup = strings.Join(lines, "\n")
+	return m
+}
+
+// popupView shows the popup in a box centered on screen
+func (m model) popupView() string {
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, splitStyle.Render(m.popup))
+}
@@ -0,0 +1,329 @@
+package main
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// diagram is a graph read from a mermaid flowchart or a graphviz dot
+// block, or the messages of a mermaid sequence diagram
+type diagram struct {
+	nodes    []string          // Node ids in the order they first appear
+	labels   map[string]string // Text shown for a node, when not its id
+	edges    []diagramEdge
+	sequence bool // Edges are messages, drawn in order rather than as a graph
+}
+
+// diagramEdge is an arrow between two nodes
+type diagramEdge struct {
+	from, to, label string
+	dashed          bool
+}
+
+// isDiagram reports whether a code block's language is a diagram drawn in
+// the preview
+func isDiagram(lang string) bool {
+	switch strings.ToLower(strings.TrimSpace(lang)) {
+	case "mermaid", "graphviz", "dot":
+		return true
+	}
+	return false
+}
+
+// addNode records a node the first time it appears, and its label when
+// given one
+func (d *diagram) addNode(id, label string) {
+	if d.labels == nil {
+		d.labels = map[string]string{}
+	}
+	if _, seen := d.labels[id]; !seen {
+		d.nodes = append(d.nodes, id)
+		d.labels[id] = id
+	}
+	if label != "" {
+		d.labels[id] = label
+	}
+}
+
+// Parts of a mermaid flowchart: a node with an optional shape around its
+// label, and an arrow of any length with an optional label
+var (
+	mermaidNodePattern = regexp.MustCompile(`^(\w+(?:[.-]\w+)*)\s*(\(\(.*?\)\)|\(\[.*?\]\)|\[\[.*?\]\]|\[\(.*?\)\]|\{\{.*?\}\}|\[.*?\]|\(.*?\)|\{.*?\}|>.*?\])?\s*`)
+	mermaidEdgePattern = regexp.MustCompile(`^(?:(?:--|==|-\.)\s*([^-=.>|&]+?)\s*)?<?(-{2,}|={2,}|-?\.+-)[>ox]?\s*(?:\|([^|]*)\|)?\s*`)
+	mermaidMessage     = regexp.MustCompile(`^(\w+(?:-\w+)*)\s*(-->>|->>|-->|->|--x|-x|--\)|-\))\s*[+-]?(\w+(?:-\w+)*)\s*:\s*(.*)$`)
+	mermaidParticipant = regexp.MustCompile(`^(?:participant|actor)\s+(\w+(?:-\w+)*)(?:\s+as\s+(.+))?$`)
+)
+
+// mermaidShapeText strips the brackets of a node shape such as [text] or
+// ((text)), and quotes around the text
+func mermaidShapeText(shape string) string {
+	text := strings.Trim(shape, "[](){}>")
+	return strings.Trim(strings.TrimSpace(text), `"`)
+}
+
+// parseMermaid reads a flowchart (graph or flowchart) or a sequence
+// diagram. Other kinds of diagram, and statements it does not know, such
+// as styles and subgraphs, are skipped.
+func parseMermaid(src string) (diagram, bool) {
+	var d diagram
+	var kind string
+	for _, raw := range strings.Split(src, "\n") {
+		for _, stmt := range strings.Split(raw, ";") {
+			stmt = strings.TrimSpace(stmt)
+			if stmt == "" || strings.HasPrefix(stmt, "%%") {
+				continue
+			}
+			if kind == "" {
+				kind = strings.Fields(stmt)[0]
+				if kind != "graph" && kind != "flowchart" && kind != "sequenceDiagram" {
+					return d, false
+				}
+				d.sequence = kind == "sequenceDiagram"
+				continue
+			}
+			if d.sequence {
+				parseMermaidMessage(&d, stmt)
+			} else {
+				parseMermaidChain(&d, stmt)
+			}
+		}
+	}
+	return d, kind != ""
+}
+
+// parseMermaidMessage reads one statement of a sequence diagram
+func parseMermaidMessage(d *diagram, stmt string) {
+	if m := mermaidParticipant.FindStringSubmatch(stmt); m != nil {
+		d.addNode(m[1], strings.TrimSpace(m[2]))
+		return
+	}
+	if m := mermaidMessage.FindStringSubmatch(stmt); m != nil {
+		d.addNode(m[1], "")
+		d.addNode(m[3], "")
+		d.edges = append(d.edges, diagramEdge{from: m[1], to: m[3], label: strings.TrimSpace(m[4]), dashed: strings.HasPrefix(m[2], "--")})
+	}
+}
+
+// parseMermaidChain reads a flowchart statement: nodes, joined with &,
+// optionally followed by arrows to further nodes
+func parseMermaidChain(d *diagram, stmt string) {
+	switch strings.Fields(stmt)[0] {
+	case "classDef", "class", "style", "linkStyle", "click", "subgraph", "end", "direction":
+		return
+	}
+	var prev []string
+	var arrow diagramEdge
+	for {
+		var group []string
+		for {
+			m := mermaidNodePattern.FindStringSubmatch(stmt)
+			if m == nil {
+				return
+			}
+			d.addNode(m[1], mermaidShapeText(m[2]))
+			group = append(group, m[1])
+			stmt = stmt[len(m[0]):]
+			if !strings.HasPrefix(stmt, "&") {
+				break
+			}
+			stmt = strings.TrimSpace(stmt[1:])
+		}
+		for _, from := range prev {
+			for _, to := range group {
+				d.edges = append(d.edges, diagramEdge{from: from, to: to, label: arrow.label, dashed: arrow.dashed})
+			}
+		}
+		prev = group
+
+		e := mermaidEdgePattern.FindStringSubmatch(stmt)
+		if e == nil {
+			return
+		}
+		arrow = diagramEdge{label: strings.TrimSpace(e[1] + e[3]), dashed: strings.Contains(e[2], ".")}
+		stmt = stmt[len(e[0]):]
+	}
+}
+
+// Parts of a graphviz statement: a node id, quoted or not, an edge
+// operator, and a label attribute
+var (
+	dotIDPattern    = regexp.MustCompile(`^\s*("(?:[^"\\]|\\.)*"|[\w.]+)\s*`)
+	dotEdgePattern  = regexp.MustCompile(`^\s*(->|--)\s*`)
+	dotLabelPattern = regexp.MustCompile(`label\s*=\s*("(?:[^"\\]|\\.)*"|[\w.]+)`)
+)
+
+// parseDot reads the nodes and edges of a graphviz graph or digraph.
+// Subgraphs are flattened, and attributes other than labels are ignored.
+func parseDot(src string) diagram {
+	var d diagram
+	unquote := func(s string) string {
+		if strings.HasPrefix(s, `"`) {
+			s = strings.ReplaceAll(strings.Trim(s, `"`), `\"`, `"`)
+		}
+		return strings.ReplaceAll(s, `\n`, " ")
+	}
+
+	// Statements end at ; or a line break; braces only group them
+	src = strings.NewReplacer("{", "\n", "}", "\n").Replace(src)
+	for _, raw := range strings.Split(src, "\n") {
+		for _, stmt := range strings.Split(raw, ";") {
+			stmt = strings.TrimSpace(stmt)
+			if stmt == "" || strings.HasPrefix(stmt, "//") || strings.HasPrefix(stmt, "#") || strings.Contains(strings.SplitN(stmt, "[", 2)[0], "=") {
+				continue
+			}
+			switch strings.Fields(stmt)[0] {
+			case "graph", "digraph", "strict", "subgraph", "node", "edge":
+				continue
+			}
+
+			label := ""
+			attrs := ""
+			if i := strings.Index(stmt, "["); i >= 0 {
+				stmt, attrs = stmt[:i], stmt[i:]
+				if m := dotLabelPattern.FindStringSubmatch(attrs); m != nil {
+					label = unquote(m[1])
+				}
+			}
+			var ids []string
+			rest := stmt
+			for {
+				m := dotIDPattern.FindStringSubmatch(rest)
+				if m == nil {
+					break
+				}
+				ids = append(ids, unquote(m[1]))
+				rest = rest[len(m[0]):]
+				op := dotEdgePattern.FindString(rest)
+				if op == "" {
+					break
+				}
+				rest = rest[len(op):]
+			}
+			if len(ids) == 1 {
+				d.addNode(ids[0], label)
+				continue
+			}
+			for i := 1; i < len(ids); i++ {
+				d.addNode(ids[i-1], "")
+				d.addNode(ids[i], "")
+				d.edges = append(d.edges, diagramEdge{from: ids[i-1], to: ids[i], label: label})
+			}
+		}
+	}
+	return d
+}
+
+// drawDiagram draws a diagram block as text, or returns false when the
+// block is not a diagram it can read
+func drawDiagram(lang, src string) ([]string, bool) {
+	var d diagram
+	if strings.EqualFold(strings.TrimSpace(lang), "mermaid") {
+		var ok bool
+		if d, ok = parseMermaid(src); !ok {
+			return nil, false
+		}
+	} else {
+		d = parseDot(src)
+	}
+	if len(d.nodes) == 0 {
+		return nil, false
+	}
+	if d.sequence {
+		return d.drawSequence(), true
+	}
+	return d.drawTree(), true
+}
+
+// drawSequence lists the participants and then each message in order
+func (d diagram) drawSequence() []string {
+	var names []string
+	for _, id := range d.nodes {
+		names = append(names, d.labels[id])
+	}
+	lines := []string{strings.Join(names, " │ "), ""}
+	for i, e := range d.edges {
+		arrow := "→"
+		if e.dashed {
+			arrow = "⇢"
+		}
+		line := d.labels[e.from] + " " + arrow + " " + d.labels[e.to]
+		if e.label != "" {
+			line += ": " + e.label
+		}
+		lines = append(lines, strings.Repeat(" ", len(strconv.Itoa(len(d.edges)))-len(strconv.Itoa(i+1)))+strconv.Itoa(i+1)+". "+line)
+	}
+	return lines
+}
+
+// drawTree draws a graph as trees growing from the nodes nothing points
+// to. A node reached a second time is named with ↑ rather than drawn again,
+// which also stops cycles.
+func (d diagram) drawTree() []string {
+	out := map[string][]diagramEdge{}
+	in := map[string]bool{}
+	for _, e := range d.edges {
+		out[e.from] = append(out[e.from], e)
+		in[e.to] = true
+	}
+
+	var lines []string
+	drawn := map[string]bool{}
+	var draw func(id, prefix string)
+	draw = func(id, prefix string) {
+		drawn[id] = true
+		edges := out[id]
+		for i, e := range edges {
+			branch, indent := "├─", "│   "
+			if i == len(edges)-1 {
+				branch, indent = "└─", "    "
+			}
+			arrow := "→ "
+			if e.dashed {
+				arrow = "⇢ "
+			}
+			if e.label != "" {
+				arrow = " " + e.label + " " + arrow
+			}
+			if drawn[e.to] {
+				lines = append(lines, prefix+branch+arrow+d.labels[e.to]+" ↑")
+				continue
+			}
+			lines = append(lines, prefix+branch+arrow+d.labels[e.to])
+			draw(e.to, prefix+indent)
+		}
+	}
+
+	// Nodes nothing points to first, then whatever only cycles reach
+	for _, pass := range []bool{true, false} {
+		for _, id := range d.nodes {
+			if drawn[id] || (pass && in[id]) {
+				continue
+			}
+			if len(lines) > 0 {
+				lines = append(lines, "")
+			}
+			lines = append(lines, d.labels[id])
+			draw(id, "")
+		}
+	}
+	return lines
+}
+
+// renderDiagram renders the source lines of a diagram block for the
+// preview, falling back to the source itself for a diagram it cannot read
+func renderDiagram(lang string, src []string, width int) []string {
+	drawing, ok := drawDiagram(lang, strings.Join(src, "\n"))
+	if !ok {
+		lines := []string{previewDimStyle.Render(tr("Diagram not drawn, shown as written"))}
+		for _, line := range src {
+			lines = append(lines, truncateLine(line, width))
+		}
+		return lines
+	}
+	var lines []string
+	for _, line := range drawing {
+		lines = append(lines, previewCodeStyle.Render(truncateLine(line, width)))
+	}
+	return lines
+}
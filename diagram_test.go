@@ -0,0 +1,103 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseMermaid(t *testing.T) {
+	tests := []struct {
+		name  string
+		src   string
+		want  diagram
+		valid bool
+	}{
+		{
+			"flowchart with shapes and labels",
+			"graph TD\n  A[Start] --> B{Ok?}\n  B -->|yes| C\n  B -.-> D((End))",
+			diagram{
+				nodes:  []string{"A", "B", "C", "D"},
+				labels: map[string]string{"A": "Start", "B": "Ok?", "C": "C", "D": "End"},
+				edges:  []diagramEdge{{from: "A", to: "B"}, {from: "B", to: "C", label: "yes"}, {from: "B", to: "D", dashed: true}},
+			},
+			true,
+		},
+		{
+			"nodes joined with &",
+			"flowchart LR; a & b --> c",
+			diagram{
+				nodes:  []string{"a", "b", "c"},
+				labels: map[string]string{"a": "a", "b": "b", "c": "c"},
+				edges:  []diagramEdge{{from: "a", to: "c"}, {from: "b", to: "c"}},
+			},
+			true,
+		},
+		{
+			"styles skipped",
+			"graph\nclassDef x fill:#f00\nA --- B",
+			diagram{
+				nodes:  []string{"A", "B"},
+				labels: map[string]string{"A": "A", "B": "B"},
+				edges:  []diagramEdge{{from: "A", to: "B"}},
+			},
+			true,
+		},
+		{
+			"sequence diagram",
+			"sequenceDiagram\nparticipant A as Alice\nA->>B: hi\nB-->>A: back",
+			diagram{
+				nodes:    []string{"A", "B"},
+				labels:   map[string]string{"A": "Alice", "B": "B"},
+				edges:    []diagramEdge{{from: "A", to: "B", label: "hi"}, {from: "B", to: "A", label: "back", dashed: true}},
+				sequence: true,
+			},
+			true,
+		},
+		{"other kind of diagram", "pie\n\"a\": 1", diagram{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseMermaid(tt.src)
+			if ok != tt.valid {
+				t.Fatalf("parseMermaid reported %v, want %v", ok, tt.valid)
+			}
+			if ok && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseMermaid = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseDot(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want diagram
+	}{
+		{
+			"chain with a node label",
+			`digraph { a -> b -> c; b [label="Bee"] }`,
+			diagram{
+				nodes:  []string{"a", "b", "c"},
+				labels: map[string]string{"a": "a", "b": "Bee", "c": "c"},
+				edges:  []diagramEdge{{from: "a", to: "b"}, {from: "b", to: "c"}},
+			},
+		},
+		{
+			"quoted ids, edge labels and attributes",
+			`graph g { "x y" -- z [label=e1]; rankdir=LR; node [shape=box] }`,
+			diagram{
+				nodes:  []string{"x y", "z"},
+				labels: map[string]string{"x y": "x y", "z": "z"},
+				edges:  []diagramEdge{{from: "x y", to: "z", label: "e1"}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseDot(tt.src); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseDot = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
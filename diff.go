@@ -0,0 +1,173 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Diff styling
+var (
+	diffAddStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("42"))
+	diffRemoveStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("203"))
+	diffSameStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("245"))
+)
+
+// Unchanged lines kept around each change; longer unchanged runs are folded
+const diffContext = 3
+
+// Largest product of the two line counts compared, bounding the LCS table
+const maxDiffCells = 4_000_000
+
+// diffOp is one line of a line-based diff
+type diffOp struct {
+	kind byte // ' ' unchanged, '-' only in the first text, '+' only in the second
+	text string
+}
+
+// diffLines compares two texts line by line using their longest common
+// subsequence
+func diffLines(a, b []string) []diffOp {
+	// lcs[i][j] is the LCS length of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var ops []diffOp
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, diffOp{' ', a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, diffOp{'-', a[i]})
+			i++
+		default:
+			ops = append(ops, diffOp{'+', b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		ops = append(ops, diffOp{'-', a[i]})
+	}
+	for ; j < len(b); j++ {
+		ops = append(ops, diffOp{'+', b[j]})
+	}
+	return ops
+}
+
+// renderDiff colours a diff for the given width, folding unchanged
+// stretches away from changes
+func renderDiff(ops []diffOp, width int) string {
+	// Mark the unchanged lines close enough to a change to be shown
+	show := make([]bool, len(ops))
+	for i, op := range ops {
+		if op.kind == ' ' {
+			continue
+		}
+		for k := max(0, i-diffContext); k <= min(len(ops)-1, i+diffContext); k++ {
+			show[k] = true
+		}
+	}
+
+	var out []string
+	for i := 0; i < len(ops); i++ {
+		if !show[i] {
+			start := i
+			for i+1 < len(ops) && !show[i+1] {
+				i++
+			}
+			out = append(out, diffSameStyle.Render("  ⋯ "+trf("%d unchanged lines", i-start+1)))
+			continue
+		}
+		op := ops[i]
+		line := truncateLine(string(op.kind)+" "+op.text, width)
+		switch op.kind {
+		case '-':
+			out = append(out, diffRemoveStyle.Render(line))
+		case '+':
+			out = append(out, diffAddStyle.Render(line))
+		default:
+			out = append(out, diffSameStyle.Render(line))
+		}
+	}
+	if len(out) == 0 {
+		out = append(out, diffSameStyle.Render("  "+tr("The notes are identical")))
+	}
+	return strings.Join(out, "\n")
+}
+
+// markForCompare marks the selected note; marking a second note shows the
+// differences between the two
+func (m model) markForCompare() model {
+	if m.marked == nil || m.marked.path == m.selectedNote.path {
+		n := *m.selectedNote
+		m.marked = &n
+		m.status = trf("Marked %s; mark another note to compare", n.title)
+		return m
+	}
+
+	m = m.showDiff(*m.marked, *m.selectedNote)
+	m.marked = nil
+	return m
+}
+
+// showDiff shows the differences between two notes full screen
+func (m model) showDiff(first, second note) model {
+	a, err := readNoteFile(first.path)
+	if err == nil {
+		var b []byte
+		if b, err = readNoteFile(second.path); err == nil {
+			aLines, bLines := strings.Split(string(a), "\n"), strings.Split(string(b), "\n")
+			if len(aLines)*len(bLines) > maxDiffCells {
+				m.status = tr("Notes are too long to compare")
+				return m
+			}
+			ops := diffLines(aLines, bLines)
+			m.diffTitle = diffRemoveStyle.Bold(true).Render("- "+first.title) + "   " +
+				diffAddStyle.Bold(true).Render("+ "+second.title)
+			m.diff = viewport.New(m.width-10, m.height-9)
+			m.diff.SetContent(renderDiff(ops, m.width-10))
+			m.mode = "diff"
+		}
+	}
+	if err != nil {
+		m.status = err.Error()
+	}
+	return m
+}
+
+// updateDiff scrolls the diff and closes it on esc
+func (m model) updateDiff(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if msg.Type == tea.KeyEsc || msg.String() == "q" {
+		m.mode = "list"
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.diff, cmd = m.diff.Update(msg)
+	return m, cmd
+}
+
+// diffView shows the diff full screen
+func (m model) diffView() string {
+	return docStyle.Render(lipgloss.JoinVertical(lipgloss.Top,
+		splitStyle.Width(m.width-8).Height(m.height-6).Render(
+			lipgloss.JoinVertical(lipgloss.Left, m.diffTitle, "", m.diff.View()),
+		),
+		helpStyle.Render(tr("↑/↓/pgup/pgdn:Scroll | esc:Back")),
+	))
+}
@@ -0,0 +1,379 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// problem is something wrong with the vault found by gleaner doctor
+type problem struct {
+	Check   string `json:"check"` // Which check found it
+	Path    string `json:"path"`
+	Message string `json:"message"`
+	Fixed   bool   `json:"fixed"`
+}
+
+// checkFiles walks the vault, outside hidden folders, for links that point
+// nowhere and files or folders the user cannot read or write. With fix,
+// dangling links are removed and the user's own files made readable and
+// writable again.
+func checkFiles(fix bool) []problem {
+	var problems []problem
+	filepath.WalkDir(notesDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// A folder's mode may already have been reported
+			if len(problems) == 0 || problems[len(problems)-1].Path != path {
+				problems = append(problems, problem{Check: "permissions", Path: path, Message: err.Error()})
+			}
+			if d != nil && d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if path != notesDir && strings.HasPrefix(d.Name(), ".") {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if d.Type()&fs.ModeSymlink != 0 {
+			if _, err := os.Stat(path); err != nil {
+				p := problem{Check: "symlinks", Path: path, Message: "link points to a missing file"}
+				if fix && !readOnly {
+					p.Fixed = os.Remove(path) == nil
+				}
+				problems = append(problems, p)
+			}
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		need := fs.FileMode(0600)
+		if d.IsDir() {
+			need = 0700
+		}
+		if d.IsDir() || isNoteExt(filepath.Ext(path)) {
+			if info.Mode().Perm()&need != need {
+				p := problem{Check: "permissions", Path: path, Message: fmt.Sprintf("mode %s lacks owner read or write access", info.Mode().Perm())}
+				if fix && !readOnly {
+					p.Fixed = os.Chmod(path, info.Mode().Perm()|need) == nil
+				}
+				problems = append(problems, p)
+			}
+		}
+		return nil
+	})
+	return problems
+}
+
+// checkFrontmatter finds frontmatter blocks that never end, and lines in
+// them that are not `key: value` pairs or list items. The indented lines of
+// a block scalar (`key: |` or `key: >`) are its value.
+func checkFrontmatter(n note, content string) []problem {
+	normalized := strings.ReplaceAll(content, "\r\n", "\n")
+	if !strings.HasPrefix(normalized, "---\n") {
+		return nil
+	}
+	rest := normalized[len("---\n"):]
+	end := strings.Index(rest, "\n---")
+	if end < 0 && !strings.HasPrefix(rest, "---") {
+		return []problem{{Check: "frontmatter", Path: n.path, Message: "frontmatter block is never closed with ---"}}
+	}
+	if end < 0 {
+		return nil
+	}
+
+	var problems []problem
+	inBlock := false
+	for i, line := range strings.Split(rest[:end], "\n") {
+		trimmed := strings.TrimSpace(line)
+		if inBlock && (trimmed == "" || strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			continue
+		}
+		inBlock = false
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, "- ") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok || strings.TrimSpace(key) == "" {
+			problems = append(problems, problem{Check: "frontmatter", Path: n.path, Message: fmt.Sprintf("line %d is not a key: value pair", i+2)})
+			continue
+		}
+		inBlock = blockScalarPattern.MatchString(value)
+	}
+	return problems
+}
+
+// Matches the value of a key starting a YAML block scalar, such as | or >-
+var blockScalarPattern = regexp.MustCompile(`^\s*[|>][+-]?[1-9]?[+-]?\s*(#.*)?$`)
+
+// checkCollisions finds notes sharing an id, which makes `gleaner open` and
+// gleaner:// links ambiguous, and notes sharing a title. With fix, all but
+// the first note with an id are renamed to the next free id.
+func checkCollisions(notes []note, fix bool) []problem {
+	byID := make(map[int64][]note)
+	byTitle := make(map[string][]note)
+	for _, n := range notes {
+		byID[n.createdAt] = append(byID[n.createdAt], n)
+		byTitle[strings.ToLower(n.title)] = append(byTitle[strings.ToLower(n.title)], n)
+	}
+
+	var problems []problem
+	ids := make([]int64, 0, len(byID))
+	for id := range byID {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	for _, id := range ids {
+		for _, n := range byID[id][1:] {
+			p := problem{Check: "collisions", Path: n.path, Message: fmt.Sprintf("id %d is also used by %s", id, filepath.Base(byID[id][0].path))}
+			if fix && !readOnly {
+				p.Fixed = renumberNote(n, byID) == nil
+			}
+			problems = append(problems, p)
+		}
+	}
+	titles := make([]string, 0, len(byTitle))
+	for title := range byTitle {
+		titles = append(titles, title)
+	}
+	sort.Strings(titles)
+	for _, title := range titles {
+		same := byTitle[title]
+		for _, n := range same[1:] {
+			problems = append(problems, problem{Check: "collisions", Path: n.path, Message: fmt.Sprintf("title %q is also used by %s", n.title, filepath.Base(same[0].path))})
+		}
+	}
+	return problems
+}
+
+// renumberNote gives a note the next id no other note uses, renaming its
+// file to match. What is known to be the note's follows it to the new id:
+// an id in its frontmatter, links in it to itself, and the schedules and
+// scores of cards and sections the note with the old id does not also
+// have. Links from other notes led to that note before and still do.
+func renumberNote(n note, byID map[int64][]note) error {
+	id := n.createdAt + 1
+	for len(byID[id]) > 0 {
+		id++
+	}
+	oldID, newID := strconv.FormatInt(n.createdAt, 10), strconv.FormatInt(id, 10)
+	content, err := readNoteFile(n.path)
+	if err != nil {
+		return err
+	}
+	_, rest, _ := strings.Cut(filepath.Base(n.path), "-")
+	dst := filepath.Join(filepath.Dir(n.path), newID+"-"+rest)
+	if err := os.Rename(n.path, dst); err != nil {
+		return err
+	}
+	byID[id] = []note{n}
+	recordChange(auditRename, dst, string(content), string(content))
+
+	updated := string(content)
+	if fields, _ := parseFrontmatter(updated); metaValue(fields, "id") == oldID {
+		updated = setFrontmatter(updated, "id", newID)
+	}
+	selfLink := regexp.MustCompile(regexp.QuoteMeta(noteURIScheme+"://note/"+oldID) + `\b`)
+	updated = selfLink.ReplaceAllString(updated, noteURIScheme+"://note/"+newID)
+	if updated != string(content) {
+		if err := writeNoteFile(dst, updated); err != nil {
+			return err
+		}
+	}
+
+	kept := byID[n.createdAt][0]
+	renamed := func(mine, theirs []string) map[string]string {
+		moved := map[string]string{}
+		for _, key := range mine {
+			if !slices.Contains(theirs, key) {
+				moved[key] = newID + strings.TrimPrefix(key, oldID)
+			}
+		}
+		return moved
+	}
+	var cards, keptCards, sections, keptSections []string
+	for _, c := range noteCards(n) {
+		cards = append(cards, c.id)
+	}
+	for _, c := range noteCards(kept) {
+		keptCards = append(keptCards, c.id)
+	}
+	for _, q := range quizSections(n, n.body) {
+		sections = append(sections, q.id)
+	}
+	for _, q := range quizSections(kept, kept.body) {
+		keptSections = append(keptSections, q.id)
+	}
+
+	reviewLogMu.Lock()
+	reviews := loadReviewLog()
+	if moveState(reviews[notesDir], renamed(cards, keptCards)) {
+		err = writeState(reviewLogPath(), reviews)
+	}
+	reviewLogMu.Unlock()
+	if err != nil {
+		return err
+	}
+	quizLogMu.Lock()
+	defer quizLogMu.Unlock()
+	quizzes := loadQuizLog()
+	if moveState(quizzes[notesDir], renamed(sections, keptSections)) {
+		return writeState(quizLogPath(), quizzes)
+	}
+	return nil
+}
+
+// moveState moves per-note state from old keys to new ones, reporting
+// whether any moved
+func moveState[V any](state map[string]V, keys map[string]string) bool {
+	moved := false
+	for from, to := range keys {
+		if v, ok := state[from]; ok {
+			state[to] = v
+			delete(state, from)
+			moved = true
+		}
+	}
+	return moved
+}
+
+// staleKeys returns the keys of per-note state, "<id>" or "<id>/<part>",
+// whose note is gone
+func staleKeys[V any](state map[string]V, notes []note) []string {
+	ids := make(map[string]bool)
+	for _, n := range notes {
+		ids[strconv.FormatInt(n.createdAt, 10)] = true
+	}
+	var stale []string
+	for key := range state {
+		if id, _, _ := strings.Cut(key, "/"); !ids[id] {
+			stale = append(stale, key)
+		}
+	}
+	sort.Strings(stale)
+	return stale
+}
+
+// checkStaleState finds flashcard schedules and quiz scores kept for notes
+// that no longer exist. With fix, they are dropped.
+func checkStaleState(notes []note, fix bool) []problem {
+	var problems []problem
+
+	reviewLogMu.Lock()
+	reviews := loadReviewLog()
+	stale := staleKeys(reviews[notesDir], notes)
+	for _, key := range stale {
+		delete(reviews[notesDir], key)
+	}
+	fixed := len(stale) > 0 && fix && writeState(reviewLogPath(), reviews) == nil
+	reviewLogMu.Unlock()
+	for _, key := range stale {
+		problems = append(problems, problem{Check: "stale", Path: reviewLogPath(), Message: fmt.Sprintf("schedule for card %s of a deleted note", key), Fixed: fixed})
+	}
+
+	quizLogMu.Lock()
+	quizzes := loadQuizLog()
+	stale = staleKeys(quizzes[notesDir], notes)
+	for _, key := range stale {
+		delete(quizzes[notesDir], key)
+	}
+	fixed = len(stale) > 0 && fix && writeState(quizLogPath(), quizzes) == nil
+	quizLogMu.Unlock()
+	for _, key := range stale {
+		problems = append(problems, problem{Check: "stale", Path: quizLogPath(), Message: fmt.Sprintf("quiz score for section %s of a deleted note", key), Fixed: fixed})
+	}
+	return problems
+}
+
+// runDoctor implements `gleaner doctor [--fix] [--json]`, checking the vault
+// for unreadable notes, malformed frontmatter, clashing ids and titles,
+// dangling symlinks, permission problems and state kept for deleted notes.
+// It fails when a problem is left unfixed.
+func runDoctor(args []string) error {
+	flags := flag.NewFlagSet("doctor", flag.ContinueOnError)
+	fix := flags.Bool("fix", false, "repair what can be repaired safely")
+	asJSON := flags.Bool("json", false, "print the problems as JSON")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() > 0 {
+		return errors.New("usage: gleaner doctor [--fix] [--json]")
+	}
+	if *fix && readOnly {
+		return errReadOnly
+	}
+
+	// Unreadable folders are reported by checkFiles, so their errors are
+	// left out here
+	notes, err := scanNotes()
+	if err != nil && len(notes) == 0 && !errors.Is(err, fs.ErrPermission) {
+		return err
+	}
+
+	problems := checkFiles(*fix)
+	for _, n := range notes {
+		content, err := readNoteText(n.path)
+		if err != nil {
+			problems = append(problems, problem{Check: "unreadable", Path: n.path, Message: err.Error()})
+			continue
+		}
+		problems = append(problems, checkFrontmatter(n, content)...)
+	}
+	collisions := checkCollisions(notes, *fix)
+	problems = append(problems, collisions...)
+
+	// Renumbered notes took their state to their new ids, so state is
+	// checked against the notes as they are now
+	current := notes
+	if slices.ContainsFunc(collisions, func(p problem) bool { return p.Fixed }) {
+		if rescanned, err := scanNotes(); err == nil {
+			current = rescanned
+		}
+	}
+	problems = append(problems, checkStaleState(current, *fix)...)
+
+	unfixed := 0
+	for _, p := range problems {
+		if !p.Fixed {
+			unfixed++
+		}
+	}
+	if *asJSON {
+		if problems == nil {
+			problems = []problem{}
+		}
+		data, err := json.MarshalIndent(problems, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+	} else {
+		for _, p := range problems {
+			status := ""
+			if p.Fixed {
+				status = " (fixed)"
+			}
+			fmt.Printf("%s: %s: %s%s\n", p.Check, p.Path, p.Message, status)
+		}
+		fmt.Printf("Checked %d notes: %d problems, %d fixed\n", len(notes), len(problems), len(problems)-unfixed)
+	}
+	if unfixed > 0 {
+		return fmt.Errorf("%d problems need attention", unfixed)
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestCheckFrontmatter(t *testing.T) {
+	tests := []struct {
+		name, content string
+		want          []string
+	}{
+		{"none", "just text\n", nil},
+		{"well formed", "---\ntitle: Plan\ntags:\n  - a\n# comment\n---\nbody\n", nil},
+		{"windows line endings", "---\r\ntitle: Plan\r\n---\r\nbody\r\n", nil},
+		{"empty block", "---\n---\nbody\n", nil},
+		{"never closed", "---\ntitle: Plan\nbody\n", []string{"frontmatter block is never closed with ---"}},
+		{"not a pair", "---\ntitle: Plan\njust words\n: no key\n---\n", []string{"line 3 is not a key: value pair", "line 4 is not a key: value pair"}},
+		{"block scalar", "---\nsummary: |\n  first line\n\n  second: line\nafter\n---\n", []string{"line 6 is not a key: value pair"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, p := range checkFrontmatter(note{path: "n.md"}, tt.content) {
+				got = append(got, p.Message)
+			}
+			if strings.Join(got, "; ") != strings.Join(tt.want, "; ") {
+				t.Errorf("problems %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckCollisions(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_DATA_HOME", filepath.Join(home, ".local", "share"))
+	oldDir, oldCfg := notesDir, cfg
+	t.Cleanup(func() { notesDir, cfg = oldDir, oldCfg })
+	notesDir, cfg = t.TempDir(), config{}
+	for name, content := range map[string]string{
+		"1700000000-Plan.md":          "first\n",
+		"projects/1700000000-Site.md": "---\nid: 1700000000\n---\nsee gleaner://note/1700000000\n",
+		"1700000001-plan.md":          "same title\n",
+	} {
+		path := filepath.Join(notesDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	notes, err := scanNotes()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	problems := checkCollisions(notes, true)
+	if len(problems) != 2 {
+		t.Fatalf("problems %+v, want 2", problems)
+	}
+	if p := problems[0]; !p.Fixed || filepath.Base(p.Path) != "1700000000-Site.md" || p.Message != "id 1700000000 is also used by 1700000000-Plan.md" {
+		t.Errorf("id problem %+v", p)
+	}
+	if p := problems[1]; p.Fixed || !strings.Contains(p.Message, `title "plan" is also used by`) {
+		t.Errorf("title problem %+v", p)
+	}
+	data, err := os.ReadFile(filepath.Join(notesDir, "projects", "1700000002-Site.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "---\nid: 1700000002\n---\nsee gleaner://note/1700000002\n"; string(data) != want {
+		t.Errorf("renumbered note is %q, want %q", data, want)
+	}
+}
+
+func TestCheckFiles(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("symlinks and modes differ on Windows")
+	}
+	oldDir, oldCfg := notesDir, cfg
+	t.Cleanup(func() { notesDir, cfg = oldDir, oldCfg })
+	notesDir, cfg = t.TempDir(), config{}
+	locked := filepath.Join(notesDir, "1700000000-Locked.md")
+	if err := os.WriteFile(locked, []byte("x"), 0400); err != nil {
+		t.Fatal(err)
+	}
+	dangling := filepath.Join(notesDir, "gone.md")
+	if err := os.Symlink(filepath.Join(notesDir, "missing.md"), dangling); err != nil {
+		t.Fatal(err)
+	}
+
+	problems := checkFiles(true)
+	if len(problems) != 2 {
+		t.Fatalf("problems %+v, want 2", problems)
+	}
+	for _, p := range problems {
+		if !p.Fixed {
+			t.Errorf("not fixed: %+v", p)
+		}
+	}
+	if info, err := os.Stat(locked); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("locked note left with %v, %v", info.Mode().Perm(), err)
+	}
+	if _, err := os.Lstat(dangling); !os.IsNotExist(err) {
+		t.Errorf("dangling link kept: %v", err)
+	}
+	if problems := checkFiles(false); len(problems) != 0 {
+		t.Errorf("problems after fixing: %+v", problems)
+	}
+}
@@ -0,0 +1,231 @@
+package main
+
+import (
+	"crypto/sha256"
+	"errors"
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Share of word triples two notes must have in common to count as near
+// duplicates
+const defaultDuplicateThreshold = 0.8
+
+// duplicatePair is two notes with the same or nearly the same text
+type duplicatePair struct {
+	a, b       note
+	similarity float64 // 1 for identical text
+	exact      bool
+}
+
+// shingles returns the set of three-word runs in text, ignoring case and
+// punctuation; texts shorter than that give their words as one run
+func shingles(text string) map[string]bool {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !(r == '\'' || r == '-' || r == '_' || 'a' <= r && r <= 'z' || '0' <= r && r <= '9' || r > 127)
+	})
+	set := make(map[string]bool)
+	if len(words) < 3 {
+		if len(words) > 0 {
+			set[strings.Join(words, " ")] = true
+		}
+		return set
+	}
+	for i := 0; i+3 <= len(words); i++ {
+		set[strings.Join(words[i:i+3], " ")] = true
+	}
+	return set
+}
+
+// jaccard returns the share of two sets' members that they have in common
+func jaccard(a, b map[string]bool) float64 {
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+	common := 0
+	for k := range a {
+		if b[k] {
+			common++
+		}
+	}
+	return float64(common) / float64(len(a)+len(b)-common)
+}
+
+// findDuplicates pairs up the notes whose bodies are identical, or share at
+// least threshold of their word triples, most similar first. Empty notes
+// are left out.
+func findDuplicates(notes []note, threshold float64) []duplicatePair {
+	type candidate struct {
+		n    note
+		hash [32]byte
+		set  map[string]bool
+	}
+	var candidates []candidate
+	for _, n := range notes {
+		body := strings.TrimSpace(n.body)
+		if body == "" {
+			continue
+		}
+		candidates = append(candidates, candidate{n, sha256.Sum256([]byte(body)), shingles(body)})
+	}
+	// Sorted by size, a note can only be similar enough to the notes that
+	// follow it until their sizes drift too far apart
+	sort.Slice(candidates, func(i, j int) bool { return len(candidates[i].set) < len(candidates[j].set) })
+
+	var pairs []duplicatePair
+	for i, a := range candidates {
+		for _, b := range candidates[i+1:] {
+			if a.hash == b.hash {
+				pairs = append(pairs, duplicatePair{a.n, b.n, 1, true})
+				continue
+			}
+			if float64(len(a.set)) < threshold*float64(len(b.set)) {
+				break
+			}
+			if similarity := jaccard(a.set, b.set); similarity >= threshold {
+				pairs = append(pairs, duplicatePair{a.n, b.n, similarity, false})
+			}
+		}
+	}
+	sort.SliceStable(pairs, func(i, j int) bool { return pairs[i].similarity > pairs[j].similarity })
+	return pairs
+}
+
+// similarityText describes how alike a pair is
+func similarityText(p duplicatePair) string {
+	if p.exact {
+		return tr("Identical")
+	}
+	return trf("%d%% similar", int(p.similarity*100))
+}
+
+// openDuplicates lists the pairs of duplicate notes among every note, not
+// only those the list is filtered to; choosing a pair offers what to do
+// with it
+func (m model) openDuplicates() model {
+	notes, err := scanNotes()
+	if err != nil && len(notes) == 0 {
+		m.status = err.Error()
+		return m
+	}
+	pairs := findDuplicates(notes, defaultDuplicateThreshold)
+	if len(pairs) == 0 {
+		m.status = tr("No duplicate notes found")
+		return m
+	}
+	items := make([]pickerItem, len(pairs))
+	for i, p := range pairs {
+		items[i] = pickerItem{
+			title: p.a.title + " ⇄ " + p.b.title,
+			desc:  similarityText(p),
+			value: p.a.path + "\n" + p.b.path,
+		}
+	}
+	return m.openPicker("duplicates", trf("%d possible duplicates", len(pairs)), items)
+}
+
+// duplicateChosen offers to compare, merge or delete the notes of a pair
+func (m model) duplicateChosen(value string) model {
+	a, b, ok := m.duplicateNotes(value)
+	if !ok {
+		return m
+	}
+	items := []pickerItem{{title: tr("Compare"), desc: a.title + " ⇄ " + b.title, value: "compare\n" + value}}
+	if !readOnly {
+		items = append(items,
+			pickerItem{title: trf("Merge into %s", a.title), desc: trf("Append %s and delete it", b.title), value: "merge\n" + a.path + "\n" + b.path},
+			pickerItem{title: trf("Merge into %s", b.title), desc: trf("Append %s and delete it", a.title), value: "merge\n" + b.path + "\n" + a.path},
+			pickerItem{title: trf("Delete %s", a.title), desc: a.path, value: "delete\n" + a.path},
+			pickerItem{title: trf("Delete %s", b.title), desc: b.path, value: "delete\n" + b.path},
+		)
+	}
+	return m.openPicker("duplicate-action", a.title+" ⇄ "+b.title, items)
+}
+
+// duplicateNotes finds the two notes of a pair by their paths
+func (m model) duplicateNotes(value string) (note, note, bool) {
+	first, second, _ := strings.Cut(value, "\n")
+	notes, _ := scanNotes()
+	var a, b *note
+	for i := range notes {
+		switch notes[i].path {
+		case first:
+			a = &notes[i]
+		case second:
+			b = &notes[i]
+		}
+	}
+	if a == nil || b == nil {
+		return note{}, note{}, false
+	}
+	return *a, *b, true
+}
+
+// duplicateAction compares, merges or deletes duplicates as chosen
+func (m model) duplicateAction(value string) (tea.Model, tea.Cmd) {
+	action, paths, _ := strings.Cut(value, "\n")
+	switch action {
+	case "compare":
+		if a, b, ok := m.duplicateNotes(paths); ok {
+			return m.showDiff(a, b), nil
+		}
+	case "merge":
+		into, from, _ := strings.Cut(paths, "\n")
+		if err := mergeNotes(into, from); err != nil {
+			m.status = err.Error()
+			return m, nil
+		}
+		m.status = tr("Merged")
+		return m, tea.Batch(deleteNote(from), loadNotes)
+	case "delete":
+		return m, tea.Batch(deleteNote(paths), loadNotes)
+	}
+	return m, nil
+}
+
+// mergeNotes appends the body of one note to another, leaving the first
+// note's frontmatter as it was
+func mergeNotes(into, from string) error {
+	if readOnly {
+		return errReadOnly
+	}
+	target, err := readNoteFile(into)
+	if err != nil {
+		return err
+	}
+	source, err := readNoteFile(from)
+	if err != nil {
+		return err
+	}
+	_, body := parseFrontmatter(string(source))
+	content := strings.TrimRight(string(target), "\n") + "\n\n" + strings.TrimLeft(body, "\n")
+	return writeNoteFile(into, content)
+}
+
+// runDuplicates implements `gleaner duplicates [--threshold 0.8]`, printing
+// each pair of identical or nearly identical notes
+func runDuplicates(args []string) error {
+	flags := flag.NewFlagSet("duplicates", flag.ContinueOnError)
+	threshold := flags.Float64("threshold", defaultDuplicateThreshold, "share of word triples two notes must have in common, from 0 to 1")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() > 0 || *threshold <= 0 || *threshold > 1 {
+		return errors.New("usage: gleaner duplicates [--threshold 0.8]")
+	}
+
+	notes, err := notesForCommand()
+	if err != nil {
+		return err
+	}
+	pairs := findDuplicates(notes, *threshold)
+	for _, p := range pairs {
+		fmt.Printf("%-14s %s\n%-14s %s\n\n", similarityText(p), p.a.path, "", p.b.path)
+	}
+	fmt.Printf("%d possible duplicates\n", len(pairs))
+	return nil
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFindDuplicates(t *testing.T) {
+	notes := []note{
+		{title: "A", body: "the quick brown fox jumps over the lazy dog"},
+		{title: "B", body: "  the quick brown fox jumps over the lazy dog\n"},
+		{title: "C", body: "The quick brown fox jumps over the lazy cat"},
+		{title: "D", body: "something else entirely, about cooking rice"},
+		{title: "E", body: ""},
+		{title: "F", body: "\n"},
+	}
+	pairs := findDuplicates(notes, 0.7)
+	want := []struct {
+		a, b  string
+		exact bool
+	}{
+		{"A", "B", true},
+		{"A", "C", false},
+		{"B", "C", false},
+	}
+	if len(pairs) != len(want) {
+		t.Fatalf("got %d pairs, want %d: %+v", len(pairs), len(want), pairs)
+	}
+	for i, w := range want {
+		p := pairs[i]
+		a, b := p.a.title, p.b.title
+		if a > b {
+			a, b = b, a
+		}
+		if a != w.a || b != w.b || p.exact != w.exact {
+			t.Errorf("pair %d = %s/%s exact %v, want %s/%s exact %v", i, a, b, p.exact, w.a, w.b, w.exact)
+		}
+	}
+	if pairs[1].similarity < 0.7 || pairs[1].similarity >= 1 {
+		t.Errorf("near duplicates are %v similar", pairs[1].similarity)
+	}
+
+	if pairs := findDuplicates(notes, 0.9); len(pairs) != 1 {
+		t.Errorf("got %d pairs at a 0.9 threshold, want only the identical pair", len(pairs))
+	}
+}
+
+func TestShingles(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"", nil},
+		{"One, two!", []string{"one two"}},
+		{"it's a well-known fact", []string{"it's a well-known", "a well-known fact"}},
+		{"a b a b a", []string{"a b a", "b a b"}},
+	}
+	for _, tt := range tests {
+		got := shingles(tt.text)
+		if len(got) != len(tt.want) {
+			t.Errorf("shingles(%q) = %v, want %v", tt.text, got, tt.want)
+			continue
+		}
+		for _, w := range tt.want {
+			if !got[w] {
+				t.Errorf("shingles(%q) = %v, missing %q", tt.text, got, w)
+			}
+		}
+	}
+}
+
+func TestOpenDuplicatesLooksPastTheFilter(t *testing.T) {
+	old := notesDir
+	notesDir = t.TempDir()
+	t.Cleanup(func() { notesDir = old })
+	for _, name := range []string{"1700000000-One.md", "1700000001-Two.md"} {
+		if err := os.WriteFile(filepath.Join(notesDir, name), []byte("same text\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The list is filtered down to nothing
+	m := model{mode: "list"}.openDuplicates()
+	if m.mode != "pick" || len(m.picker.Items()) != 1 {
+		t.Fatalf("mode %q with %d pairs, status %q", m.mode, len(m.picker.Items()), m.status)
+	}
+	value := m.picker.Items()[0].(pickerItem).value
+	a, b, ok := m.duplicateNotes(value)
+	if !ok || a.title == b.title {
+		t.Errorf("pair %q found %v: %q and %q", value, ok, a.title, b.title)
+	}
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Tab widths cycled through at runtime
+var tabWidths = []int{2, 4, 8}
+
+// Default number of spaces inserted for a tab
+const defaultTabWidth = 4
+
+// configureEditor applies editor settings from the config file
+func (m model) configureEditor() model {
+	m.textarea.ShowLineNumbers = cfg.LineNumbers
+	m.wrap = !cfg.NoWrap
+	m.tabWidth = cfg.TabWidth
+	if m.tabWidth <= 0 {
+		m.tabWidth = defaultTabWidth
+	}
+	return m
+}
+
+// toggleLineNumbers shows or hides line numbers in the editor
+func (m model) toggleLineNumbers() model {
+	m.textarea.ShowLineNumbers = !m.textarea.ShowLineNumbers
+	m = m.resize()
+	m.status = trf("Line numbers %s", onOff(m.textarea.ShowLineNumbers))
+	return m
+}
+
+// toggleWrap switches soft wrapping of long lines while reading
+func (m model) toggleWrap() model {
+	m.wrap = !m.wrap
+	m.status = trf("Soft wrap %s", onOff(m.wrap))
+	return m
+}
+
+// cycleTabWidth moves to the next tab width
+func (m model) cycleTabWidth() model {
+	next := tabWidths[0]
+	for i, w := range tabWidths {
+		if w == m.tabWidth && i+1 < len(tabWidths) {
+			next = tabWidths[i+1]
+		}
+	}
+	m.tabWidth = next
+	m.status = trf("Tab width %d", m.tabWidth)
+	return m
+}
+
+// insertTab indents at the cursor with spaces
+func (m model) insertTab() model {
+	m.textarea.InsertString(strings.Repeat(" ", m.tabWidth))
+	return m
+}
+
+// unwrappedView renders the note with long lines cut at the pane edge
+// instead of wrapped, keeping the cursor line in view
+func (m model) unwrappedView() string {
+	lines := strings.Split(m.textarea.Value(), "\n")
+	height := m.textarea.Height()
+	start := max(0, min(m.textarea.Line()-height+1, len(lines)-height))
+	end := min(len(lines), start+height)
+
+	width := m.textarea.Width()
+	visible := make([]string, 0, height)
+	for i := start; i < end; i++ {
+		prefix := m.textarea.Prompt
+		if m.textarea.ShowLineNumbers {
+			prefix += fmt.Sprintf("%3d ", i+1)
+		}
+		visible = append(visible, prefix+ansi.Truncate(lines[i], width, "…"))
+	}
+	for len(visible) < height {
+		visible = append(visible, m.textarea.Prompt)
+	}
+	return strings.Join(visible, "\n")
+}
+
+// onOff describes a boolean setting
+func onOff(on bool) string {
+	if on {
+		return tr("on")
+	}
+	return tr("off")
+}
+
+// cursorPos returns the logical row and rune column of the editor cursor
+func cursorPos(ta textarea.Model) (int, int) {
+	info := ta.LineInfo()
+	return ta.Line(), info.StartColumn + info.ColumnOffset
+}
+
+// moveCursor places the editor cursor at a logical row and rune column
+func moveCursor(ta *textarea.Model, row, col int) {
+	// Each step moves at least one visual line, so the line count bounds the loop
+	for i := 0; ta.Line() > row && i < ta.LineCount()*ta.Height()+ta.Length(); i++ {
+		ta.CursorUp()
+	}
+	for i := 0; ta.Line() < row && i < ta.LineCount()*ta.Height()+ta.Length(); i++ {
+		ta.CursorDown()
+	}
+	ta.SetCursor(col)
+}
+
+// replaceValue swaps the editor content while keeping the cursor in place
+func replaceValue(ta *textarea.Model, value string, row, col int) {
+	ta.SetValue(value)
+	moveCursor(ta, row, col)
+}
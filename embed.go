@@ -0,0 +1,44 @@
+package main
+
+import (
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Matches a line holding only a ![[Title]] embed, capturing the title.
+// A heading anchor or label after the title is ignored.
+var embedPattern = regexp.MustCompile(`^\s*!\[\[([^\]|#]+)(?:#[^\]|]*)?(?:\|[^\]]*)?\]\]\s*$`)
+
+// Embeds inside embedded notes are followed this many levels deep
+const maxEmbedDepth = 3
+
+// Notes embeds are looked up among, kept up to date as the notes load
+var embedNotes []note
+
+// renderEmbed renders the note a ![[Title]] line embeds, indented under its
+// title. embedding holds the paths of the notes already being embedded
+// around it, so a note that embeds itself, directly or through others, is
+// shown once and then only named.
+func renderEmbed(target string, width int, embedding []string) []string {
+	target = strings.TrimSpace(target)
+	n, err := findNote(embedNotes, target)
+	switch {
+	case err != nil:
+		return []string{previewDimStyle.Render("⚠ " + trf("No note to embed: %s", target))}
+	case slices.Contains(embedding, n.path):
+		return []string{previewDimStyle.Render("↻ " + trf("%s embeds itself", n.title))}
+	case len(embedding) >= maxEmbedDepth:
+		return []string{previewDimStyle.Render("⋯ " + trf("%s is embedded too deeply to show", n.title))}
+	case n.encrypted:
+		return []string{previewDimStyle.Render("🔒 " + trf("%s is encrypted", n.title))}
+	}
+
+	doc := renderMarkdown(n.body, filepath.Dir(n.path), width-2, append(slices.Clone(embedding), n.path))
+	lines := []string{previewDimStyle.Render("┌ ") + previewHStyle.Render(n.title)}
+	for _, line := range doc.lines {
+		lines = append(lines, previewDimStyle.Render("│ ")+line)
+	}
+	return append(lines, previewDimStyle.Render("└"))
+}
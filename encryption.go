@@ -0,0 +1,350 @@
+package main
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Marks a note file sealed with the vault key: this header, a random nonce,
+// then the note encrypted with AES-256-GCM
+const sealedHeader = "gleaner-sealed-v1\n"
+
+// File in the notes directory that marks the vault as encrypted. It holds a
+// sealed known text, so a wrong key is caught before any note is touched.
+const vaultLockFile = ".gleaner-encrypted"
+
+// Text sealed into the vault lock file
+const vaultLockText = "gleaner vault key check"
+
+// errNoVaultKey is returned when an encrypted vault is opened without its key
+var errNoVaultKey = errors.New("vault is encrypted and its key is missing: set GLEANER_VAULT_KEY or vault_key_file")
+
+var (
+	// Key read from the key file, cached per file
+	vaultKeyCache   = map[string][]byte{}
+	vaultKeyCacheMu sync.Mutex
+)
+
+// vaultKeyPath returns the file the vault key is kept in: "vault_key_file"
+// from the config file, or vault.key next to it
+func vaultKeyPath() string {
+	if cfg.VaultKeyFile != "" {
+		return expandHome(cfg.VaultKeyFile)
+	}
+	return filepath.Join(configDir(), "vault.key")
+}
+
+// parseVaultKey decodes a hex-encoded 256-bit key
+func parseVaultKey(text string) ([]byte, error) {
+	key, err := hex.DecodeString(strings.TrimSpace(text))
+	if err != nil || len(key) != 32 {
+		return nil, errors.New("vault key must be 64 hex digits")
+	}
+	return key, nil
+}
+
+// vaultKey returns the key from GLEANER_VAULT_KEY or the key file
+func vaultKey() ([]byte, error) {
+	if env := os.Getenv("GLEANER_VAULT_KEY"); env != "" {
+		return parseVaultKey(env)
+	}
+
+	path := vaultKeyPath()
+	vaultKeyCacheMu.Lock()
+	defer vaultKeyCacheMu.Unlock()
+	if key, ok := vaultKeyCache[path]; ok {
+		return key, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, errNoVaultKey
+	}
+	if err != nil {
+		return nil, err
+	}
+	key, err := parseVaultKey(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	vaultKeyCache[path] = key
+	return key, nil
+}
+
+// vaultEncrypted reports whether the open vault keeps its notes sealed
+func vaultEncrypted() bool {
+	_, err := os.Stat(filepath.Join(notesDir, vaultLockFile))
+	return err == nil
+}
+
+// checkVaultKey confirms that the key opens an encrypted vault
+func checkVaultKey() error {
+	if !vaultEncrypted() {
+		return nil
+	}
+	key, err := vaultKey()
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(filepath.Join(notesDir, vaultLockFile))
+	if err != nil {
+		return err
+	}
+	if text, err := unseal(key, data); err != nil || string(text) != vaultLockText {
+		return errors.New("the vault key does not open this vault")
+	}
+	return nil
+}
+
+// seal encrypts data with the key
+func seal(key, data []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	out := append([]byte(sealedHeader), nonce...)
+	return gcm.Seal(out, nonce, data, []byte(sealedHeader)), nil
+}
+
+// unseal decrypts data sealed with the key
+func unseal(key, data []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	data = bytes.TrimPrefix(data, []byte(sealedHeader))
+	if len(data) < gcm.NonceSize() {
+		return nil, errors.New("sealed note is truncated")
+	}
+	text, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], []byte(sealedHeader))
+	if err != nil {
+		return nil, errors.New("sealed note cannot be decrypted with this key")
+	}
+	return text, nil
+}
+
+// isSealed reports whether file data is a sealed note
+func isSealed(data []byte) bool {
+	return bytes.HasPrefix(data, []byte(sealedHeader))
+}
+
+// readNoteFile reads a note, decrypting it in memory when it is sealed.
+// Windows line endings are read as plain newlines; storeNoteFile puts them
+// back when the note is saved.
+func readNoteFile(path string) ([]byte, error) {
+	data, err := readNoteBytes(path)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n")), nil
+}
+
+// readNoteBytes reads a note as it is stored, line endings and all,
+// decrypting it when it is sealed
+func readNoteBytes(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil || !isSealed(data) {
+		return data, err
+	}
+	key, err := vaultKey()
+	if err != nil {
+		return nil, err
+	}
+	text, err := unseal(key, data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filepath.Base(path), err)
+	}
+	return text, nil
+}
+
+// matchLineEndings gives content Windows line endings when the note at
+// path has them, so saving a note written on Windows does not rewrite every
+// line of it
+func matchLineEndings(path string, content []byte) []byte {
+	data, err := readNoteBytes(path)
+	if err != nil || !bytes.Contains(data, []byte("\r\n")) {
+		return content
+	}
+	content = bytes.ReplaceAll(content, []byte("\r\n"), []byte("\n"))
+	return bytes.ReplaceAll(content, []byte("\n"), []byte("\r\n"))
+}
+
+// storeNoteFile writes a note, sealing it when the vault is encrypted. A
+// note that had Windows line endings keeps them, and a note already on
+// disk keeps its permissions.
+func storeNoteFile(path string, content []byte) error {
+	slog.Debug("write note", "path", path, "bytes", len(content))
+	content = matchLineEndings(path, content)
+	if vaultEncrypted() {
+		key, err := vaultKey()
+		if err != nil {
+			return err
+		}
+		if content, err = seal(key, content); err != nil {
+			return err
+		}
+	}
+	return writeFileAtomic(path, content, 0644)
+}
+
+// createVaultKey writes a new random key to the key file, readable only by
+// its owner
+func createVaultKey() ([]byte, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	path := vaultKeyPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(path, []byte(hex.EncodeToString(key)+"\n"), 0600); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// plainBackups returns the backups in dir that hold notes as they were
+// before the vault was encrypted: archives without a password, and the
+// folders find and replace copies notes into
+func plainBackups(dir string) []string {
+	var plain []string
+	archives, _ := listBackups(dir)
+	for _, a := range archives {
+		if !strings.HasSuffix(a.path, encryptedSuffix) {
+			plain = append(plain, a.path)
+		}
+	}
+	copies, _ := filepath.Glob(filepath.Join(dir, "replaced-*"))
+	return append(plain, copies...)
+}
+
+// runEncrypt implements `gleaner encrypt [--off] [--keep-plain-backups]`,
+// sealing every note of the vault, trashed ones included, with the vault
+// key, or with --off decrypting them again. A key is created when there is
+// none yet. Backups taken before would still hold the notes in plain text,
+// so encrypting stops while there are any unless --keep-plain-backups says
+// to leave them.
+func runEncrypt(args []string) error {
+	flags := flag.NewFlagSet("encrypt", flag.ContinueOnError)
+	off := flags.Bool("off", false, "decrypt the vault and stop encrypting notes")
+	keepBackups := flags.Bool("keep-plain-backups", false, "encrypt even though earlier backups hold the notes unencrypted")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() > 0 {
+		return errors.New("usage: gleaner encrypt [--off] [--keep-plain-backups]")
+	}
+	if readOnly {
+		return errReadOnly
+	}
+	if err := checkVaultKey(); err != nil {
+		return err
+	}
+	if !*off && !vaultEncrypted() && !*keepBackups {
+		if plain := plainBackups(backupDir()); len(plain) > 0 {
+			return fmt.Errorf("these backups hold the notes unencrypted; move or delete them first, or pass --keep-plain-backups:\n  %s", strings.Join(plain, "\n  "))
+		}
+	}
+
+	key, err := vaultKey()
+	if errors.Is(err, errNoVaultKey) && !*off {
+		if key, err = createVaultKey(); err == nil {
+			fmt.Printf("Created a vault key in %s; keep a copy somewhere safe\n", vaultKeyPath())
+		}
+	}
+	if err != nil && (!*off || vaultEncrypted()) {
+		return err
+	}
+
+	notes, err := notesForCommand()
+	if err != nil {
+		return err
+	}
+	trashed, err := listTrash()
+	if err != nil {
+		return err
+	}
+	paths := make([]string, 0, len(notes)+len(trashed))
+	for _, n := range notes {
+		paths = append(paths, n.path)
+	}
+	for _, t := range trashed {
+		paths = append(paths, t.path)
+	}
+
+	lock := filepath.Join(notesDir, vaultLockFile)
+	if !*off {
+		check, err := seal(key, []byte(vaultLockText))
+		if err != nil {
+			return err
+		}
+		if err := writeFileAtomic(lock, check, 0644); err != nil {
+			return err
+		}
+	}
+
+	changed := 0
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		switch {
+		case !*off && !isSealed(data):
+			data, err = seal(key, data)
+		case *off && isSealed(data):
+			data, err = unseal(key, data)
+		default:
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		// Written whole or not at all, keeping the file's mode and time so
+		// the list's order and the trash's purge dates are unchanged
+		if err := writeFileAtomic(path, data, 0644); err != nil {
+			return err
+		}
+		if err := os.Chtimes(path, info.ModTime(), info.ModTime()); err != nil {
+			return err
+		}
+		changed++
+	}
+
+	if *off {
+		if err := os.Remove(lock); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		fmt.Printf("Decrypted %d notes\n", changed)
+		return nil
+	}
+	fmt.Printf("Encrypted %d notes\n", changed)
+	return nil
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMatchLineEndings(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{"windows.md": "one\r\ntwo\r\n", "unix.md": "one\ntwo\n"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tests := []struct {
+		name, file, content, want string
+	}{
+		{"windows note", "windows.md", "one\ntwo\nthree\n", "one\r\ntwo\r\nthree\r\n"},
+		{"windows note already matching", "windows.md", "one\r\nthree\n", "one\r\nthree\r\n"},
+		{"unix note", "unix.md", "one\ntwo\n", "one\ntwo\n"},
+		{"new note", "new.md", "one\r\ntwo\n", "one\r\ntwo\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(matchLineEndings(filepath.Join(dir, tt.file), []byte(tt.content))); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunEncrypt(t *testing.T) {
+	oldDir, oldCfg := notesDir, cfg
+	t.Cleanup(func() { notesDir, cfg = oldDir, oldCfg })
+	notesDir, cfg = t.TempDir(), config{BackupDir: t.TempDir()}
+	t.Setenv("GLEANER_VAULT_KEY", strings.Repeat("ab", 32))
+
+	note := filepath.Join(notesDir, "1700000000-Private.md")
+	trashed := filepath.Join(notesDir, trashDir, "1700000001-Old.md")
+	then := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	for path, text := range map[string]string{note: "secret\n", trashed: "old secret\n"} {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(text), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, then, then); err != nil {
+			t.Fatal(err)
+		}
+	}
+	plain := filepath.Join(cfg.BackupDir, "gleaner-20240301-120000.zip")
+	if err := os.WriteFile(plain, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(cfg.BackupDir, "gleaner-20240302-120000.zip"+encryptedSuffix), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	err := runEncrypt(nil)
+	if err == nil || !strings.Contains(err.Error(), plain) || strings.Contains(err.Error(), encryptedSuffix) {
+		t.Fatalf("encrypting beside a plain backup: %v", err)
+	}
+	if vaultEncrypted() {
+		t.Fatal("vault marked encrypted after refusing")
+	}
+
+	if err := runEncrypt([]string{"--keep-plain-backups"}); err != nil {
+		t.Fatal(err)
+	}
+	for path, text := range map[string]string{note: "secret\n", trashed: "old secret\n"} {
+		data, err := os.ReadFile(path)
+		if err != nil || !isSealed(data) {
+			t.Errorf("%s not sealed: %q, %v", filepath.Base(path), data, err)
+		}
+		if got, err := readNoteFile(path); string(got) != text {
+			t.Errorf("%s reads as %q, %v", filepath.Base(path), got, err)
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Mode().Perm() != 0600 || !info.ModTime().Equal(then) {
+			t.Errorf("%s has mode %v and time %v, want 0600 and %v", filepath.Base(path), info.Mode().Perm(), info.ModTime(), then)
+		}
+	}
+	if leftovers, _ := filepath.Glob(filepath.Join(notesDir, ".*.tmp-*")); len(leftovers) > 0 {
+		t.Errorf("temporary files left behind: %v", leftovers)
+	}
+
+	if err := runEncrypt([]string{"--off"}); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(trashed); string(data) != "old secret\n" {
+		t.Errorf("trashed note decrypted to %q", data)
+	}
+	if vaultEncrypted() {
+		t.Error("vault still marked encrypted")
+	}
+}
@@ -0,0 +1,243 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Note type of notes that are a flashcard as a whole: the title is the
+// question and the body the answer
+const noteTypeFlashcard = "flashcard"
+
+// Flashcard styles
+var (
+	cardQuestionStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("205"))
+	cardSourceStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+)
+
+// flashcard is a question to review and its answer
+type flashcard struct {
+	id       string // Key of the card's schedule: the note id, and the question for Q/A blocks
+	question string
+	answer   string
+	source   string // Title of the note the card comes from
+}
+
+// cardState is a card's SM-2 schedule
+type cardState struct {
+	Ease     float64 `json:"ease"`     // Easiness factor, at least 1.3
+	Interval int     `json:"interval"` // Days until the next review
+	Reps     int     `json:"reps"`     // Successful reviews in a row
+	Due      string  `json:"due"`      // Day of the next review
+}
+
+// Serializes updates to the review log
+var reviewLogMu sync.Mutex
+
+// reviewLogPath returns the file the flashcard schedules are kept in
+func reviewLogPath() string {
+	return filepath.Join(configDir(), "reviews.json")
+}
+
+// loadReviewLog reads the flashcard schedules of every vault, keyed by
+// notes directory and then by card id
+func loadReviewLog() vaultState[cardState] {
+	return loadState[cardState](reviewLogPath())
+}
+
+// gradeCard schedules a card's next review in this vault from its grade
+func gradeCard(id string, grade int) error {
+	_, err := updateState(&reviewLogMu, reviewLogPath(), id, func(state cardState) cardState {
+		return schedule(state, grade, localNow())
+	})
+	return err
+}
+
+// noteCards returns the flashcards in a note: the whole note for flashcard
+// notes, and otherwise each "Q:" line followed by an "A:" line. An answer
+// runs on until a blank line or the next question.
+func noteCards(n note) []flashcard {
+	if n.kind == noteTypeFlashcard {
+		return []flashcard{{
+			id:       fmt.Sprint(n.createdAt),
+			question: n.title,
+			answer:   strings.TrimSpace(n.body),
+			source:   n.title,
+		}}
+	}
+
+	var cards []flashcard
+	var card *flashcard
+	inAnswer := false
+	for _, line := range strings.Split(n.body, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, "Q:"):
+			question := strings.TrimSpace(trimmed[2:])
+			cards = append(cards, flashcard{id: fmt.Sprintf("%d/%s", n.createdAt, question), question: question, source: n.title})
+			card, inAnswer = &cards[len(cards)-1], false
+		case card != nil && strings.HasPrefix(trimmed, "A:"):
+			card.answer, inAnswer = strings.TrimSpace(trimmed[2:]), true
+		case card != nil && inAnswer && trimmed != "":
+			card.answer += "\n" + line
+		default:
+			card, inAnswer = nil, false
+		}
+	}
+
+	// Questions without an answer are not cards
+	var complete []flashcard
+	for _, c := range cards {
+		if c.question != "" && c.answer != "" {
+			complete = append(complete, c)
+		}
+	}
+	return complete
+}
+
+// dueCards returns the cards in notes that are new or due by today
+func dueCards(notes []note, states map[string]cardState, today string) []flashcard {
+	var due []flashcard
+	for _, n := range notes {
+		for _, c := range noteCards(n) {
+			if state, ok := states[c.id]; !ok || state.Due <= today {
+				due = append(due, c)
+			}
+		}
+	}
+	return due
+}
+
+// schedule applies an SM-2 grade from 0 (forgotten) to 5 (perfect recall)
+// to a card's schedule, counting the days from today
+func schedule(state cardState, grade int, today time.Time) cardState {
+	if state.Ease == 0 {
+		state.Ease = 2.5
+	}
+	if grade >= 3 {
+		switch state.Reps {
+		case 0:
+			state.Interval = 1
+		case 1:
+			state.Interval = 6
+		default:
+			state.Interval = int(math.Round(float64(state.Interval) * state.Ease))
+		}
+		state.Reps++
+	} else {
+		state.Reps = 0
+		state.Interval = 1
+	}
+	miss := float64(5 - grade)
+	state.Ease = max(1.3, state.Ease+0.1-miss*(0.08+miss*0.02))
+	state.Due = dayKey(today.AddDate(0, 0, state.Interval))
+	return state
+}
+
+// dueStatus reports the number of flashcards due, or "" when there are none
+func dueStatus(notes []note) string {
+	due := dueCards(notes, loadReviewLog()[notesDir], dayKey(localNow()))
+	if len(due) == 0 {
+		return ""
+	}
+	return trf("%d flashcards due, alt+R to review", len(due))
+}
+
+// startReview opens the review of the flashcards due in the listed notes
+func (m model) startReview() model {
+	m.reviewCards = dueCards(m.notes, loadReviewLog()[notesDir], dayKey(localNow()))
+	if len(m.reviewCards) == 0 {
+		m.status = tr("No flashcards due")
+		return m
+	}
+	m.reviewTotal = len(m.reviewCards)
+	m.reviewGraded = make(map[string]bool)
+	m.reviewShown = false
+	m.mode = "review"
+	return m
+}
+
+// updateReview shows the answer on space or enter and takes a grade from 0
+// to 5 for it. Cards graded below 3 come back at the end of the session,
+// though only the first grade changes their schedule.
+func (m model) updateReview(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch key := msg.String(); {
+	case msg.Type == tea.KeyEsc || key == "q":
+		if remaining := len(m.reviewCards); remaining > 0 {
+			m.status = trf("%d flashcards left for later", remaining)
+		}
+		m.mode = "list"
+		m.reviewCards = nil
+
+	case len(m.reviewCards) == 0:
+		m.mode = "list"
+
+	case !m.reviewShown && (key == " " || key == "enter"):
+		m.reviewShown = true
+
+	case m.reviewShown && len(key) == 1 && key[0] >= '0' && key[0] <= '5':
+		grade := int(key[0] - '0')
+		card := m.reviewCards[0]
+		m.reviewCards = m.reviewCards[1:]
+		if !m.reviewGraded[card.id] {
+			m.reviewGraded[card.id] = true
+			if err := gradeCard(card.id, grade); err != nil {
+				m.status = trf("Could not save the review: %v", err)
+			}
+		}
+		if grade < 3 {
+			m.reviewCards = append(m.reviewCards, card)
+		}
+		m.reviewShown = false
+	}
+	return m, nil
+}
+
+// reviewText renders the card under review, or the end of the session
+func (m model) reviewText(width int) string {
+	if len(m.reviewCards) == 0 {
+		return cardQuestionStyle.Render(trf("Reviewed %d flashcards", m.reviewTotal))
+	}
+	card := m.reviewCards[0]
+	done := m.reviewTotal - len(m.reviewCards) + 1
+	lines := []string{
+		cardSourceStyle.Render(trf("Card %d of %d · %s", max(1, min(done, m.reviewTotal)), m.reviewTotal, card.source)),
+		"",
+		cardQuestionStyle.Render(card.question),
+	}
+	if m.reviewShown {
+		lines = append(lines, "", previewRuleStyle.Render(strings.Repeat("─", max(1, min(width, 40)))), "")
+		for _, line := range strings.Split(card.answer, "\n") {
+			lines = append(lines, wrapLines(renderLine(line, width), width)...)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// reviewHelp describes the keys for the current step of the review
+func (m model) reviewHelp() string {
+	switch {
+	case len(m.reviewCards) == 0:
+		return tr("esc:Back")
+	case m.reviewShown:
+		return tr("0-5:Grade (0 forgot, 5 easy) | esc:Back")
+	default:
+		return tr("space:Show answer | esc:Back")
+	}
+}
+
+// reviewView shows the flashcard review full screen
+func (m model) reviewView() string {
+	width := max(m.width-12, 10)
+	return docStyle.Render(lipgloss.JoinVertical(lipgloss.Top,
+		splitStyle.Width(m.width-8).Height(m.height-6).Render(m.reviewText(width)),
+		helpStyle.Render(m.reviewHelp()),
+	))
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Key that jumps from a footnote reference to its definition and back
+const footnoteKey = "ctrl+]"
+
+// Markdown footnotes: [^label] references and [^label]: text definitions
+var (
+	footnoteRefPattern = regexp.MustCompile(`\[\^([^\]\s]+)\]`)
+	footnoteDefPattern = regexp.MustCompile(`^\[\^([^\]\s]+)\]:\s?(.*)$`)
+)
+
+// footnoteNumbers numbers a document's footnotes in the order they are
+// first referenced, followed by any defined but never referenced. Code
+// blocks are skipped.
+func footnoteNumbers(lines []string) map[string]int {
+	numbers := map[string]int{}
+	number := func(label string) {
+		if _, ok := numbers[label]; !ok {
+			numbers[label] = len(numbers) + 1
+		}
+	}
+	var defined []string
+	inCode := false
+	for _, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inCode = !inCode
+		}
+		if inCode {
+			continue
+		}
+		if def := footnoteDefPattern.FindStringSubmatch(line); def != nil {
+			defined = append(defined, def[1])
+			line = def[2]
+		}
+		for _, ref := range footnoteRefPattern.FindAllStringSubmatch(line, -1) {
+			number(ref[1])
+		}
+	}
+	for _, label := range defined {
+		number(label)
+	}
+	return numbers
+}
+
+// superscript writes a number with superscript digits
+func superscript(n int) string {
+	const digits = "⁰¹²³⁴⁵⁶⁷⁸⁹"
+	var b strings.Builder
+	for _, d := range strconv.Itoa(n) {
+		b.WriteString(string([]rune(digits)[d-'0']))
+	}
+	return b.String()
+}
+
+// renderFootnoteRefs shows the footnote references in a line as their
+// numbers in superscript
+func renderFootnoteRefs(line string, numbers map[string]int) string {
+	return footnoteRefPattern.ReplaceAllStringFunc(line, func(ref string) string {
+		return previewLinkStyle.Render(superscript(numbers[footnoteRefPattern.FindStringSubmatch(ref)[1]]))
+	})
+}
+
+// renderFootnote renders a footnote definition as its number and text, or
+// returns false when the line is not one
+func renderFootnote(line string, numbers map[string]int) (string, bool) {
+	def := footnoteDefPattern.FindStringSubmatch(line)
+	if def == nil {
+		return "", false
+	}
+	return previewDimStyle.Render(superscript(numbers[def[1]])+" ") + renderInline(renderFootnoteRefs(def[2], numbers)), true
+}
+
+// refersTo reports whether a line references the footnote, outside the
+// footnote's own definition
+func refersTo(line, label string) bool {
+	if def := footnoteDefPattern.FindStringSubmatch(line); def != nil {
+		line = def[2]
+	}
+	for _, ref := range footnoteRefPattern.FindAllStringSubmatch(line, -1) {
+		if ref[1] == label {
+			return true
+		}
+	}
+	return false
+}
+
+// followFootnote moves the cursor from a footnote reference to the
+// footnote's definition, and from a definition back to the reference it
+// was reached from, or else the first one
+func (m model) followFootnote() model {
+	lines := strings.Split(m.textarea.Value(), "\n")
+	row, col := cursorPos(m.textarea)
+	if row >= len(lines) {
+		return m
+	}
+	line := lines[row]
+
+	if def := footnoteDefPattern.FindStringSubmatch(line); def != nil {
+		label := def[1]
+		if m.footnoteFrom < len(lines) && m.footnoteFrom != row && refersTo(lines[m.footnoteFrom], label) {
+			return m.jumpToLine(m.footnoteFrom)
+		}
+		for i, other := range lines {
+			if i != row && refersTo(other, label) {
+				return m.jumpToLine(i)
+			}
+		}
+		m.status = trf("Footnote %s is never referenced", label)
+		return m
+	}
+
+	// Follow the reference under or after the cursor, else the last one
+	refs := footnoteRefPattern.FindAllStringSubmatchIndex(line, -1)
+	if len(refs) == 0 {
+		m.status = tr("No footnote reference on this line")
+		return m
+	}
+	offset := len(string([]rune(line)[:min(col, len([]rune(line)))]))
+	ref := refs[len(refs)-1]
+	for _, r := range refs {
+		if r[1] > offset {
+			ref = r
+			break
+		}
+	}
+	label := line[ref[2]:ref[3]]
+	for i, other := range lines {
+		if def := footnoteDefPattern.FindStringSubmatch(other); def != nil && def[1] == label {
+			m.footnoteFrom = row
+			return m.jumpToLine(i)
+		}
+	}
+	m.status = trf("Footnote %s has no definition", label)
+	return m
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"maps"
+	"testing"
+)
+
+func TestFootnoteNumbers(t *testing.T) {
+	tests := []struct {
+		name  string
+		lines []string
+		want  map[string]int
+	}{
+		{"numbered as first referenced", []string{"See[^b] and[^a], again[^b].", "[^a]: x", "[^b]: y"}, map[string]int{"b": 1, "a": 2}},
+		{"code blocks skipped", []string{"```", "[^c]", "```", "text[^a]"}, map[string]int{"a": 1}},
+		{"references inside definitions", []string{"[^a]", "[^a]: see [^d]"}, map[string]int{"a": 1, "d": 2}},
+		{"defined but never referenced last", []string{"[^e]: unused", "text[^a]", "[^a]: x"}, map[string]int{"a": 1, "e": 2}},
+		{"none", []string{"plain [text]"}, map[string]int{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := footnoteNumbers(tt.lines); !maps.Equal(got, tt.want) {
+				t.Errorf("footnoteNumbers = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
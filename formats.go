@@ -0,0 +1,182 @@
+package main
+
+import (
+	"html"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Extensions of note files when "extensions" is not set in the config file
+var defaultExtensions = []string{".md"}
+
+// noteExtensions returns the configured note extensions, lower case and
+// with a leading dot; new notes are created with the first
+func noteExtensions() []string {
+	var exts []string
+	for _, ext := range cfg.Extensions {
+		ext = strings.ToLower(strings.TrimSpace(ext))
+		if ext == "" {
+			continue
+		}
+		if !strings.HasPrefix(ext, ".") {
+			ext = "." + ext
+		}
+		exts = append(exts, ext)
+	}
+	if len(exts) == 0 {
+		return defaultExtensions
+	}
+	return exts
+}
+
+// isNoteExt reports whether files with the extension are notes
+func isNoteExt(ext string) bool {
+	ext = strings.ToLower(ext)
+	for _, e := range noteExtensions() {
+		if e == ext {
+			return true
+		}
+	}
+	return false
+}
+
+// newNoteExt returns the extension new notes are saved with
+func newNoteExt() string {
+	return noteExtensions()[0]
+}
+
+// renderNote renders a note for the preview pane according to its format:
+// Org files are converted to the equivalent markdown, plain text is only
+// wrapped, and everything else is treated as markdown. dir is the note's
+// folder, and embedding holds its own path, if saved, so embeds that lead
+// back to it stop there.
+func renderNote(src, ext, dir string, width int, embedding []string) renderedDoc {
+	switch strings.ToLower(ext) {
+	case ".txt":
+		return renderPlain(src, width)
+	case ".org":
+		return renderMarkdown(orgToMarkdown(src), dir, width, embedding)
+	}
+	return renderMarkdown(src, dir, width, embedding)
+}
+
+// noteToHTML converts a note to an HTML fragment according to its format
+func noteToHTML(src, ext string) string {
+	switch strings.ToLower(ext) {
+	case ".txt":
+		return "<pre>" + html.EscapeString(src) + "</pre>\n"
+	case ".org":
+		return markdownToHTML(orgToMarkdown(src))
+	}
+	return markdownToHTML(src)
+}
+
+// editorExt returns the extension of the note in the editor, which for a
+// new note is the one it will be saved with
+func (m model) editorExt() string {
+	if m.mode != "new" && m.selectedNote != nil {
+		return filepath.Ext(m.selectedNote.path)
+	}
+	return newNoteExt()
+}
+
+// isMarkdownExt reports whether notes with the extension are markdown,
+// which every format but plain text and Org is taken to be
+func isMarkdownExt(ext string) bool {
+	switch strings.ToLower(ext) {
+	case ".txt", ".org":
+		return false
+	}
+	return true
+}
+
+// editorText returns the text in the editor as it is saved: with its pipe
+// tables aligned when the note is markdown. Other formats are left as
+// typed, since a | line means something else in them.
+func (m model) editorText() string {
+	if !isMarkdownExt(m.editorExt()) {
+		return m.textarea.Value()
+	}
+	return formatTables(m.textarea.Value())
+}
+
+// renderPlain wraps plain text without interpreting any markup
+func renderPlain(src string, width int) renderedDoc {
+	var doc renderedDoc
+	width = max(width, 10)
+	for _, line := range strings.Split(src, "\n") {
+		doc.srcLine = append(doc.srcLine, len(doc.lines))
+		doc.lines = append(doc.lines, wrapLines(line, width)...)
+	}
+	return doc
+}
+
+// Org mode markup that has a markdown counterpart
+var (
+	orgHeadingPattern = regexp.MustCompile(`^(\*+)\s+(.*)$`)
+	orgKeywordPattern = regexp.MustCompile(`^\s*#\+(\w+):?\s*(.*)$`)
+	orgLinkPattern    = regexp.MustCompile(`\[\[([^\]]+)\](?:\[([^\]]+)\])?\]`)
+	orgBoldPattern    = regexp.MustCompile(`(^|[\s(])\*([^*\s][^*]*?)\*($|[\s).,;:!?])`)
+	orgEmPattern      = regexp.MustCompile(`(^|[\s(])/([^/\s][^/]*?)/($|[\s).,;:!?])`)
+	orgCodePattern    = regexp.MustCompile(`(^|[\s(])[=~]([^=~\s][^=~]*?)[=~]($|[\s).,;:!?])`)
+)
+
+// orgToMarkdown rewrites Org mode markup line by line, so each source line
+// still maps to one markdown line for cursor tracking
+func orgToMarkdown(src string) string {
+	lines := strings.Split(src, "\n")
+	inBlock, inQuote := false, false
+	for i, line := range lines {
+		if match := orgKeywordPattern.FindStringSubmatch(line); match != nil {
+			switch keyword := strings.ToUpper(match[1]); {
+			case keyword == "TITLE":
+				lines[i] = "# " + match[2]
+			case keyword == "BEGIN_SRC" || keyword == "BEGIN_EXAMPLE" ||
+				keyword == "END_SRC" || keyword == "END_EXAMPLE":
+				inBlock = strings.HasPrefix(keyword, "BEGIN")
+				lines[i] = "```"
+			case keyword == "BEGIN_QUOTE" || keyword == "END_QUOTE":
+				inQuote = keyword == "BEGIN_QUOTE"
+				lines[i] = ""
+			default:
+				lines[i] = "" // Other settings are not part of the text
+			}
+			continue
+		}
+		if inBlock {
+			continue
+		}
+		if strings.HasPrefix(strings.TrimSpace(line), "# ") {
+			lines[i] = "" // Comment
+			continue
+		}
+
+		if match := orgHeadingPattern.FindStringSubmatch(line); match != nil {
+			line = strings.Repeat("#", len(match[1])) + " " + match[2]
+		}
+		line = orgLinkPattern.ReplaceAllStringFunc(line, func(s string) string {
+			m := orgLinkPattern.FindStringSubmatch(s)
+			if m[2] == "" {
+				return "[" + m[1] + "](" + m[1] + ")"
+			}
+			return "[" + m[2] + "](" + m[1] + ")"
+		})
+		line = orgCodePattern.ReplaceAllString(line, "$1`$2`$3")
+		line = orgBoldPattern.ReplaceAllString(line, "$1**$2**$3")
+		line = orgEmPattern.ReplaceAllString(line, "$1*$2*$3")
+		if inQuote {
+			line = "> " + line
+		}
+		lines[i] = line
+	}
+	return strings.Join(lines, "\n")
+}
+
+// trimNoteExt removes a note extension from a file name or title
+func trimNoteExt(name string) string {
+	if ext := filepath.Ext(name); ext != "" && isNoteExt(ext) {
+		return strings.TrimSuffix(name, ext)
+	}
+	return name
+}
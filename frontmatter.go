@@ -0,0 +1,209 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+)
+
+// metaField is a single "key: value" line from a note's frontmatter. List
+// values are kept in their inline form, e.g. "[work, ideas]".
+type metaField struct {
+	key   string
+	value string
+}
+
+// First line of an ASCII-armored age file
+const ageArmorHeader = "-----BEGIN AGE ENCRYPTED FILE-----"
+
+// Matches markdown checkboxes, capturing the check mark
+var taskPattern = regexp.MustCompile(`(?m)^\s*[-*+]\s+\[([ xX])\]`)
+
+// parseFrontmatter splits a note into its frontmatter fields and body. Notes
+// without a leading "---" block have no fields and the whole content as body.
+func parseFrontmatter(content string) ([]metaField, string) {
+	normalized := strings.ReplaceAll(content, "\r\n", "\n")
+	if !strings.HasPrefix(normalized, "---\n") {
+		return nil, content
+	}
+	rest := normalized[len("---\n"):]
+	end := strings.Index(rest, "\n---")
+	if end < 0 {
+		return nil, content
+	}
+	block := rest[:end]
+	body := strings.TrimPrefix(rest[end+len("\n---"):], "\n")
+
+	var fields []metaField
+	for _, line := range strings.Split(block, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		// Block list items ("  - value") belong to the previous key
+		if strings.HasPrefix(trimmed, "- ") && len(fields) > 0 {
+			last := &fields[len(fields)-1]
+			items := metaList(last.value)
+			items = append(items, unquote(strings.TrimSpace(trimmed[2:])))
+			last.value = "[" + strings.Join(items, ", ") + "]"
+			continue
+		}
+
+		key, value, found := strings.Cut(trimmed, ":")
+		if !found {
+			continue
+		}
+		fields = append(fields, metaField{
+			key:   strings.TrimSpace(key),
+			value: strings.TrimSpace(value),
+		})
+	}
+	return fields, body
+}
+
+// setFrontmatter sets key to value in the note's frontmatter, replacing an
+// existing entry or adding one, and creating the block when there is none
+func setFrontmatter(content, key, value string) string {
+	line := key + ": " + value
+	normalized := strings.ReplaceAll(content, "\r\n", "\n")
+	if !strings.HasPrefix(normalized, "---\n") {
+		return keepLineEndings(content, "---\n"+line+"\n---\n"+normalized)
+	}
+	rest := normalized[len("---\n"):]
+	end := strings.Index(rest, "\n---")
+	if end < 0 {
+		return keepLineEndings(content, "---\n"+line+"\n---\n"+normalized)
+	}
+
+	lines := strings.Split(rest[:end], "\n")
+	if i, n := frontmatterEntry(lines, key); i >= 0 {
+		// The new value is inline, so block list items of the old one go
+		lines = append(lines[:i], append([]string{line}, lines[i+n:]...)...)
+		return keepLineEndings(content, "---\n"+strings.Join(lines, "\n")+rest[end:])
+	}
+	return keepLineEndings(content, "---\n"+strings.Join(append(lines, line), "\n")+rest[end:])
+}
+
+// deleteFrontmatter removes key and its value from the note's frontmatter
+func deleteFrontmatter(content, key string) string {
+	normalized := strings.ReplaceAll(content, "\r\n", "\n")
+	if !strings.HasPrefix(normalized, "---\n") {
+		return content
+	}
+	rest := normalized[len("---\n"):]
+	end := strings.Index(rest, "\n---")
+	if end < 0 {
+		return content
+	}
+
+	lines := strings.Split(rest[:end], "\n")
+	i, n := frontmatterEntry(lines, key)
+	if i < 0 {
+		return content
+	}
+	lines = append(lines[:i], lines[i+n:]...)
+	if len(lines) == 0 {
+		return keepLineEndings(content, strings.TrimPrefix(rest[end+len("\n---"):], "\n"))
+	}
+	return keepLineEndings(content, "---\n"+strings.Join(lines, "\n")+rest[end:])
+}
+
+// keepLineEndings gives edited text the Windows line endings of the
+// original when it had them
+func keepLineEndings(original, edited string) string {
+	if !strings.Contains(original, "\r\n") {
+		return edited
+	}
+	return strings.ReplaceAll(edited, "\n", "\r\n")
+}
+
+// frontmatterEntry finds the line of key in a frontmatter block and counts
+// the lines its value takes up, including block list items; -1 if absent
+func frontmatterEntry(lines []string, key string) (int, int) {
+	for i, l := range lines {
+		if k, _, found := strings.Cut(l, ":"); found && strings.EqualFold(strings.TrimSpace(k), key) && !strings.HasPrefix(l, " ") {
+			n := 1
+			for i+n < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i+n]), "- ") {
+				n++
+			}
+			return i, n
+		}
+	}
+	return -1, 0
+}
+
+// metaValue returns the value of key, or "" when it is absent
+func metaValue(fields []metaField, key string) string {
+	for _, f := range fields {
+		if strings.EqualFold(f.key, key) {
+			return unquote(f.value)
+		}
+	}
+	return ""
+}
+
+// metaBool reports whether key is set to a truthy value
+func metaBool(fields []metaField, key string) bool {
+	switch strings.ToLower(metaValue(fields, key)) {
+	case "true", "yes", "on", "1":
+		return true
+	}
+	return false
+}
+
+// metaList splits an inline list ("[a, b]" or "a, b") into its items
+func metaList(value string) []string {
+	value = strings.TrimSpace(value)
+	value = strings.TrimSuffix(strings.TrimPrefix(value, "["), "]")
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = unquote(strings.TrimSpace(item)); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// unquote strips matching single or double quotes around a value
+func unquote(value string) string {
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		return value[1 : len(value)-1]
+	}
+	return value
+}
+
+// readMetadata fills the cached list details of a note from its content
+func (n *note) readMetadata(content string) {
+	fields, body := parseFrontmatter(content)
+	// A title in the frontmatter keeps punctuation the file name cannot
+	if title := metaValue(fields, "title"); title != "" {
+		n.title = title
+	}
+	n.tags = metaList(metaValue(fields, "tags"))
+	n.aliases = metaList(metaValue(fields, "aliases"))
+	n.pinned = metaBool(fields, "pinned")
+	n.archived = metaBool(fields, "archived")
+	n.label = strings.ToLower(metaValue(fields, "label"))
+	n.priority = parsePriority(metaValue(fields, "priority"))
+	n.status = strings.ToLower(metaValue(fields, "status"))
+	n.kind = strings.ToLower(metaValue(fields, "type"))
+	n.language = strings.ToLower(metaValue(fields, "language"))
+	n.url = metaValue(fields, "url")
+	n.icon = metaValue(fields, "icon")
+	n.encrypted = metaBool(fields, "encrypted") || strings.HasPrefix(body, ageArmorHeader)
+	n.tasksOpen, n.tasksDone = countTasks(body)
+	n.excerpt = noteExcerpt(body)
+	n.body = body
+}
+
+// countTasks returns the number of open and completed checkboxes in body
+func countTasks(body string) (open, done int) {
+	for _, match := range taskPattern.FindAllStringSubmatch(body, -1) {
+		if match[1] == " " {
+			open++
+		} else {
+			done++
+		}
+	}
+	return open, done
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// explainError adds what the user can do about a file system failure they
+// can fix themselves: a full disk, a read-only mount or missing permissions
+func explainError(err error) string {
+	switch {
+	case errors.Is(err, syscall.ENOSPC):
+		return err.Error() + "; " + tr("the disk is full, free some space and try again")
+	case errors.Is(err, syscall.EROFS):
+		return err.Error() + "; " + tr("the file system is mounted read-only")
+	case errors.Is(err, fs.ErrPermission):
+		return err.Error() + "; " + tr("check the owner and permissions of the notes directory")
+	}
+	return err.Error()
+}
+
+// checkWritable confirms that new files can be created in dir
+func checkWritable(dir string) error {
+	f, err := os.CreateTemp(dir, ".gleaner-write-check-*")
+	if err != nil {
+		return err
+	}
+	f.Close()
+	return os.Remove(f.Name())
+}
+
+// ensureNotesDir creates the notes directory when it is missing and checks
+// that notes can be written to it. A vault that cannot be written to is
+// opened read-only, and the returned warning says why.
+func ensureNotesDir() (string, error) {
+	if _, err := os.Stat(notesDir); errors.Is(err, fs.ErrNotExist) {
+		if err := os.MkdirAll(notesDir, 0755); err != nil {
+			return "", fmt.Errorf("cannot create the notes directory %s: %s\nChoose another location with --dir or GLEANER_DIR", notesDir, explainError(err))
+		}
+	}
+	if err := checkWritable(notesDir); err != nil {
+		readOnly = true
+		return trf("Opened read-only because notes cannot be written: %s", explainError(err)), nil
+	}
+	return "", nil
+}
+
+// writeFileAtomic writes a file by way of a temporary file beside it, so a
+// full disk or a crash leaves the old contents in place rather than a
+// truncated file. A symlink is followed so the file it points to is
+// replaced rather than the link, and a file already there keeps its mode;
+// perm is the mode of a new file.
+func writeFileAtomic(path string, data []byte, perm fs.FileMode) error {
+	if target, err := filepath.EvalSymlinks(path); err == nil {
+		path = target
+	}
+	if info, err := os.Stat(path); err == nil {
+		perm = info.Mode().Perm()
+	}
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(f.Name(), perm)
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), path)
+	}
+	if err != nil {
+		os.Remove(f.Name())
+	}
+	return err
+}
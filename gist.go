@@ -0,0 +1,126 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// GitHub endpoint gists are created at
+const gistAPI = "https://api.github.com/gists"
+
+// Frontmatter key the published gist URL is stored under
+const gistKey = "gist"
+
+// gistFile is the content of one file in a gist
+type gistFile struct {
+	Content string `json:"content"`
+}
+
+// gistRequest is the body sent to create or update a gist
+type gistRequest struct {
+	Description string              `json:"description"`
+	Public      bool                `json:"public"`
+	Files       map[string]gistFile `json:"files"`
+}
+
+// gistMsg reports the outcome of sharing a note
+type gistMsg struct {
+	url     string
+	updated bool
+	err     error
+}
+
+// gistToken returns the GitHub token used for gists, preferring the
+// environment over the config file
+func gistToken() string {
+	for _, token := range []string{os.Getenv("GLEANER_GIST_TOKEN"), os.Getenv("GITHUB_TOKEN"), cfg.GistToken} {
+		if token != "" {
+			return token
+		}
+	}
+	return ""
+}
+
+// shareGist publishes a note as a secret gist, or updates the gist it was
+// shared to before, and records the gist URL in the note's frontmatter
+func shareGist(n note) tea.Cmd {
+	return func() tea.Msg {
+		token := gistToken()
+		if token == "" {
+			return gistMsg{err: fmt.Errorf("no GitHub token: set gist_token in config or GITHUB_TOKEN")}
+		}
+		data, err := readNoteFile(n.path)
+		if err != nil {
+			return gistMsg{err: err}
+		}
+		content := string(data)
+		fields, body := parseFrontmatter(content)
+
+		url := metaValue(fields, gistKey)
+		method, endpoint := http.MethodPost, gistAPI
+		if url != "" {
+			method, endpoint = http.MethodPatch, gistAPI+"/"+path.Base(url)
+		}
+
+		req := gistRequest{
+			Description: n.title,
+			Files:       map[string]gistFile{sanitizeFileName(n.title) + filepath.Ext(n.path): {Content: body}},
+		}
+		shared, err := callGistAPI(method, endpoint, token, req)
+		if err != nil {
+			return gistMsg{err: err}
+		}
+
+		if shared != url {
+			updated := setFrontmatter(content, gistKey, shared)
+			if err := storeNoteFile(n.path, []byte(updated)); err != nil {
+				return gistMsg{err: err}
+			}
+			recordChange(auditEdit, n.path, content, updated)
+		}
+		return gistMsg{url: shared, updated: url != ""}
+	}
+}
+
+// callGistAPI sends a gist request and returns the URL of the gist
+func callGistAPI(method, endpoint, token string, body gistRequest) (string, error) {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequest(method, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Content-Type", "application/json")
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		HTMLURL string `json:"html_url"`
+		Message string `json:"message"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("invalid response from GitHub: %w", err)
+	}
+	if resp.StatusCode >= 300 {
+		return "", fmt.Errorf("GitHub returned %s: %s", resp.Status, strings.TrimSpace(result.Message))
+	}
+	return result.HTMLURL, nil
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Word goal progress bar styling
+var (
+	goalFilledStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("212"))
+	goalEmptyStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("237"))
+	goalDoneStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("42"))
+)
+
+// noteGoal returns the words in a note's body and the target set by its
+// "goal" frontmatter field, which is 0 when the note has none
+func noteGoal(content string) (int, int) {
+	fields, body := parseFrontmatter(content)
+	goal, err := strconv.Atoi(strings.TrimSpace(metaValue(fields, "goal")))
+	if err != nil || goal < 0 {
+		goal = 0
+	}
+	return wordCount(body), goal
+}
+
+// goalBar draws the progress of the note being edited towards its word
+// goal, or returns "" when it has none
+func (m model) goalBar(width int) string {
+	words, goal := noteGoal(m.textarea.Value())
+	if goal == 0 {
+		return ""
+	}
+
+	label := " " + trf("%d/%d words (%d%%)", words, goal, words*100/goal)
+	if words >= goal {
+		label += " ✓"
+	}
+	barWidth := max(5, width-lipgloss.Width(label))
+	filled := min(barWidth, words*barWidth/goal)
+	if words >= goal {
+		return goalDoneStyle.Render(strings.Repeat("█", barWidth) + label)
+	}
+	return goalFilledStyle.Render(strings.Repeat("█", filled)) +
+		goalEmptyStyle.Render(strings.Repeat("░", barWidth-filled)) +
+		statsLabelStyle.Render(label)
+}
@@ -0,0 +1,255 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// How long the driver waits for commands to report back before it treats
+// the UI as settled. Timers such as the cursor blink keep running, so the
+// UI is never entirely idle.
+const settleQuiet = 50 * time.Millisecond
+
+// How long expect waits for text to appear
+const expectTimeout = 2 * time.Second
+
+// Key names, such as "enter" or "ctrl+n", mapped to their key types
+var keyTypes = func() map[string]tea.KeyType {
+	types := make(map[string]tea.KeyType)
+	for t := tea.KeyType(-100); t <= 127; t++ {
+		if name := t.String(); name != "" {
+			types[name] = t
+		}
+	}
+	return types
+}()
+
+// driver runs the model without a terminal: it feeds it messages, runs the
+// commands it returns and feeds their results back, and renders its view on
+// demand. Integration tests and scripted demos drive the UI through it.
+type driver struct {
+	m    tea.Model
+	msgs chan tea.Msg
+	quit bool
+}
+
+// newDriver starts a model as a terminal of the given size would
+func newDriver(m tea.Model, width, height int) *driver {
+	d := &driver{m: m, msgs: make(chan tea.Msg, 64)}
+	d.run(m.Init())
+	d.send(tea.WindowSizeMsg{Width: width, Height: height})
+	d.settle()
+	return d
+}
+
+// run carries out a command in the background, queuing its result
+func (d *driver) run(cmd tea.Cmd) {
+	if cmd == nil {
+		return
+	}
+	go func() {
+		if msg := cmd(); msg != nil {
+			d.msgs <- msg
+		}
+	}()
+}
+
+// send delivers a message to the model
+func (d *driver) send(msg tea.Msg) {
+	switch msg := msg.(type) {
+	case tea.BatchMsg:
+		for _, cmd := range msg {
+			d.run(cmd)
+		}
+	case tea.QuitMsg:
+		d.quit = true
+	default:
+		var cmd tea.Cmd
+		d.m, cmd = d.m.Update(msg)
+		d.run(cmd)
+	}
+}
+
+// settle delivers queued results until none arrive for a moment
+func (d *driver) settle() {
+	for {
+		select {
+		case msg := <-d.msgs:
+			d.send(msg)
+		case <-time.After(settleQuiet):
+			return
+		}
+	}
+}
+
+// wait keeps delivering results for a while
+func (d *driver) wait(duration time.Duration) {
+	deadline := time.After(duration)
+	for {
+		select {
+		case msg := <-d.msgs:
+			d.send(msg)
+		case <-deadline:
+			return
+		}
+	}
+}
+
+// parseKey turns a key name like "enter", "ctrl+d", "alt+a" or "x" into a
+// key press
+func parseKey(name string) (tea.KeyMsg, error) {
+	if t, ok := keyTypes[name]; ok {
+		return tea.KeyMsg{Type: t}, nil
+	}
+	if rest, ok := strings.CutPrefix(name, "alt+"); ok {
+		key, err := parseKey(rest)
+		key.Alt = true
+		return key, err
+	}
+	if len([]rune(name)) == 1 {
+		return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(name)}, nil
+	}
+	return tea.KeyMsg{}, fmt.Errorf("unknown key %q", name)
+}
+
+// press sends key presses by name
+func (d *driver) press(names ...string) error {
+	for _, name := range names {
+		key, err := parseKey(name)
+		if err != nil {
+			return err
+		}
+		d.send(key)
+		d.settle()
+	}
+	return nil
+}
+
+// typeText sends text one character at a time, as typing it would. Spaces
+// arrive as their own key type, as they do from a terminal.
+func (d *driver) typeText(text string) {
+	for _, r := range text {
+		if r == ' ' {
+			d.send(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{r}})
+			continue
+		}
+		d.send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	d.settle()
+}
+
+// view renders the screen as plain text
+func (d *driver) view() string {
+	return ansi.Strip(d.m.View())
+}
+
+// expect waits for text to appear on screen, or with absent for it to
+// disappear
+func (d *driver) expect(text string, absent bool) error {
+	deadline := time.Now().Add(expectTimeout)
+	for {
+		if strings.Contains(d.view(), text) != absent {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			if absent {
+				return fmt.Errorf("%q is still shown", text)
+			}
+			return fmt.Errorf("%q is not shown", text)
+		}
+		d.wait(settleQuiet)
+	}
+}
+
+// runScript drives the model through a script, one command per line:
+//
+//	size 100 30      resize the screen
+//	key ctrl+n       press keys, separated by spaces
+//	type some text   type text
+//	wait 500ms       let timers and background work run
+//	expect text      fail unless text is shown, waiting briefly for it
+//	expect-not text  fail while text is shown
+//	snapshot         print the screen
+//
+// Blank lines and lines starting with # are skipped. The script stops at
+// the first failure or when the model quits.
+func runScript(m tea.Model, name string, script io.Reader) error {
+	d := newDriver(m, 100, 30)
+	scanner := bufio.NewScanner(script)
+	for number := 1; scanner.Scan() && !d.quit; number++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		command, arg, _ := strings.Cut(line, " ")
+		if err := d.step(command, arg); err != nil {
+			return fmt.Errorf("%s:%d: %w\n%s", name, number, err, d.view())
+		}
+	}
+	return scanner.Err()
+}
+
+// step carries out one script command
+func (d *driver) step(command, arg string) error {
+	switch command {
+	case "size":
+		fields := strings.Fields(arg)
+		if len(fields) != 2 {
+			return errors.New("usage: size <width> <height>")
+		}
+		width, err := strconv.Atoi(fields[0])
+		if err != nil {
+			return err
+		}
+		height, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return err
+		}
+		d.send(tea.WindowSizeMsg{Width: width, Height: height})
+		d.settle()
+	case "key":
+		return d.press(strings.Fields(arg)...)
+	case "type":
+		d.typeText(arg)
+	case "wait":
+		duration, err := time.ParseDuration(arg)
+		if err != nil {
+			return err
+		}
+		d.wait(duration)
+	case "expect":
+		return d.expect(arg, false)
+	case "expect-not":
+		return d.expect(arg, true)
+	case "snapshot":
+		fmt.Println(d.view())
+	default:
+		return fmt.Errorf("unknown command %q", command)
+	}
+	return nil
+}
+
+// runHeadless implements `gleaner --headless [--script file]`, running a
+// script against the interface without a terminal. The script is read from
+// standard input when no file is given. The session is neither restored nor
+// saved, so runs start the same way each time.
+func runHeadless(m model, path string) error {
+	if path == "" || path == "-" {
+		return runScript(m, "stdin", os.Stdin)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return runScript(m, path, f)
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// startDriver runs the interface over an empty vault and config of its own
+// for the length of the test
+func startDriver(t *testing.T) *driver {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+	t.Setenv("XDG_DATA_HOME", filepath.Join(home, ".local", "share"))
+	oldDir, oldCfg, oldBase := notesDir, cfg, baseCfg
+	t.Cleanup(func() { notesDir, cfg, baseCfg = oldDir, oldCfg, oldBase })
+	notesDir, cfg, baseCfg = t.TempDir(), config{}, config{}
+	return newDriver(initialModel(), 100, 30)
+}
+
+// must fails the test on a script error, showing the screen
+func must(t *testing.T, d *driver, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("%v\n%s", err, d.view())
+	}
+}
+
+func TestSaveNewNote(t *testing.T) {
+	d := startDriver(t)
+	must(t, d, d.press("ctrl+n"))
+	d.typeText("Groceries")
+	must(t, d, d.press("tab"))
+	d.typeText("milk and eggs")
+	must(t, d, d.press("ctrl+s"))
+	must(t, d, d.expect("Groceries", false))
+
+	notes, err := scanNotes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(notes) != 1 {
+		t.Fatalf("%d notes saved, want 1", len(notes))
+	}
+	if n := notes[0]; n.title != "Groceries" || !strings.HasSuffix(n.path, "-Groceries.md") || strings.TrimSpace(n.body) != "milk and eggs" {
+		t.Errorf("saved %q at %s with %q", n.title, filepath.Base(n.path), n.body)
+	}
+}
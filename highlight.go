@@ -0,0 +1,155 @@
+package main
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Syntax highlighting styles
+var (
+	codeKeywordStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("205")).Bold(true)
+	codeStringStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("150"))
+	codeCommentStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Italic(true)
+	codeNumberStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
+)
+
+// syntax is what the highlighter knows about a language: its keywords, how
+// line comments start and which characters quote strings
+type syntax struct {
+	keywords map[string]bool
+	comment  string
+	quotes   string
+	caseless bool // Keywords match in any case, as in SQL
+}
+
+// newSyntax builds a syntax from a space-separated keyword list
+func newSyntax(keywords, comment, quotes string) *syntax {
+	s := &syntax{keywords: map[string]bool{}, comment: comment, quotes: quotes}
+	for _, k := range strings.Fields(keywords) {
+		s.keywords[k] = true
+	}
+	return s
+}
+
+// Languages the highlighter supports, by the name used for code fences and
+// the "language" field of snippet notes
+var syntaxes = map[string]*syntax{
+	"go": newSyntax("break case chan const continue default defer else fallthrough for func go goto if import "+
+		"interface map package range return select struct switch type var nil true false", "//", "\"'`"),
+	"python": newSyntax("and as assert async await break class continue def del elif else except finally for from "+
+		"global if import in is lambda nonlocal not or pass raise return try while with yield None True False", "#", "\"'"),
+	"javascript": newSyntax("async await break case catch class const continue debugger default delete do else export "+
+		"extends finally for function if import in instanceof let new of return super switch this throw try typeof "+
+		"var void while yield null undefined true false", "//", "\"'`"),
+	"typescript": newSyntax("abstract any as async await boolean break case catch class const continue default do else "+
+		"enum export extends finally for function if implements import in interface keyof let new number of private "+
+		"protected public readonly return string switch this throw try type typeof var void while null undefined true false",
+		"//", "\"'`"),
+	"rust": newSyntax("as async await break const continue crate else enum extern fn for if impl in let loop match mod "+
+		"move mut pub ref return self Self static struct super trait type unsafe use where while true false", "//", "\""),
+	"c": newSyntax("auto break case char const continue default do double else enum extern float for goto if int long "+
+		"register return short signed sizeof static struct switch typedef union unsigned void volatile while NULL", "//", "\"'"),
+	"cpp": newSyntax("auto bool break case catch char class const constexpr continue default delete do double else enum "+
+		"explicit extern false float for friend if inline int long namespace new nullptr operator private protected "+
+		"public return short signed sizeof static struct switch template this throw true try typedef typename union "+
+		"unsigned using virtual void volatile while", "//", "\"'"),
+	"java": newSyntax("abstract boolean break byte case catch char class const continue default do double else enum "+
+		"extends final finally float for if implements import instanceof int interface long new package private "+
+		"protected public return short static super switch synchronized this throw throws try void volatile while "+
+		"null true false var record", "//", "\"'"),
+	"ruby": newSyntax("alias and begin break case class def defined do else elsif end ensure false for if in module "+
+		"next nil not or redo rescue retry return self super then true undef unless until when while yield", "#", "\"'"),
+	"sh": newSyntax("case do done elif else esac export fi for function if in local readonly return select then "+
+		"until while echo cd exit set unset", "#", "\"'"),
+	"sql": {keywords: sqlKeywords(), comment: "--", quotes: "'\"", caseless: true},
+}
+
+// sqlKeywords lists SQL keywords in lower case
+func sqlKeywords() map[string]bool {
+	keywords := map[string]bool{}
+	for _, k := range strings.Fields("select from where and or not insert into values update set delete create table " +
+		"drop alter index join left right inner outer on as group by order having limit offset distinct union all " +
+		"null is in like between case when then else end primary key foreign references default exists") {
+		keywords[k] = true
+	}
+	return keywords
+}
+
+// Other names languages go by
+var syntaxAliases = map[string]string{
+	"golang": "go", "py": "python", "python3": "python", "js": "javascript", "jsx": "javascript",
+	"node": "javascript", "ts": "typescript", "tsx": "typescript", "rs": "rust", "h": "c",
+	"c++": "cpp", "cc": "cpp", "hpp": "cpp", "rb": "ruby", "bash": "sh", "shell": "sh", "zsh": "sh",
+	"console": "sh", "postgres": "sql", "mysql": "sql", "sqlite": "sql",
+}
+
+// syntaxFor returns the syntax of a language, or nil when it is unknown
+func syntaxFor(language string) *syntax {
+	language = strings.ToLower(strings.TrimSpace(language))
+	if alias, ok := syntaxAliases[language]; ok {
+		language = alias
+	}
+	return syntaxes[language]
+}
+
+// highlightLine colours the keywords, strings, numbers and comment of one
+// line of code. Strings and comments spanning lines are not recognised.
+func highlightLine(line string, syn *syntax) string {
+	if syn == nil {
+		return previewCodeStyle.Render(line)
+	}
+
+	var b strings.Builder
+	runes := []rune(line)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case syn.comment != "" && strings.HasPrefix(string(runes[i:]), syn.comment):
+			b.WriteString(codeCommentStyle.Render(string(runes[i:])))
+			return b.String()
+
+		case strings.ContainsRune(syn.quotes, r):
+			end := i + 1
+			for end < len(runes) && runes[end] != r {
+				if runes[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			end = min(end+1, len(runes))
+			b.WriteString(codeStringStyle.Render(string(runes[i:end])))
+			i = end
+
+		case unicode.IsDigit(r):
+			end := i
+			for end < len(runes) && (unicode.IsDigit(runes[end]) || unicode.IsLetter(runes[end]) || runes[end] == '.' || runes[end] == '_') {
+				end++
+			}
+			b.WriteString(codeNumberStyle.Render(string(runes[i:end])))
+			i = end
+
+		case unicode.IsLetter(r) || r == '_':
+			end := i
+			for end < len(runes) && (unicode.IsLetter(runes[end]) || unicode.IsDigit(runes[end]) || runes[end] == '_') {
+				end++
+			}
+			word := string(runes[i:end])
+			key := word
+			if syn.caseless {
+				key = strings.ToLower(word)
+			}
+			if syn.keywords[key] {
+				word = codeKeywordStyle.Render(word)
+			}
+			b.WriteString(word)
+			i = end
+
+		default:
+			b.WriteRune(r)
+			i++
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Lifecycle events that can trigger user hooks
+const (
+	hookPreSave    = "pre-save"
+	hookPostSave   = "post-save"
+	hookPostDelete = "post-delete"
+)
+
+// Maximum time a single hook command may run
+const hookTimeout = 30 * time.Second
+
+// hookErrMsg reports a failed hook back to the TUI
+type hookErrMsg struct {
+	err error
+}
+
+// runHooks executes every command configured for event, passing note details
+// through GLEANER_* environment variables and the note content on stdin.
+// It stops at the first failing command.
+func runHooks(event, path, content string) error {
+	for _, command := range cfg.Hooks[event] {
+		if err := runHook(event, command, path, content); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runHook runs a single hook command through the system shell
+func runHook(event, command, path, content string) error {
+	slog.Debug("run hook", "event", event, "command", command, "path", path)
+	ctx, cancel := context.WithTimeout(context.Background(), hookTimeout)
+	defer cancel()
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+	cmd.Dir = notesDir
+	cmd.Stdin = strings.NewReader(content)
+	cmd.Env = append(os.Environ(), hookEnv(event, path)...)
+
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	if err := cmd.Run(); err != nil {
+		detail := strings.TrimSpace(output.String())
+		if detail != "" {
+			return fmt.Errorf("%s hook %q failed: %v: %s", event, command, err, detail)
+		}
+		return fmt.Errorf("%s hook %q failed: %v", event, command, err)
+	}
+	return nil
+}
+
+// hookEnv describes the event and note to a hook command
+func hookEnv(event, path string) []string {
+	env := []string{
+		"GLEANER_EVENT=" + event,
+		"GLEANER_VAULT=" + activeVault,
+		"GLEANER_VAULT_DIR=" + notesDir,
+		"GLEANER_NOTE_PATH=" + path,
+	}
+	if title, createdAt, ok := parseNoteFilename(filepath.Base(path)); ok {
+		env = append(env,
+			"GLEANER_NOTE_TITLE="+title,
+			"GLEANER_NOTE_CREATED="+strconv.FormatInt(createdAt, 10),
+		)
+	}
+	return env
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestSaveHooks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hooks in the tests are shell commands")
+	}
+	oldDir, oldCfg, oldVault := notesDir, cfg, activeVault
+	t.Cleanup(func() { notesDir, cfg, activeVault = oldDir, oldCfg, oldVault })
+	notesDir, activeVault = t.TempDir(), "work"
+	hookLog := filepath.Join(t.TempDir(), "hooks")
+	cfg = config{Hooks: map[string][]string{
+		hookPreSave: {
+			`printf '%s %s %s %s|' "$GLEANER_EVENT" "$GLEANER_VAULT" "$GLEANER_NOTE_TITLE" "$GLEANER_NOTE_CREATED" >> "` + hookLog + `"`,
+			`cat >> "` + hookLog + `"`,
+		},
+		hookPostSave: {`echo " saved in $(pwd)" >> "` + hookLog + `"`},
+	}}
+
+	path := filepath.Join(notesDir, "1700000000-Plan.md")
+	if err := writeNoteFile(path, "step one"); err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile(hookLog)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir, _ := filepath.EvalSymlinks(notesDir)
+	if want := "pre-save work Plan 1700000000|step one saved in " + dir + "\n"; string(got) != want {
+		t.Errorf("hooks logged %q, want %q", got, want)
+	}
+
+	// A failing pre-save hook stops the save and the hooks after it
+	cfg.Hooks[hookPreSave] = []string{`echo "no saving on Fridays" >&2; exit 1`, `touch "` + hookLog + `.2"`}
+	err = writeNoteFile(path, "step two")
+	if err == nil || !strings.Contains(err.Error(), "pre-save hook") || !strings.Contains(err.Error(), "no saving on Fridays") {
+		t.Errorf("failing hook gave %v", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "step one" {
+		t.Errorf("note saved despite the hook: %q", data)
+	}
+	if _, err := os.Stat(hookLog + ".2"); err == nil {
+		t.Error("hook after the failing one ran")
+	}
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"html"
+	"strings"
+)
+
+// markdownToHTML converts a note to an HTML fragment. Frontmatter is dropped;
+// the supported syntax matches what the preview pane renders.
+func markdownToHTML(src string) string {
+	_, body := parseFrontmatter(src)
+	lines := strings.Split(strings.ReplaceAll(body, "\r\n", "\n"), "\n")
+
+	var out strings.Builder
+	var paragraph []string
+	list := "" // Tag of the list currently open, "ul" or "ol"
+
+	flushParagraph := func() {
+		if len(paragraph) > 0 {
+			out.WriteString("<p>" + strings.Join(paragraph, "<br>\n") + "</p>\n")
+			paragraph = nil
+		}
+	}
+	closeList := func() {
+		if list != "" {
+			out.WriteString("</" + list + ">\n")
+			list = ""
+		}
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+
+		// Fenced code blocks are copied verbatim, except diagrams
+		if strings.HasPrefix(trimmed, "```") {
+			flushParagraph()
+			closeList()
+			lang := strings.TrimSpace(strings.TrimPrefix(trimmed, "```"))
+			i++
+			start := i
+			for i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), "```") {
+				i++
+			}
+			block := lines[start:i]
+
+			// Diagrams are drawn as they are in the preview
+			if isDiagram(lang) {
+				if drawing, ok := drawDiagram(lang, strings.Join(block, "\n")); ok {
+					block = drawing
+					lang = "diagram"
+				}
+			}
+			if lang != "" {
+				out.WriteString(`<pre><code class="language-` + html.EscapeString(lang) + `">`)
+			} else {
+				out.WriteString("<pre><code>")
+			}
+			for _, line := range block {
+				out.WriteString(html.EscapeString(line) + "\n")
+			}
+			out.WriteString("</code></pre>\n")
+			continue
+		}
+
+		// Tables run until the first line that is not part of one
+		if isTableLine(line) {
+			flushParagraph()
+			closeList()
+			start := i
+			for i+1 < len(lines) && isTableLine(lines[i+1]) {
+				i++
+			}
+			out.WriteString(tableToHTML(lines[start : i+1]))
+			continue
+		}
+
+		if match := listItemPattern.FindStringSubmatch(line); match != nil {
+			flushParagraph()
+			tag := "ul"
+			if match[3] != "" {
+				tag = "ol"
+			}
+			if tag != list {
+				closeList()
+				out.WriteString("<" + tag + ">\n")
+				list = tag
+			}
+			item := inlineHTML(match[7])
+			switch strings.TrimSpace(match[6]) {
+			case "[ ]":
+				item = `<input type="checkbox" disabled> ` + item
+			case "[x]", "[X]":
+				item = `<input type="checkbox" checked disabled> ` + item
+			}
+			out.WriteString("<li>" + item + "</li>\n")
+			continue
+		}
+		closeList()
+
+		switch {
+		case trimmed == "":
+			flushParagraph()
+
+		case rulePattern.MatchString(line):
+			flushParagraph()
+			out.WriteString("<hr>\n")
+
+		case strings.HasPrefix(trimmed, "#"):
+			flushParagraph()
+			level := min(len(trimmed)-len(strings.TrimLeft(trimmed, "#")), 6)
+			tag := "h" + string(rune('0'+level))
+			out.WriteString("<" + tag + ">" + inlineHTML(strings.TrimSpace(strings.TrimLeft(trimmed, "#"))) + "</" + tag + ">\n")
+
+		case strings.HasPrefix(trimmed, ">"):
+			flushParagraph()
+			var quote []string
+			for ; i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), ">"); i++ {
+				quote = append(quote, inlineHTML(strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(lines[i]), ">"))))
+			}
+			i--
+			out.WriteString("<blockquote><p>" + strings.Join(quote, "<br>\n") + "</p></blockquote>\n")
+
+		default:
+			paragraph = append(paragraph, inlineHTML(trimmed))
+		}
+	}
+	flushParagraph()
+	closeList()
+	return out.String()
+}
+
+// tableToHTML converts the lines of a pipe table; the first row is the
+// header when followed by a separator row
+func tableToHTML(lines []string) string {
+	var out strings.Builder
+	out.WriteString("<table>\n")
+	for i, line := range lines {
+		cells := splitRow(line)
+		if isSeparatorRow(cells) {
+			continue
+		}
+		tag := "td"
+		if i == 0 && len(lines) > 1 && isSeparatorRow(splitRow(lines[1])) {
+			tag = "th"
+		}
+		out.WriteString("<tr>")
+		for _, cell := range cells {
+			out.WriteString("<" + tag + ">" + inlineHTML(cell) + "</" + tag + ">")
+		}
+		out.WriteString("</tr>\n")
+	}
+	out.WriteString("</table>\n")
+	return out.String()
+}
+
+// inlineHTML escapes text and converts code spans, links and emphasis
+func inlineHTML(text string) string {
+	// Code spans are swapped out first so their contents stay literal
+	var spans []string
+	text = inlineCodePattern.ReplaceAllStringFunc(text, func(s string) string {
+		spans = append(spans, "<code>"+html.EscapeString(inlineCodePattern.FindStringSubmatch(s)[1])+"</code>")
+		return "\x00"
+	})
+
+	text = html.EscapeString(text)
+	text = linkPattern.ReplaceAllStringFunc(text, func(s string) string {
+		m := linkPattern.FindStringSubmatch(s)
+		return `<a href="` + m[2] + `">` + m[1] + "</a>"
+	})
+	text = boldPattern.ReplaceAllStringFunc(text, func(s string) string {
+		m := boldPattern.FindStringSubmatch(s)
+		return "<strong>" + m[1] + m[2] + "</strong>"
+	})
+	text = emPattern.ReplaceAllStringFunc(text, func(s string) string {
+		m := emPattern.FindStringSubmatch(s)
+		return "<em>" + m[1] + m[2] + "</em>"
+	})
+
+	for _, span := range spans {
+		text = strings.Replace(text, "\x00", span, 1)
+	}
+	return text
+}
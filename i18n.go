@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// Layout for note timestamps when timestamp_format is not set
+const defaultTimestampFormat = "2006-01-02 15:04:05"
+
+// Translations of the UI strings, keyed by language and then by the English
+// text. Missing entries fall back to English.
+var catalogs = map[string]map[string]string{
+	"de": catalogDE,
+	"es": catalogES,
+}
+
+// Language the UI is shown in; "en" or a key of catalogs
+var locale = "en"
+
+// setLocale picks the UI language from the config file, then the
+// LC_ALL, LC_MESSAGES and LANG environment variables
+func setLocale() {
+	locale = "en"
+	for _, value := range []string{cfg.Locale, os.Getenv("LC_ALL"), os.Getenv("LC_MESSAGES"), os.Getenv("LANG")} {
+		if value == "" {
+			continue
+		}
+		// "de_DE.UTF-8" and "de-AT" both select "de"
+		lang := strings.ToLower(value)
+		if i := strings.IndexAny(lang, "_-."); i >= 0 {
+			lang = lang[:i]
+		}
+		if _, ok := catalogs[lang]; ok {
+			locale = lang
+		}
+		return
+	}
+}
+
+// tr translates a UI string into the current language
+func tr(text string) string {
+	if translated, ok := catalogs[locale][text]; ok {
+		return translated
+	}
+	return text
+}
+
+// trf translates a format string and fills it in
+func trf(format string, args ...any) string {
+	return fmt.Sprintf(tr(format), args...)
+}
+
+// formatTime renders a timestamp with the configured layout, translating
+// month and weekday names into the current language
+func formatTime(t time.Time) string {
+	layout := cfg.TimestampFormat
+	if layout == "" {
+		layout = defaultTimestampFormat
+	}
+	text := t.In(displayZone).Format(layout)
+	if locale == "en" {
+		return text
+	}
+
+	month, weekday := t.Month().String(), t.Weekday().String()
+	switch {
+	case strings.Contains(layout, "January"):
+		text = strings.ReplaceAll(text, month, tr(month))
+	case strings.Contains(layout, "Jan"):
+		text = strings.ReplaceAll(text, month[:3], tr(month[:3]))
+	}
+	switch {
+	case strings.Contains(layout, "Monday"):
+		text = strings.ReplaceAll(text, weekday, tr(weekday))
+	case strings.Contains(layout, "Mon"):
+		text = strings.ReplaceAll(text, weekday[:3], tr(weekday[:3]))
+	}
+	return text
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// File listing paths inside the vault to leave out of the notes list,
+// in gitignore syntax; each folder may have its own
+const ignoreFileName = ".gleanerignore"
+
+// ignoreRule is one pattern from a .gleanerignore file
+type ignoreRule struct {
+	base     string // folder holding the ignore file, relative to the vault
+	pattern  *regexp.Regexp
+	negate   bool // "!pattern" brings back a path an earlier rule ignored
+	dirOnly  bool // "pattern/" only matches folders
+	anchored bool // patterns with an inner or leading slash match from base
+}
+
+// readIgnoreFile loads the rules in dir's .gleanerignore, if there is one
+func readIgnoreFile(dir, base string) ([]ignoreRule, error) {
+	data, err := os.ReadFile(filepath.Join(dir, ignoreFileName))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return parseIgnoreRules(string(data), base), nil
+}
+
+// parseIgnoreRules parses gitignore-style lines: blank lines and # comments
+// are skipped, and a backslash escapes a leading # or !
+func parseIgnoreRules(text, base string) []ignoreRule {
+	var rules []ignoreRule
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimRight(line, " \t\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		rule := ignoreRule{base: base}
+		if strings.HasPrefix(line, "!") {
+			rule.negate = true
+			line = line[1:]
+		} else if strings.HasPrefix(line, `\`) {
+			line = line[1:]
+		}
+		if strings.HasSuffix(line, "/") {
+			rule.dirOnly = true
+			line = strings.TrimRight(line, "/")
+		}
+		if strings.Contains(line, "/") {
+			rule.anchored = true
+			line = strings.TrimPrefix(line, "/")
+		}
+		if line == "" {
+			continue
+		}
+
+		pattern, err := regexp.Compile("^" + globToRegexp(line) + "$")
+		if err != nil {
+			continue
+		}
+		rule.pattern = pattern
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+// globToRegexp translates a gitignore glob: * and ? stay within one path
+// segment, ** spans any number of folders and [...] is a character class
+func globToRegexp(glob string) string {
+	var b strings.Builder
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+		switch {
+		case strings.HasPrefix(glob[i:], "**/"):
+			b.WriteString("(.*/)?")
+			i += 2
+		case strings.HasPrefix(glob[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case c == '*':
+			b.WriteString("[^/]*")
+		case c == '?':
+			b.WriteString("[^/]")
+		case c == '[':
+			end := strings.IndexByte(glob[i+1:], ']')
+			if end < 0 {
+				b.WriteString(`\[`)
+				continue
+			}
+			class := glob[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			b.WriteString("[" + class + "]")
+			i += end + 1
+		case c == '\\' && i+1 < len(glob):
+			i++
+			b.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	return b.String()
+}
+
+// ignored reports whether the vault-relative path is excluded by the rules.
+// As in git, the last matching rule wins.
+func ignored(rules []ignoreRule, rel string, isDir bool) bool {
+	rel = filepath.ToSlash(rel)
+	result := false
+	for _, rule := range rules {
+		if rule.dirOnly && !isDir {
+			continue
+		}
+		sub := rel
+		if rule.base != "" {
+			if !strings.HasPrefix(rel, rule.base+"/") {
+				continue
+			}
+			sub = strings.TrimPrefix(rel, rule.base+"/")
+		}
+		if !rule.anchored {
+			sub = sub[strings.LastIndex(sub, "/")+1:]
+		}
+		if rule.pattern.MatchString(sub) {
+			result = !rule.negate
+		}
+	}
+	return result
+}
@@ -0,0 +1,329 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif" // Registers the decoders image.Decode uses
+	_ "image/jpeg"
+	"image/png"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Terminal graphics protocols images can be shown with
+const (
+	imagesKitty   = "kitty"
+	imagesITerm   = "iterm2"
+	imagesSixel   = "sixel"
+	imagesNone    = "none"
+	imageMaxRows  = 16 // Tallest an image is drawn, in lines
+	imageCacheMax = 32 // Most images kept drawn at once
+
+	// Assumed size of a terminal cell in pixels; terminals don't report it
+	// reliably, so images are scaled for cells twice as tall as wide
+	cellWidthPx  = 10
+	cellHeightPx = 20
+)
+
+// Protocol the preview draws images with, empty when images are shown as
+// placeholders
+var imageProtocol string
+
+// Matches a line holding only a ![alt](path) image, with an optional title
+var imagePattern = regexp.MustCompile(`^\s*!\[([^\]]*)\]\(<?([^)>\s]+)>?(?:\s+"[^"]*")?\)\s*$`)
+
+// detectImageProtocol returns the graphics protocol the terminal speaks:
+// the image_protocol setting when there is one, and otherwise a guess from
+// the environment. Inside tmux and screen, which pass no graphics through,
+// it returns none.
+func detectImageProtocol(setting string) string {
+	switch setting = strings.ToLower(strings.TrimSpace(setting)); setting {
+	case imagesKitty, imagesITerm, imagesSixel:
+		return setting
+	case imagesNone:
+		return ""
+	}
+
+	term, program := os.Getenv("TERM"), os.Getenv("TERM_PROGRAM")
+	switch {
+	case os.Getenv("TMUX") != "" || strings.HasPrefix(term, "screen") || strings.HasPrefix(term, "tmux"):
+		return ""
+	case os.Getenv("KITTY_WINDOW_ID") != "" || term == "xterm-kitty" || program == "ghostty" || term == "xterm-ghostty":
+		return imagesKitty
+	case program == "iTerm.app" || program == "WezTerm" || os.Getenv("LC_TERMINAL") == "iTerm2":
+		return imagesITerm
+	case strings.Contains(term, "sixel") || term == "foot" || strings.HasPrefix(term, "foot-") || strings.HasPrefix(term, "mlterm") || program == "contour":
+		return imagesSixel
+	}
+	return ""
+}
+
+// cachedImage is an image drawn for the preview at one width
+type cachedImage struct {
+	modified int64
+	width    int
+	lines    []string
+}
+
+// Images already drawn, by path, so the preview can redraw on every
+// keystroke without decoding them again. Only the latest width of each is
+// kept, and at most imageCacheMax of them.
+var (
+	imageCache  = map[string]cachedImage{}
+	imageIDs    = map[string]int{} // Kitty image number for each path
+	nextImageID = 1
+)
+
+// renderImage draws the image a ![alt](path) line shows, or a placeholder
+// naming it when the terminal cannot show images or the file cannot be read.
+// dir is the folder of the note showing it.
+func renderImage(alt, target, dir string, width int) []string {
+	name := target
+	if unescaped, err := url.PathUnescape(target); err == nil {
+		name = unescaped
+	}
+	label := filepath.Base(name)
+	if alt != "" && alt != label {
+		label = alt + " (" + label + ")"
+	}
+	placeholder := []string{truncateLine(previewDimStyle.Render("🖼 "+label), width)}
+	if imageProtocol == "" || strings.Contains(target, "://") {
+		return placeholder
+	}
+
+	path, info := findImage(name, dir)
+	if path == "" {
+		return []string{truncateLine(previewDimStyle.Render("⚠ "+trf("Image not found: %s", name)), width)}
+	}
+	if cached, ok := imageCache[path]; ok && cached.modified == info.ModTime().UnixNano() && cached.width == width {
+		return cached.lines
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return placeholder
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return placeholder
+	}
+	cols, rows := imageCells(img.Bounds(), width)
+	var lines []string
+	switch imageProtocol {
+	case imagesKitty:
+		if imageIDs[path] == 0 {
+			imageIDs[path] = nextImageID
+			nextImageID++
+		}
+		lines = kittyImage(img, imageIDs[path], cols, rows)
+	case imagesITerm:
+		lines = stripImage(img, cols, rows, iTermStrip)
+	case imagesSixel:
+		lines = stripImage(img, cols, rows, sixelStrip)
+	}
+	if lines == nil {
+		return placeholder
+	}
+	if _, ok := imageCache[path]; !ok && len(imageCache) >= imageCacheMax {
+		for old := range imageCache {
+			delete(imageCache, old)
+			break
+		}
+	}
+	imageCache[path] = cachedImage{modified: info.ModTime().UnixNano(), width: width, lines: lines}
+	return lines
+}
+
+// findImage looks for an image next to the note, then in the notes
+// directory, and then by file name in the attachment folders
+func findImage(name, dir string) (string, os.FileInfo) {
+	candidates := []string{name}
+	if !filepath.IsAbs(name) {
+		candidates = []string{filepath.Join(dir, name), filepath.Join(notesDir, name)}
+		for _, dir := range attachmentDirs() {
+			candidates = append(candidates, filepath.Join(dir, filepath.Base(name)))
+		}
+	}
+	for _, path := range candidates {
+		if info, err := os.Stat(path); err == nil && info.Mode().IsRegular() {
+			return path, info
+		}
+	}
+	return "", nil
+}
+
+// imageCells sizes an image in terminal cells: no wider than the pane or
+// its own pixels, and no taller than imageMaxRows, keeping its shape
+func imageCells(bounds image.Rectangle, width int) (int, int) {
+	w, h := max(bounds.Dx(), 1), max(bounds.Dy(), 1)
+	cols := max(1, min(width, (w+cellWidthPx-1)/cellWidthPx))
+	rows := max(1, (cols*cellWidthPx*h/w+cellHeightPx/2)/cellHeightPx)
+	if rows > imageMaxRows {
+		rows = imageMaxRows
+		cols = max(1, min(cols, rows*cellHeightPx*w/h/cellWidthPx))
+	}
+	return cols, rows
+}
+
+// scaleImage resizes an image to w by h pixels, picking the nearest pixel
+func scaleImage(img image.Image, w, h int) *image.NRGBA {
+	bounds := img.Bounds()
+	scaled := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		sy := bounds.Min.Y + y*bounds.Dy()/h
+		for x := 0; x < w; x++ {
+			scaled.Set(x, y, img.At(bounds.Min.X+x*bounds.Dx()/w, sy))
+		}
+	}
+	return scaled
+}
+
+// encodePNG encodes an image as base64 PNG data
+func encodePNG(img image.Image) (string, bool) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return "", false
+	}
+	return base64.StdEncoding.EncodeToString(buf.Bytes()), true
+}
+
+// Combining marks that number the rows of kitty image placeholders
+var kittyRowMarks = []rune{
+	0x0305, 0x030D, 0x030E, 0x0310, 0x0312, 0x033D, 0x033E, 0x033F,
+	0x0346, 0x034A, 0x034B, 0x034C, 0x0350, 0x0351, 0x0352, 0x0357,
+}
+
+// kittyImage sends an image to kitty and lays it out with placeholder
+// characters. Placeholders are ordinary text, so the image moves and clears
+// with the preview like the words around it. The image travels with the
+// first line, and the terminal keeps it for the lines after.
+func kittyImage(img image.Image, id, cols, rows int) []string {
+	data, ok := encodePNG(scaleImage(img, cols*cellWidthPx, rows*cellHeightPx))
+	if !ok {
+		return nil
+	}
+	var send strings.Builder
+	for i := 0; i < len(data); i += 4096 {
+		more := 0
+		if i+4096 < len(data) {
+			more = 1
+		}
+		chunk := data[i:min(i+4096, len(data))]
+		if i == 0 {
+			fmt.Fprintf(&send, "\x1b_Ga=T,U=1,q=2,f=100,i=%d,c=%d,r=%d,m=%d;%s\x1b\\", id, cols, rows, more, chunk)
+		} else {
+			fmt.Fprintf(&send, "\x1b_Gm=%d;%s\x1b\\", more, chunk)
+		}
+	}
+
+	// The colour of the placeholders names the image; each row's first
+	// cell carries its row, and the cells after count on from it
+	colour := fmt.Sprintf("\x1b[38;2;%d;%d;%dm", id>>16&255, id>>8&255, id&255)
+	lines := make([]string, rows)
+	for r := range lines {
+		lines[r] = colour + "\U0010EEEE" + string(kittyRowMarks[r]) + string(kittyRowMarks[0]) +
+			strings.Repeat("\U0010EEEE", cols-1) + "\x1b[39m"
+	}
+	lines[0] = send.String() + lines[0]
+	return lines
+}
+
+// stripImage cuts an image into one strip per line, each drawn over the
+// blank line it sits on. The terminal wipes the pixels of any line that is
+// written again, and this way rewriting a line also redraws its strip.
+func stripImage(img image.Image, cols, rows int, encode func(image.Image, int) string) []string {
+	scaled := scaleImage(img, cols*cellWidthPx, rows*cellHeightPx)
+	lines := make([]string, rows)
+	for r := range lines {
+		strip := scaled.SubImage(image.Rect(0, r*cellHeightPx, cols*cellWidthPx, (r+1)*cellHeightPx))
+		data := encode(strip, cols)
+		if data == "" {
+			return nil
+		}
+		// Save the cursor, step back over the blank line and restore it
+		// after drawing, so the terminal's cursor stays where the
+		// interface expects it
+		lines[r] = strings.Repeat(" ", cols) + fmt.Sprintf("\x1b7\x1b[%dD", cols) + data + "\x1b8"
+	}
+	return lines
+}
+
+// iTermStrip encodes a strip of an image as an iTerm2 inline image one
+// line tall
+func iTermStrip(strip image.Image, cols int) string {
+	data, ok := encodePNG(strip)
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf("\x1b]1337;File=inline=1;width=%d;height=1;preserveAspectRatio=0;doNotMoveCursor=1:%s\a", cols, data)
+}
+
+// sixelStrip encodes a strip of an image as sixels, in a palette of 216
+// colours. Transparent pixels are left undrawn.
+func sixelStrip(strip image.Image, _ int) string {
+	bounds := strip.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	index := func(x, y int) int {
+		c := color.NRGBAModel.Convert(strip.At(bounds.Min.X+x, bounds.Min.Y+y)).(color.NRGBA)
+		if c.A < 128 {
+			return -1
+		}
+		return int(c.R)*6/256*36 + int(c.G)*6/256*6 + int(c.B)*6/256
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "\x1bP0;1;0q\"1;1;%d;%d", w, h)
+	defined := make([]bool, 216)
+	for band := 0; band < h; band += 6 {
+		// Each colour in the band is drawn across it in turn, returning
+		// to the band's start with $
+		bits := map[int][]byte{}
+		for x := 0; x < w; x++ {
+			for dy := 0; dy < 6 && band+dy < h; dy++ {
+				if i := index(x, band+dy); i >= 0 {
+					if bits[i] == nil {
+						bits[i] = make([]byte, w)
+					}
+					bits[i][x] |= 1 << dy
+				}
+			}
+		}
+		for i := 0; i < 216; i++ {
+			if bits[i] == nil {
+				continue
+			}
+			// Colours are defined where first used
+			if defined[i] {
+				fmt.Fprintf(&b, "#%d", i)
+			} else {
+				fmt.Fprintf(&b, "#%d;2;%d;%d;%d", i, i/36*20, i/6%6*20, i%6*20)
+				defined[i] = true
+			}
+			row := bits[i]
+			for x := 0; x < w; {
+				run := 1
+				for x+run < w && row[x+run] == row[x] {
+					run++
+				}
+				if run > 3 {
+					fmt.Fprintf(&b, "!%d%c", run, 63+row[x])
+				} else {
+					b.WriteString(strings.Repeat(string(rune(63+row[x])), run))
+				}
+				x += run
+			}
+			b.WriteByte('$')
+		}
+		if band+6 < h {
+			b.WriteByte('-')
+		}
+	}
+	b.WriteString("\x1b\\")
+	return b.String()
+}
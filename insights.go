@@ -0,0 +1,252 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Most used shortcuts and commands shown
+const topCommands = 5
+
+// How often counts kept in memory are written out while the app runs
+const insightFlushInterval = time.Minute
+
+// insightLog holds the usage counters kept when insights are turned on.
+// They never leave the machine.
+type insightLog struct {
+	Created  map[string]int `json:"created"`  // Notes created per day
+	Searches map[string]int `json:"searches"` // Searches run per day
+	Commands map[string]int `json:"commands"` // Uses of each shortcut and subcommand
+}
+
+// Counts not yet written out, and the lock that guards them and the file
+var (
+	pendingInsights = emptyInsightLog()
+	insightLogMu    sync.Mutex
+)
+
+// insightFlushMsg fires when the counts kept in memory are due to be
+// written out
+type insightFlushMsg struct{}
+
+// emptyInsightLog returns usage counters with nothing counted
+func emptyInsightLog() insightLog {
+	return insightLog{Created: map[string]int{}, Searches: map[string]int{}, Commands: map[string]int{}}
+}
+
+// insightLogPath returns the file the usage counters are kept in
+func insightLogPath() string {
+	return filepath.Join(configDir(), "insights.json")
+}
+
+// loadInsightLog reads the usage counters, returning empty ones when there
+// are none
+func loadInsightLog() insightLog {
+	log := emptyInsightLog()
+	if data, err := os.ReadFile(insightLogPath()); err == nil {
+		json.Unmarshal(data, &log)
+	}
+	return log
+}
+
+// countInsight updates the usage counters when the user has opted in. The
+// counts are kept in memory until flushInsights writes them out.
+func countInsight(update func(log *insightLog)) {
+	if !cfg.Insights {
+		return
+	}
+	insightLogMu.Lock()
+	defer insightLogMu.Unlock()
+	update(&pendingInsights)
+}
+
+// flushInsights adds the counts kept in memory to the usage counters file
+func flushInsights() {
+	insightLogMu.Lock()
+	defer insightLogMu.Unlock()
+	if len(pendingInsights.Created)+len(pendingInsights.Searches)+len(pendingInsights.Commands) == 0 {
+		return
+	}
+	log := loadInsightLog()
+	for day, n := range pendingInsights.Created {
+		log.Created[day] += n
+	}
+	for day, n := range pendingInsights.Searches {
+		log.Searches[day] += n
+	}
+	for name, n := range pendingInsights.Commands {
+		log.Commands[name] += n
+	}
+	data, err := json.Marshal(log)
+	if err != nil || os.MkdirAll(configDir(), 0755) != nil {
+		return
+	}
+	if os.WriteFile(insightLogPath(), data, 0644) == nil {
+		pendingInsights = emptyInsightLog()
+	}
+}
+
+// scheduleInsightFlush waits before writing out the counts kept in memory
+func scheduleInsightFlush() tea.Cmd {
+	if !cfg.Insights {
+		return nil
+	}
+	return tea.Tick(insightFlushInterval, func(time.Time) tea.Msg { return insightFlushMsg{} })
+}
+
+// flushedInsights writes out the counts kept in memory in the background
+func flushedInsights() tea.Msg {
+	flushInsights()
+	return nil
+}
+
+// countCreated counts a new note towards today's total
+func countCreated() {
+	countInsight(func(log *insightLog) { log.Created[dayKey(localNow())]++ })
+}
+
+// countCommand counts a use of a shortcut or subcommand
+func countCommand(name string) {
+	countInsight(func(log *insightLog) { log.Commands[name]++ })
+}
+
+// Shortcut descriptions by key, for counting their uses
+var shortcutNames = func() map[string]string {
+	names := make(map[string]string)
+	for _, b := range bindings {
+		for _, key := range strings.Split(b.key, "/") {
+			names[key] = b.desc
+		}
+	}
+	return names
+}()
+
+// countKey counts the shortcuts pressed in the notes list, and the searches
+// run from its filter. Messages other than keys are not counted.
+func (m model) countKey(msg tea.Msg) {
+	key, ok := msg.(tea.KeyMsg)
+	if !ok || !cfg.Insights || m.mode != "list" {
+		return
+	}
+	if m.list.FilterState() == list.Filtering {
+		if key.Type == tea.KeyEnter && m.list.FilterValue() != "" {
+			countInsight(func(log *insightLog) { log.Searches[dayKey(localNow())]++ })
+		}
+		return
+	}
+	if name, ok := shortcutNames[key.String()]; ok {
+		countCommand(name)
+	}
+}
+
+// flushedInsightLog writes out the counts kept in memory and reads the
+// usage counters with them included
+func flushedInsightLog() insightLog {
+	flushInsights()
+	return loadInsightLog()
+}
+
+// recentTotal adds up the daily counts of the past days, today included
+func recentTotal(counts map[string]int, now time.Time, days int) int {
+	total := 0
+	for i := 0; i < days; i++ {
+		total += counts[dayKey(now.AddDate(0, 0, -i))]
+	}
+	return total
+}
+
+// mostUsed returns the most used shortcuts and commands, most used first
+func mostUsed(commands map[string]int) []string {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if commands[names[i]] != commands[names[j]] {
+			return commands[names[i]] > commands[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	return names[:min(len(names), topCommands)]
+}
+
+// insightsSummary describes the user's habits for the statistics screen
+func insightsSummary(log insightLog, now time.Time) string {
+	if !cfg.Insights {
+		return statsLabelStyle.Render(tr("Set \"insights\": true in the config file to see your habits here"))
+	}
+	line := func(label string, counts map[string]int) string {
+		return statsLabelStyle.Render(fmt.Sprintf("%-14s", tr(label))) +
+			trf("%d today, %d this week, %d this month", counts[dayKey(now)], recentTotal(counts, now, 7), recentTotal(counts, now, 30))
+	}
+	rows := []string{line("Notes created", log.Created), line("Searches", log.Searches)}
+	for i, name := range mostUsed(log.Commands) {
+		label := ""
+		if i == 0 {
+			label = tr("Most used")
+		}
+		rows = append(rows, statsLabelStyle.Render(fmt.Sprintf("%-14s", label))+fmt.Sprintf("%s (%d)", tr(name), log.Commands[name]))
+	}
+	return strings.Join(rows, "\n")
+}
+
+// runInsights implements `gleaner insights [--enable|--disable|--clear]`,
+// printing the usage counters, turning their collection on or off, or
+// deleting them
+func runInsights(args []string) error {
+	flags := flag.NewFlagSet("insights", flag.ContinueOnError)
+	enable := flags.Bool("enable", false, "start keeping usage counters")
+	disable := flags.Bool("disable", false, "stop keeping usage counters")
+	wipe := flags.Bool("clear", false, "delete the usage counters kept so far")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() > 0 || *enable && *disable {
+		return errors.New("usage: gleaner insights [--enable|--disable|--clear]")
+	}
+
+	if *enable || *disable {
+		baseCfg.Insights = *enable
+		cfg.Insights = *enable
+		if err := saveConfig(); err != nil {
+			return err
+		}
+		if *enable {
+			fmt.Println("Usage insights are on; they are kept in", insightLogPath())
+		} else {
+			fmt.Println("Usage insights are off")
+		}
+	}
+	if *wipe {
+		if err := os.Remove(insightLogPath()); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		fmt.Println("Usage insights cleared")
+	}
+	if *enable || *disable || *wipe {
+		return nil
+	}
+
+	if !cfg.Insights {
+		fmt.Println("Usage insights are off; turn them on with gleaner insights --enable")
+		return nil
+	}
+	log, now := flushedInsightLog(), localNow()
+	fmt.Printf("Notes created  %d today, %d this week, %d this month\n", log.Created[dayKey(now)], recentTotal(log.Created, now, 7), recentTotal(log.Created, now, 30))
+	fmt.Printf("Searches       %d today, %d this week, %d this month\n", log.Searches[dayKey(now)], recentTotal(log.Searches, now, 7), recentTotal(log.Searches, now, 30))
+	for _, name := range mostUsed(log.Commands) {
+		fmt.Printf("%6d  %s\n", log.Commands[name], name)
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// movableBinding returns the shortcut from the bindings table that the
+// keymap may move: a single ctrl or alt shortcut, not a navigation key or
+// one of the grouped keys
+func movableBinding(key string) (binding, bool) {
+	if !strings.HasPrefix(key, "ctrl+") && !strings.HasPrefix(key, "alt+") {
+		return binding{}, false
+	}
+	for _, b := range bindings {
+		if b.key == key && !strings.Contains(b.key, "/") {
+			return b, true
+		}
+	}
+	return binding{}, false
+}
+
+// boundKey returns the shortcut a key already triggers, including the
+// grouped ones such as alt+n/alt+w/alt+t
+func boundKey(key string) (binding, bool) {
+	for _, b := range bindings {
+		for _, k := range strings.Split(b.key, "/") {
+			if k == key {
+				return b, true
+			}
+		}
+	}
+	return binding{}, false
+}
+
+// componentKeys returns the keys the notes list, the editor and text fields
+// handle themselves, such as up, backspace or ctrl+w
+func componentKeys() map[string]bool {
+	keys := map[string]bool{}
+	for _, keymap := range []any{list.DefaultKeyMap(), textarea.DefaultKeyMap, textinput.DefaultKeyMap} {
+		v := reflect.ValueOf(keymap)
+		for i := 0; i < v.NumField(); i++ {
+			if b, ok := v.Field(i).Interface().(key.Binding); ok {
+				for _, k := range b.Keys() {
+					keys[k] = true
+				}
+			}
+		}
+	}
+	return keys
+}
+
+// keymapProblems checks a keymap, which moves shortcuts from their keys in
+// the bindings table to others, e.g. "alt+C": "ctrl+y". It reports keys
+// that are not shortcuts, new keys that are not keys, would stop text being
+// typed or are taken by the list or editor, and new keys that clash with
+// another shortcut.
+func keymapProblems(keymap map[string]string) []string {
+	var problems []string
+	actions := make([]string, 0, len(keymap))
+	for action := range keymap {
+		actions = append(actions, action)
+	}
+	sort.Strings(actions)
+
+	taken := map[string]string{}
+	reserved := componentKeys()
+	for _, action := range actions {
+		b, ok := movableBinding(action)
+		if !ok {
+			problems = append(problems, fmt.Sprintf("%q is not a shortcut that can be moved", action))
+			continue
+		}
+		msg, err := parseKey(keymap[action])
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%q for %s is not a key", keymap[action], b.desc))
+			continue
+		}
+		key := msg.String()
+		if msg.Type == tea.KeyRunes && !msg.Alt {
+			problems = append(problems, fmt.Sprintf("%q for %s is a plain character and would stop it being typed", key, b.desc))
+			continue
+		}
+		if reserved[key] && key != action {
+			problems = append(problems, fmt.Sprintf("%q for %s is used by the notes list or the editor", key, b.desc))
+			continue
+		}
+		if other, ok := boundKey(key); ok && other.key != action {
+			problems = append(problems, fmt.Sprintf("conflict: %q for %s is already %s", key, b.desc, other.desc))
+		}
+		if other, ok := taken[key]; ok {
+			problems = append(problems, fmt.Sprintf("conflict: %q is given to both %s and %s", key, other, b.desc))
+		}
+		taken[key] = b.desc
+	}
+	return problems
+}
+
+// shortcutKey returns the key a shortcut from the bindings table is on,
+// after the keymap
+func shortcutKey(key string) string {
+	if moved := cfg.Keymap[key]; moved != "" {
+		return moved
+	}
+	return key
+}
+
+// remapMsg remaps a key pressed in the notes list while neither the filter
+// nor the editor takes typing; other messages pass through unchanged
+func (m model) remapMsg(msg tea.Msg) tea.Msg {
+	if key, ok := msg.(tea.KeyMsg); ok && m.mode == "list" && !m.list.SettingFilter() && !m.textarea.Focused() {
+		return remapKey(key)
+	}
+	return msg
+}
+
+// remapKey turns a key the keymap moved a shortcut to into the shortcut's
+// own key, which is what the rest of the interface handles. The shortcut's
+// own key keeps working too. Keys are only remapped in the notes list, so
+// nothing typed into the editor, a prompt or the filter changes meaning.
+func remapKey(msg tea.KeyMsg) tea.KeyMsg {
+	for action, key := range cfg.Keymap {
+		if moved, err := parseKey(key); err != nil || moved.String() != msg.String() {
+			continue
+		}
+		if original, err := parseKey(action); err == nil {
+			return original
+		}
+	}
+	return msg
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"slices"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestKeymapProblems(t *testing.T) {
+	tests := []struct {
+		name   string
+		keymap map[string]string
+		want   []string
+	}{
+		{"free key", map[string]string{"alt+C": "ctrl+y"}, nil},
+		{"own key", map[string]string{"alt+C": "alt+C"}, nil},
+		{"not a shortcut", map[string]string{"nope": "alt+9"}, []string{`"nope" is not a shortcut that can be moved`}},
+		{"not a key", map[string]string{"alt+C": "hyper+x"}, []string{`"hyper+x" for New from template is not a key`}},
+		{"plain character", map[string]string{"alt+C": "x"}, []string{`"x" for New from template is a plain character and would stop it being typed`}},
+		{"editor key", map[string]string{"alt+C": "ctrl+k"}, []string{`"ctrl+k" for New from template is used by the notes list or the editor`}},
+		{"another shortcut", map[string]string{"alt+C": "alt+Y"}, []string{`conflict: "alt+Y" for New from template is already Copy code`}},
+		{"two shortcuts on one key", map[string]string{"alt+C": "alt+9", "alt+X": "alt+9"}, []string{`conflict: "alt+9" is given to both New from template and Rename titles`}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := keymapProblems(tt.keymap); !slices.Equal(got, tt.want) {
+				t.Errorf("keymapProblems(%v) = %q, want %q", tt.keymap, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRemapMsg(t *testing.T) {
+	oldCfg := cfg
+	t.Cleanup(func() { cfg = oldCfg })
+	cfg = config{Keymap: map[string]string{"alt+C": "ctrl+y"}}
+	moved := tea.KeyMsg{Type: tea.KeyCtrlY}
+
+	m := initialModel()
+	if got := m.remapMsg(moved); got.(tea.KeyMsg).String() != "alt+C" {
+		t.Errorf("ctrl+y in the list is %v, want alt+C", got)
+	}
+	if got := m.remapMsg(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'C'}, Alt: true}); got.(tea.KeyMsg).String() != "alt+C" {
+		t.Errorf("alt+C stopped working: %v", got)
+	}
+	if got := m.remapMsg(tea.WindowSizeMsg{Width: 80}); got != (tea.WindowSizeMsg{Width: 80}) {
+		t.Errorf("other message changed to %v", got)
+	}
+	m.mode = "new"
+	if got := m.remapMsg(moved); got.(tea.KeyMsg).String() != "ctrl+y" {
+		t.Errorf("ctrl+y in the editor is %v, want it left alone", got)
+	}
+}
@@ -0,0 +1,97 @@
+package main
+
+import "strings"
+
+// binding describes a keyboard shortcut shown in the help line
+type binding struct {
+	key    string // Key as displayed to the user
+	desc   string // Short description of the action
+	writes bool   // Action modifies the vault and is unavailable in read-only mode
+}
+
+// Shortcuts listed in the help line, in display order
+var bindings = []binding{
+	{key: "↑/↓", desc: "Navigate"},
+	{key: "enter", desc: "View"},
+	{key: "esc", desc: "Back"},
+	{key: "ctrl+n", desc: "New", writes: true},
+	{key: "ctrl+s", desc: "Save", writes: true},
+	{key: "ctrl+e", desc: "Edit", writes: true},
+	{key: "ctrl+d", desc: "Delete", writes: true},
+	{key: "ctrl+u", desc: "Refresh"},
+	{key: "ctrl+l", desc: "Density"},
+	{key: "alt+o", desc: "Sort order"},
+	{key: "ctrl+t", desc: "Search scope"},
+	{key: "alt+d", desc: "Date filter"},
+	{key: "alt+j", desc: "Label", writes: true},
+	{key: "alt+J", desc: "Label filter"},
+	{key: "alt+h", desc: "Timeline"},
+	{key: "alt+a", desc: "Stats"},
+	{key: "ctrl+←/→", desc: "Resize"},
+	{key: "tab", desc: "Switch pane"},
+	{key: "alt+l/alt+c", desc: "Hide list/content"},
+	{key: "alt+z", desc: "Zen"},
+	{key: "alt+x", desc: "Scratchpad"},
+	{key: "alt+g", desc: "Outline"},
+	{key: "ctrl+]", desc: "Footnote"},
+	{key: "alt+v", desc: "Preview"},
+	{key: "alt+b", desc: "Side by side"},
+	{key: "alt+m", desc: "Mark to compare"},
+	{key: "alt+y", desc: "Copy as HTML"},
+	{key: "alt+Y", desc: "Copy code"},
+	{key: "alt+N", desc: "New bookmark", writes: true},
+	{key: "alt+B", desc: "Open bookmark"},
+	{key: "alt+C", desc: "New from template", writes: true},
+	{key: "alt+X", desc: "Rename titles", writes: true},
+	{key: "alt+G", desc: "Change tags", writes: true},
+	{key: "alt+A", desc: "Find and replace", writes: true},
+	{key: "alt+W", desc: "Record macro"},
+	{key: "alt+P", desc: "Play macro"},
+	{key: "alt+M", desc: "New meeting", writes: true},
+	{key: "alt+T", desc: "Tasks"},
+	{key: "alt+R", desc: "Review flashcards"},
+	{key: "alt+Q", desc: "Quiz"},
+	{key: "alt+I", desc: "Surprise me"},
+	{key: "alt+H", desc: "On this day"},
+	{key: "alt+L", desc: "Change log"},
+	{key: "alt+D", desc: "Trash", writes: true},
+	{key: "alt+U", desc: "Duplicates"},
+	{key: "alt+K", desc: "Broken links"},
+	{key: "alt+O", desc: "Orphans"},
+	{key: "alt+F", desc: "Unused attachments", writes: true},
+	{key: "alt+E", desc: "Error details"},
+	{key: "alt+s", desc: "Share gist", writes: true},
+	{key: "alt+p", desc: "Share paste"},
+	{key: "alt+e", desc: "Details"},
+	{key: "alt+u", desc: "Fields"},
+	{key: "alt+S", desc: "Status", writes: true},
+	{key: ".", desc: "Repeat", writes: true},
+	{key: "alt+q", desc: "QR code"},
+	{key: "alt+k", desc: "Copy link"},
+	{key: "alt+n/alt+w/alt+t", desc: "Line numbers/wrap/tab width"},
+	{key: "alt+r/alt+i/alt+f", desc: "Table row/column/format", writes: true},
+	{key: "ctrl+b", desc: "Backup"},
+	{key: "ctrl+o", desc: "Vaults"},
+	{key: "ctrl+p", desc: "Plugins"},
+	{key: "ctrl+c", desc: "Quit"},
+}
+
+// helpText provides quick reference for the shortcuts currently available
+func helpText() string {
+	var parts []string
+	for _, b := range bindings {
+		if b.writes && readOnly {
+			continue
+		}
+		if b.key == "ctrl+o" && len(baseCfg.Vaults) == 0 {
+			continue
+		}
+		parts = append(parts, shortcutKey(b.key)+":"+tr(b.desc))
+	}
+
+	text := tr("Navigation: ") + strings.Join(parts, " | ")
+	if readOnly {
+		text = tr("[read-only] ") + text
+	}
+	return text
+}
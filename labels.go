@@ -0,0 +1,122 @@
+package main
+
+import (
+	"slices"
+	"sort"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Colour labels that can be given to a note with "label" in its
+// frontmatter, in the order they are offered
+var labelNames = []string{"red", "orange", "yellow", "green", "blue", "purple", "gray"}
+
+// Terminal colours of the labels; labels typed by hand that are not listed
+// here are drawn in labelColors["gray"]
+var labelColors = map[string]lipgloss.Color{
+	"red":    "196",
+	"orange": "208",
+	"yellow": "220",
+	"green":  "40",
+	"blue":   "33",
+	"purple": "135",
+	"gray":   "245",
+}
+
+// labelMarker renders the coloured dot that marks a labeled note, or the
+// label's name when colours are off
+func labelMarker(label string) string {
+	if noColor {
+		return badgeStyle.Reverse(true).Render(tr(label))
+	}
+	color, ok := labelColors[label]
+	if !ok {
+		color = labelColors["gray"]
+	}
+	return lipgloss.NewStyle().Foreground(color).Render("●")
+}
+
+// filterByLabel keeps the notes with the given label, or all of them when
+// label is empty
+func filterByLabel(notes []note, label string) []note {
+	if label == "" {
+		return notes
+	}
+	var kept []note
+	for _, n := range notes {
+		if n.label == label {
+			kept = append(kept, n)
+		}
+	}
+	return kept
+}
+
+// openLabelPicker offers the labels for the selected note
+func (m model) openLabelPicker() model {
+	if m.selectedNote == nil {
+		return m
+	}
+	items := []pickerItem{{title: tr("No label"), value: ""}}
+	for _, name := range labelNames {
+		items = append(items, pickerItem{title: labelMarker(name) + " " + tr(name), value: name})
+	}
+	return m.openPicker("label", trf("Label for %s", m.selectedNote.title), items)
+}
+
+// setLabel writes the chosen label into the selected note's frontmatter
+func (m model) setLabel(label string) (tea.Model, tea.Cmd) {
+	if readOnly {
+		m.status = errReadOnly.Error()
+		return m, nil
+	}
+	path := m.selectedNote.path
+	content, err := readNoteFile(path)
+	if err != nil {
+		m.status = err.Error()
+		return m, nil
+	}
+	var updated string
+	if label == "" {
+		updated = deleteFrontmatter(string(content), "label")
+	} else {
+		updated = setFrontmatter(string(content), "label", label)
+	}
+	if err := writeNoteFile(path, updated); err != nil {
+		m.status = err.Error()
+		return m, nil
+	}
+	m.lastAction = &fieldAction{key: "label", value: label}
+	return m, loadNotes
+}
+
+// openLabelFilter offers to show only the notes with one label, listing
+// the standard labels and any others in use
+func (m model) openLabelFilter() model {
+	names := append([]string{}, labelNames...)
+	var custom []string
+	for _, n := range m.notes {
+		if _, known := labelColors[n.label]; n.label != "" && !known && !slices.Contains(custom, n.label) {
+			custom = append(custom, n.label)
+		}
+	}
+	sort.Strings(custom)
+	names = append(names, custom...)
+
+	items := []pickerItem{{title: tr("All labels"), desc: tr("Show all notes"), value: ""}}
+	for _, name := range names {
+		items = append(items, pickerItem{title: labelMarker(name) + " " + tr(name), value: name})
+	}
+	return m.openPicker("label-filter", tr("Filter by label"), items)
+}
+
+// applyLabelFilter limits the list to notes with the chosen label
+func (m model) applyLabelFilter(label string) (tea.Model, tea.Cmd) {
+	m.labelFilter = label
+	if label == "" {
+		m.status = tr("Showing notes with any label")
+	} else {
+		m.status = trf("Showing %s notes", tr(label))
+	}
+	return m, loadNotes
+}
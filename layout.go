@@ -0,0 +1,140 @@
+package main
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Split ratio limits and step size for resizing the panes
+const (
+	defaultSplitRatio = 0.3
+	minSplitRatio     = 0.15
+	maxSplitRatio     = 0.7
+	splitStep         = 0.05
+
+	minPaneWidth = 12 // Narrowest a pane may get, border included
+)
+
+// clampSplit keeps a split ratio within usable bounds
+func clampSplit(ratio float64) float64 {
+	if ratio == 0 {
+		return defaultSplitRatio
+	}
+	return max(minSplitRatio, min(maxSplitRatio, ratio))
+}
+
+// paneWidths returns the width to give the list and content boxes, not
+// counting their borders
+func (m model) paneWidths() (int, int) {
+	// docStyle pads 2 columns on each side; each box has a 1-column border
+	available := m.width - 4
+	switch {
+	case m.hideList:
+		return 0, max(0, available-2)
+	case m.hideContent:
+		return max(0, available-2), 0
+	}
+	listTotal := int(float64(available) * m.splitRatio)
+	listTotal = max(minPaneWidth, min(available-minPaneWidth, listTotal))
+	contentTotal := available - listTotal
+	return max(0, listTotal-2), max(0, contentTotal-2)
+}
+
+// resize sizes the list, editor and picker to the current window and split
+func (m model) resize() model {
+	listWidth, contentWidth := m.paneWidths()
+	m.list.SetSize(listWidth-2, m.height-10)
+	m.textarea.SetWidth(contentWidth - 2)
+	m.textarea.SetHeight(m.height - 12)
+	if m.sideNote != nil || m.preview && (m.mode == "new" || m.mode == "edit") {
+		// Editor and preview or side note share the content pane
+		m.textarea.SetWidth(contentWidth/2 - 2)
+	}
+	if m.zen {
+		m.textarea.SetWidth(min(zenWidth(), m.width-4))
+		m.textarea.SetHeight(m.height - 8)
+	}
+	m.picker.SetSize(m.width-8, m.height-10)
+	m.scratch.SetWidth(contentWidth - 2)
+	m.scratch.SetHeight(m.height - 12)
+	m.diff.Width = m.width - 10
+	m.diff.Height = m.height - 9
+	return m.layoutSide(contentWidth)
+}
+
+// toggleListPane hides or shows the list, giving the content the full width
+func (m model) toggleListPane() model {
+	m.hideList = !m.hideList
+	m.hideContent = false
+	return m.focusContent(m.hideList).resize()
+}
+
+// toggleContentPane hides or shows the content, giving the list the full width
+func (m model) toggleContentPane() model {
+	m.hideContent = !m.hideContent
+	m.hideList = false
+	return m.focusContent(false).resize()
+}
+
+// focusContent moves browsing focus to the content pane or back to the list.
+// Focus stays on whichever pane is the only one visible.
+func (m model) focusContent(content bool) model {
+	if m.hideList {
+		content = true
+	} else if m.hideContent {
+		content = false
+	}
+	if m.mode != "list" {
+		return m
+	}
+
+	m.contentFocus = content
+	if content {
+		m.textarea.Focus()
+	} else {
+		m.textarea.Blur()
+	}
+	return m
+}
+
+// isScrollKey reports whether a key only moves around the content
+func isScrollKey(msg tea.KeyMsg) bool {
+	switch msg.Type {
+	case tea.KeyUp, tea.KeyDown, tea.KeyLeft, tea.KeyRight,
+		tea.KeyPgUp, tea.KeyPgDown, tea.KeyHome, tea.KeyEnd,
+		tea.KeyCtrlHome, tea.KeyCtrlEnd:
+		return true
+	}
+	return false
+}
+
+// divider returns the vertical rule between two halves of the content pane
+func divider(height int) string {
+	return strings.TrimSuffix(strings.Repeat(" │ \n", max(1, height)), "\n")
+}
+
+// paneStyle returns the bordered pane style, highlighted when focused
+func paneStyle(focused bool) lipgloss.Style {
+	if focused && noColor {
+		return splitStyle.Border(lipgloss.ThickBorder())
+	}
+	if focused {
+		return splitStyle.BorderForeground(focusColor)
+	}
+	return splitStyle
+}
+
+// adjustSplit grows or shrinks the list pane and remembers the new ratio
+func (m model) adjustSplit(delta float64) model {
+	m.splitRatio = clampSplit(m.splitRatio + delta)
+	m = m.resize()
+
+	cfg.SplitRatio = m.splitRatio
+	baseCfg.SplitRatio = m.splitRatio
+	if err := saveConfig(); err != nil {
+		m.status = trf("Could not save split ratio: %v", err)
+	}
+	return m
+}
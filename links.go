@@ -0,0 +1,68 @@
+package main
+
+import (
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Matches [[Title]] and [[Title|label]] wiki links, capturing the title
+var wikiLinkPattern = regexp.MustCompile(`\[\[([^\]|]+)(?:\|[^\]]*)?\]\]`)
+
+// resolveLink finds the note a link target points to: a gleaner:// link, a
+// note file name or path, or a note title
+func resolveLink(notes []note, target string) (note, bool) {
+	target = strings.TrimSpace(target)
+	if strings.HasPrefix(target, noteURIScheme+"://") {
+		id, _, err := parseNoteURI(target)
+		if err != nil {
+			return note{}, false
+		}
+		target = id
+	} else if strings.Contains(target, "://") || strings.HasPrefix(target, "#") || strings.HasPrefix(target, "mailto:") {
+		return note{}, false
+	}
+
+	// Links to files may be escaped and carry a heading anchor
+	target, _, _ = strings.Cut(target, "#")
+	if unescaped, err := url.PathUnescape(target); err == nil {
+		target = unescaped
+	}
+	if isNoteExt(filepath.Ext(target)) {
+		for _, n := range notes {
+			if filepath.Base(n.path) == filepath.Base(target) {
+				return n, true
+			}
+		}
+		return note{}, false
+	}
+
+	n, err := findNote(notes, target)
+	return n, err == nil
+}
+
+// noteLinks returns the paths of the other notes a note's body links to,
+// each listed once
+func noteLinks(notes []note, n note) []string {
+	var targets []string
+	for _, match := range linkPattern.FindAllStringSubmatch(n.body, -1) {
+		targets = append(targets, match[2])
+	}
+	for _, match := range wikiLinkPattern.FindAllStringSubmatch(n.body, -1) {
+		targets = append(targets, match[1])
+	}
+	if mentionPattern != nil {
+		targets = append(targets, mentionPattern.FindAllString(n.body, -1)...)
+	}
+
+	seen := make(map[string]bool)
+	var links []string
+	for _, target := range targets {
+		if linked, ok := resolveLink(notes, target); ok && linked.path != n.path && !seen[linked.path] {
+			seen[linked.path] = true
+			links = append(links, linked.path)
+		}
+	}
+	return links
+}
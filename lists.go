@@ -0,0 +1,82 @@
+package main
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Matches a markdown list item: indentation, marker, optional checkbox, text
+var listItemPattern = regexp.MustCompile(`^(\s*)([-*+]|(\d+)([.)]))(\s+)(\[[ xX]\]\s+)?(.*)$`)
+
+// continueList handles Enter on a list item: it starts the next item with
+// the same marker, or ends the list when the current item is empty. It
+// reports false when the cursor is not on a list item.
+func (m model) continueList() (model, bool) {
+	lines := strings.Split(m.textarea.Value(), "\n")
+	row, col := cursorPos(m.textarea)
+	line := []rune(lines[row])
+	match := listItemPattern.FindStringSubmatch(string(line))
+	if match == nil {
+		return m, false
+	}
+	indent, bullet, number, delim, gap, checkbox, text := match[1], match[2], match[3], match[4], match[5], match[6], match[7]
+
+	// Enter on an empty item removes the marker and leaves the list
+	if strings.TrimSpace(text) == "" {
+		lines[row] = ""
+		replaceValue(&m.textarea, strings.Join(lines, "\n"), row, 0)
+		return m, true
+	}
+
+	marker := bullet
+	if number != "" {
+		n, _ := strconv.Atoi(number)
+		marker = strconv.Itoa(n+1) + delim
+	}
+	if checkbox != "" {
+		checkbox = "[ ] "
+	}
+	prefix := indent + marker + gap + checkbox
+
+	// Split the line at the cursor; the rest moves to the new item
+	head, tail := string(line[:col]), strings.TrimLeft(string(line[col:]), " ")
+	lines[row] = head
+	lines = append(lines[:row+1], append([]string{prefix + tail}, lines[row+1:]...)...)
+	if number != "" {
+		renumberList(lines, row+1)
+	}
+
+	replaceValue(&m.textarea, strings.Join(lines, "\n"), row+1, len([]rune(prefix)))
+	return m, true
+}
+
+// renumberList rewrites the numbers of the ordered items following row at the
+// same indentation so they count up from row's number
+func renumberList(lines []string, row int) {
+	match := listItemPattern.FindStringSubmatch(lines[row])
+	if match == nil || match[3] == "" {
+		return
+	}
+	indent := match[1]
+	n, _ := strconv.Atoi(match[3])
+
+	for i := row + 1; i < len(lines); i++ {
+		next := listItemPattern.FindStringSubmatch(lines[i])
+		if next == nil {
+			// Deeper-indented continuation lines don't end the list
+			if strings.HasPrefix(lines[i], indent+" ") && strings.TrimSpace(lines[i]) != "" {
+				continue
+			}
+			return
+		}
+		if len(next[1]) > len(indent) {
+			continue
+		}
+		if next[1] != indent || next[3] == "" {
+			return
+		}
+		n++
+		lines[i] = indent + strconv.Itoa(n) + next[4] + next[5] + next[6] + next[7]
+	}
+}
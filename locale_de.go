@@ -0,0 +1,459 @@
+package main
+
+// German translations of the UI
+var catalogDE = map[string]string{
+	// Help line
+	"Navigation: ":                "Navigation: ",
+	"[read-only] ":                "[schreibgeschützt] ",
+	"Navigate":                    "Bewegen",
+	"View":                        "Anzeigen",
+	"Back":                        "Zurück",
+	"New":                         "Neu",
+	"Save":                        "Speichern",
+	"Edit":                        "Bearbeiten",
+	"Delete":                      "Löschen",
+	"Refresh":                     "Aktualisieren",
+	"Density":                     "Dichte",
+	"Sort order":                  "Sortierung",
+	"Search scope":                "Suchbereich",
+	"Date filter":                 "Datumsfilter",
+	"Timeline":                    "Zeitleiste",
+	"Stats":                       "Statistik",
+	"Resize":                      "Größe",
+	"Switch pane":                 "Bereich wechseln",
+	"Hide list/content":           "Liste/Inhalt ausblenden",
+	"Zen":                         "Zen",
+	"Scratchpad":                  "Notizblock",
+	"Outline":                     "Gliederung",
+	"Preview":                     "Vorschau",
+	"Side by side":                "Nebeneinander",
+	"Mark to compare":             "Zum Vergleich markieren",
+	"Copy as HTML":                "Als HTML kopieren",
+	"Copy code":                   "Code kopieren",
+	"New bookmark":                "Neues Lesezeichen",
+	"Open bookmark":               "Lesezeichen öffnen",
+	"New meeting":                 "Neues Meeting",
+	"Tasks":                       "Aufgaben",
+	"Review flashcards":           "Lernkarten wiederholen",
+	"Quiz":                        "Quiz",
+	"Surprise me":                 "Überrasch mich",
+	"On this day":                 "An diesem Tag",
+	"Share gist":                  "Als Gist teilen",
+	"Share paste":                 "Als Paste teilen",
+	"QR code":                     "QR-Code",
+	"Copy link":                   "Link kopieren",
+	"Line numbers/wrap/tab width": "Zeilennummern/Umbruch/Tabbreite",
+	"Table row/column/format":     "Tabellenzeile/-spalte/-format",
+	"Backup":                      "Sicherung",
+	"Vaults":                      "Tresore",
+	"Plugins":                     "Plugins",
+	"Quit":                        "Beenden",
+
+	"enter:Select | /:Filter | esc:Cancel":         "enter:Auswählen | /:Filtern | esc:Abbrechen",
+	"enter:Confirm | esc:Cancel":                   "enter:Bestätigen | esc:Abbrechen",
+	"esc:Back":                                     "esc:Zurück",
+	"↑/↓/pgup/pgdn:Scroll | esc:Back":              "↑/↓/pgup/pgdn:Blättern | esc:Zurück",
+	"tab:Switch field | ctrl+s:Save | esc:Discard": "tab:Feld wechseln | ctrl+s:Speichern | esc:Verwerfen",
+
+	// Editor and list
+	"Notes":     "Notizen",
+	"No notes.": "Keine Notizen.",
+	"pinned":    "angeheftet",
+	"archived":  "archiviert",
+	"locked":    "gesperrt",
+	"Note title (Press Tab to enter content)": "Titel der Notiz (Tab wechselt zum Inhalt)",
+	"Enter note content (Ctrl+S to save)...":  "Inhalt eingeben (Strg+S speichert)...",
+	"Title":                                   "Titel",
+	"Note (Ctrl+S to save)":                   "Notiz (Strg+S speichert)",
+	"A title is required":                     "Ein Titel ist erforderlich",
+	"Saved %s":                                "%s gespeichert",
+	"Scratchpad: saved as you type":           "Notizblock: wird beim Tippen gespeichert",
+	"Line numbers %s":                         "Zeilennummern %s",
+	"Soft wrap %s":                            "Zeilenumbruch %s",
+	"Tab width %d":                            "Tabbreite %d",
+	"on":                                      "an",
+	"off":                                     "aus",
+	"line %d":                                 "Zeile %d",
+	"No headings in this note":                "Diese Notiz hat keine Überschriften",
+	"Not inside a table":                      "Nicht in einer Tabelle",
+	"%d words":                                "%d Wörter",
+	"%d/%d words":                             "%d/%d Wörter",
+	"%d/%d words (%d%%)":                      "%d/%d Wörter (%d%%)",
+	"space:Toggle | a:Add | e:Edit | d:Delete | shift+↑/↓:Move | esc:Back": "Leertaste:Abhaken | a:Neu | e:Ändern | d:Löschen | Umschalt+↑/↓:Verschieben | esc:Zurück",
+	"New item":         "Neuer Eintrag",
+	"Item":             "Eintrag",
+	"open":             "offen",
+	"done":             "erledigt",
+	"%s, %s, %d of %d": "%s, %s, %d von %d",
+
+	// Search and filters
+	"search %s (ctrl+t to change)": "%s durchsuchen (ctrl+t wechselt)",
+	"Searching %s":                 "Durchsuche %s",
+	"titles":                       "Titel",
+	"contents":                     "Inhalte",
+	"tags":                         "Tags",
+	"everything":                   "alles",
+	"Filter by date":               "Nach Datum filtern",
+	"Any time":                     "Beliebige Zeit",
+	"Show all notes":               "Alle Notizen anzeigen",
+	"created %s":                   "erstellt %s",
+	"modified %s":                  "geändert %s",
+	"in a custom range…":           "in einem eigenen Zeitraum…",
+	"between":                      "zwischen",
+	"e.g. 2024-03, 2024-03-01..2024-03-15 or 2024-01..": "z. B. 2024-03, 2024-03-01..2024-03-15 oder 2024-01..",
+	"Showing notes from any time":                       "Zeige Notizen aus beliebiger Zeit",
+	"Showing notes %s":                                  "Zeige Notizen %s",
+	"today":                                             "heute",
+	"yesterday":                                         "gestern",
+	"this week":                                         "diese Woche",
+	"last week":                                         "letzte Woche",
+	"this month":                                        "diesen Monat",
+	"last month":                                        "letzten Monat",
+	"this year":                                         "dieses Jahr",
+	"Today":                                             "Heute",
+	"Yesterday":                                         "Gestern",
+	"This week":                                         "Diese Woche",
+
+	"Label":                        "Farbe",
+	"Label filter":                 "Farbfilter",
+	"No label":                     "Keine Farbe",
+	"Label for %s":                 "Farbe für %s",
+	"All labels":                   "Alle Farben",
+	"Filter by label":              "Nach Farbe filtern",
+	"Showing notes with any label": "Zeige Notizen jeder Farbe",
+	"Showing %s notes":             "Zeige Notizen in %s",
+	"red":                          "Rot",
+	"orange":                       "Orange",
+	"yellow":                       "Gelb",
+	"green":                        "Grün",
+	"blue":                         "Blau",
+	"purple":                       "Lila",
+	"gray":                         "Grau",
+
+	// Status messages
+	"Copying...":                                       "Kopiere...",
+	"Copy failed: %v":                                  "Kopieren fehlgeschlagen: %v",
+	"Copied %s":                                        "%s kopiert",
+	"Copied note as HTML":                              "Notiz als HTML kopiert",
+	"Copied HTML source as text":                       "HTML-Quelltext als Text kopiert",
+	"No code in this note":                             "Diese Notiz enthält keinen Code",
+	"Copied %d lines of code":                          "%d Zeilen Code kopiert",
+	"URL":                                              "URL",
+	"Fetching %s...":                                   "Lade %s...",
+	"Saved bookmark %s":                                "Lesezeichen %s gespeichert",
+	"Saved %s without page details: %v":                "%s ohne Seitendetails gespeichert: %v",
+	"Could not save the bookmark: %v":                  "Lesezeichen konnte nicht gespeichert werden: %v",
+	"This note has no web address":                     "Diese Notiz hat keine Webadresse",
+	"Could not open the browser: %v":                   "Browser konnte nicht geöffnet werden: %v",
+	"Opened %s":                                        "%s geöffnet",
+	"Attendees (comma-separated)":                      "Teilnehmer (durch Kommas getrennt)",
+	"Agenda (separate items with ;)":                   "Tagesordnung (Punkte mit ; trennen)",
+	"Meeting %s":                                       "Meeting %s",
+	"No open tasks":                                    "Keine offenen Aufgaben",
+	"Open tasks (%d)":                                  "Offene Aufgaben (%d)",
+	"The note is hidden by the current filter":         "Die Notiz ist durch den aktuellen Filter ausgeblendet",
+	"%d flashcards due, alt+R to review":               "%d Lernkarten fällig, alt+R zum Wiederholen",
+	"No flashcards due":                                "Keine Lernkarten fällig",
+	"%d flashcards left for later":                     "%d Lernkarten für später übrig",
+	"Could not save the review: %v":                    "Wiederholung konnte nicht gespeichert werden: %v",
+	"Reviewed %d flashcards":                           "%d Lernkarten wiederholt",
+	"Card %d of %d · %s":                               "Karte %d von %d · %s",
+	"0-5:Grade (0 forgot, 5 easy) | esc:Back":          "0-5:Bewerten (0 vergessen, 5 leicht) | esc:Zurück",
+	"space:Show answer | esc:Back":                     "Leertaste:Antwort zeigen | esc:Zurück",
+	"Reviewing flashcards":                             "Lernkarten wiederholen",
+	"No sections with text under a heading to quiz on": "Keine Abschnitte mit Text unter einer Überschrift für ein Quiz",
+	"Could not save the score: %v":                     "Punktestand konnte nicht gespeichert werden: %v",
+	"Recalled %d of %d sections":                       "%d von %d Abschnitten gewusst",
+	"%d of %d times":                                   "%d von %d Mal",
+	"Section %d of %d":                                 "Abschnitt %d von %d",
+	"recalled %d of %d times":                          "%d von %d Mal gewusst",
+	"y:Recalled | n:Forgot | esc:Back":                 "y:Gewusst | n:Vergessen | esc:Zurück",
+	"How about %s?":                                    "Wie wäre es mit %s?",
+	"1 year ago":                                       "vor 1 Jahr",
+	"%d years ago":                                     "vor %d Jahren",
+	"1 month ago":                                      "vor 1 Monat",
+	"%d months ago":                                    "vor %d Monaten",
+	"Nothing was written on this day before":           "An diesem Tag wurde früher nichts geschrieben",
+	"On this day, %s %d":                               "An diesem Tag, %[2]d. %[1]s",
+	"private key":                                      "privater Schlüssel",
+	"AWS access key":                                   "AWS-Zugriffsschlüssel",
+	"AWS secret key":                                   "geheimer AWS-Schlüssel",
+	"GitHub token":                                     "GitHub-Token",
+	"Slack token":                                      "Slack-Token",
+	"Stripe key":                                       "Stripe-Schlüssel",
+	"Google API key":                                   "Google-API-Schlüssel",
+	"API key":                                          "API-Schlüssel",
+	"password or token":                                "Passwort oder Token",
+	"This note looks like it contains: %s. Type yes to share it anyway":              "Diese Notiz scheint Folgendes zu enthalten: %s. Gib ja ein, um sie trotzdem zu teilen",
+	"This note looks like it contains: %s. Type yes to publish it anyway":            "Diese Notiz scheint Folgendes zu enthalten: %s. Gib ja ein, um sie trotzdem zu veröffentlichen",
+	"These notes look like they contain credentials: %s. Type yes to back up anyway": "Diese Notizen scheinen Zugangsdaten zu enthalten: %s. Gib ja ein, um sie trotzdem zu sichern",
+	"yes":                            "ja",
+	"Not shared":                     "Nicht geteilt",
+	"No backup written":              "Keine Sicherung geschrieben",
+	"Not published":                  "Nicht veröffentlicht",
+	"and %d more":                    "und %d weitere",
+	"%s created %s":                  "%s hat %s erstellt",
+	"%s deleted %s":                  "%s hat %s gelöscht",
+	"%s restored %s":                 "%s hat %s wiederhergestellt",
+	"%s renamed a note to %s":        "%s hat eine Notiz in %s umbenannt",
+	"%s edited %s":                   "%s hat %s bearbeitet",
+	"word":                           "Wort",
+	"No changes logged yet":          "Noch keine Änderungen protokolliert",
+	"Change log":                     "Änderungsprotokoll",
+	"That note no longer exists":     "Diese Notiz existiert nicht mehr",
+	"words":                          "Wörter",
+	"The trash is empty":             "Der Papierkorb ist leer",
+	"Empty trash now":                "Papierkorb jetzt leeren",
+	"Delete %d notes for good":       "%d Notizen endgültig löschen",
+	"Deleted %s":                     "Gelöscht %s",
+	"purged in %d days":              "wird in %d Tagen endgültig gelöscht",
+	"Trash (%s)":                     "Papierkorb (%s)",
+	"Purged %d notes (%s)":           "%d Notizen endgültig gelöscht (%s)",
+	"Restored %s":                    "%s wiederhergestellt",
+	"purged within a day":            "wird innerhalb eines Tages endgültig gelöscht",
+	"Trash":                          "Papierkorb",
+	"Identical":                      "Identisch",
+	"%d%% similar":                   "%d%% ähnlich",
+	"No duplicate notes found":       "Keine doppelten Notizen gefunden",
+	"%d possible duplicates":         "%d mögliche Duplikate",
+	"Compare":                        "Vergleichen",
+	"Merge into %s":                  "In %s zusammenführen",
+	"Append %s and delete it":        "%s anhängen und löschen",
+	"Delete %s":                      "%s löschen",
+	"Merged":                         "Zusammengeführt",
+	"Duplicates":                     "Duplikate",
+	"No broken links":                "Keine defekten Links",
+	"In %s, line %d":                 "In %s, Zeile %d",
+	"%d broken links":                "%d defekte Links",
+	"Create %s":                      "%s erstellen",
+	"New empty note":                 "Neue leere Notiz",
+	"Link to %s":                     "Auf %s verlinken",
+	"Open %s":                        "%s öffnen",
+	"Line %d":                        "Zeile %d",
+	"Created %s":                     "%s erstellt",
+	"Linked to %s":                   "Auf %s verlinkt",
+	"Broken links":                   "Defekte Links",
+	"Every note is linked or tagged": "Jede Notiz ist verlinkt oder getaggt",
+	"%d orphaned notes":              "%d verwaiste Notizen",
+	"Orphans":                        "Verwaiste Notizen",
+	"Every attachment is used":       "Alle Anhänge werden verwendet",
+	"Delete all":                     "Alle löschen",
+	"Type yes to delete every trashed note for good":      "Gib ja ein, um alle Notizen im Papierkorb endgültig zu löschen",
+	"Type yes to delete every unused attachment for good": "Gib ja ein, um alle ungenutzten Anhänge endgültig zu löschen",
+	"Nothing deleted":                     "Nichts gelöscht",
+	"Free %s":                             "%s freigeben",
+	"Archive all":                         "Alle archivieren",
+	"Move them into a zip in %s":          "In eine Zip-Datei in %s verschieben",
+	"%d unused attachments (%s)":          "%d ungenutzte Anhänge (%s)",
+	"Archive":                             "Archivieren",
+	"Move it into a zip in %s":            "In eine Zip-Datei in %s verschieben",
+	"Archived %d files to %s, freeing %s": "%d Dateien nach %s archiviert, %s freigegeben",
+	"Deleted %d files, freeing %s":        "%d Dateien gelöscht, %s freigegeben",
+	"Unused attachments":                  "Ungenutzte Anhänge",
+	"Your habits":                         "Deine Gewohnheiten",
+	"Set \"insights\": true in the config file to see your habits here":        "Setze \"insights\": true in der Konfigurationsdatei, um hier deine Gewohnheiten zu sehen",
+	"%d today, %d this week, %d this month":                                    "%d heute, %d diese Woche, %d diesen Monat",
+	"Most used":                                                                "Am häufigsten",
+	"Error details":                                                            "Fehlerdetails",
+	"No errors so far":                                                         "Bisher keine Fehler",
+	"alt+E:Details":                                                            "alt+E:Details",
+	"Could not save the note":                                                  "Die Notiz konnte nicht gespeichert werden",
+	"Could not delete the note":                                                "Die Notiz konnte nicht gelöscht werden",
+	"This note cannot be read: %v":                                             "Diese Notiz kann nicht gelesen werden: %v",
+	"unreadable":                                                               "unlesbar",
+	"Opened read-only because notes cannot be written: %s":                     "Schreibgeschützt geöffnet, weil keine Notizen geschrieben werden können: %s",
+	"Your text is still in the editor; press ctrl+s to try again":              "Dein Text ist noch im Editor; drücke ctrl+s, um es erneut zu versuchen",
+	"check the owner and permissions of the notes directory":                   "prüfe Besitzer und Berechtigungen des Notizverzeichnisses",
+	"the disk is full, free some space and try again":                          "der Datenträger ist voll, gib Speicherplatz frei und versuche es erneut",
+	"the file system is mounted read-only":                                     "das Dateisystem ist schreibgeschützt eingehängt",
+	"Nothing saved; the note on disk is unchanged":                             "Nichts gespeichert; die Notiz auf der Festplatte ist unverändert",
+	"Merged text in the editor; press ctrl+s to save it":                       "Zusammengeführter Text im Editor; ctrl+s speichert ihn",
+	"%d conflicts still to settle":                                             "Noch %d Konflikte zu klären",
+	"%s changed on disk while you were editing it; the changes merge cleanly":  "%s wurde auf der Festplatte geändert, während du sie bearbeitet hast; die Änderungen lassen sich sauber zusammenführen",
+	"%s changed on disk while you were editing it: %d of %d conflicts settled": "%s wurde auf der Festplatte geändert, während du sie bearbeitet hast: %d von %d Konflikten geklärt",
+	"mine": "meins",
+	"disk": "Festplatte",
+	"n/p:Next/previous conflict | m/d/b:Keep mine/disk/both | M/D:All mine/disk | e:Edit merge | enter:Save | esc:Back": "n/p:Nächster/vorheriger Konflikt | m/d/b:Meins/Festplatte/beides behalten | M/D:Alles meins/Festplatte | e:Zusammenführung bearbeiten | enter:Speichern | esc:Zurück",
+	"Merging %s":                                  "%s wird zusammengeführt",
+	"Image not found: %s":                         "Bild nicht gefunden: %s",
+	"Diagram not drawn, shown as written":         "Diagramm nicht gezeichnet, wie geschrieben angezeigt",
+	"Footnote":                                    "Fußnote",
+	"Footnote %s has no definition":               "Fußnote %s ist nicht definiert",
+	"Footnote %s is never referenced":             "Auf Fußnote %s wird nirgends verwiesen",
+	"No footnote reference on this line":          "Kein Fußnotenverweis in dieser Zeile",
+	"%s embeds itself":                            "%s bettet sich selbst ein",
+	"%s is embedded too deeply to show":           "%s ist zu tief eingebettet, um es anzuzeigen",
+	"%s is encrypted":                             "%s ist verschlüsselt",
+	"No note to embed: %s":                        "Keine Notiz zum Einbetten: %s",
+	"Aliases":                                     "Aliasse",
+	"Updated %d links to the renamed note":        "%d Links auf die umbenannte Notiz angepasst",
+	"%d of %d matches accepted":                   "%d von %d Treffern angenommen",
+	"Enter a regular expression, such as colou?r": "Gib einen regulären Ausdruck ein, etwa Farbe?n",
+	"Find and replace":                            "Suchen und ersetzen",
+	"Find in all notes (regular expression)":      "In allen Notizen suchen (regulärer Ausdruck)",
+	"No notes contain %s":                         "Keine Notiz enthält %s",
+	"Nothing replaced":                            "Nichts ersetzt",
+	"Replaced %d matches in %d notes; the notes as they were are in %s":                                       "%d Treffer in %d Notizen ersetzt; die vorherigen Fassungen liegen in %s",
+	"y:Accept | n:Reject | space:Toggle | a/N:Accept/reject note | A:Accept all | enter:Replace | esc:Cancel": "y:Annehmen | n:Ablehnen | Leertaste:Umschalten | a/N:Notiz annehmen/ablehnen | A:Alle annehmen | enter:Ersetzen | esc:Abbrechen",
+	"%d notes in the list":           "%d Notizen in der Liste",
+	"%s changes none of these notes": "%s ändert keine dieser Notizen",
+	"%s in %d notes":                 "%s in %d Notizen",
+	"All %d notes":                   "Alle %d Notizen",
+	"Change %d notes":                "%d Notizen ändern",
+	"Change tags":                    "Tags ändern",
+	"Changes %d notes":               "Ändert %d Notizen",
+	"Apply %s to":                    "%s anwenden auf",
+	"No tags changed":                "Keine Tags geändert",
+	"Selected note: %s":              "Ausgewählte Notiz: %s",
+	"Tag change (+tag adds, -tag removes, old>new renames)":     "Tag-Änderung (+tag fügt hinzu, -tag entfernt, alt>neu benennt um)",
+	"Enter a regular expression, such as ^Draft ":               "Gib einen regulären Ausdruck ein, etwa ^Entwurf ",
+	"Links to them are updated too":                             "Links darauf werden ebenfalls angepasst",
+	"No titles in the list match":                               "Kein Titel in der Liste passt",
+	"Nothing renamed":                                           "Nichts umbenannt",
+	"Rename %d notes":                                           "%d Notizen umbenennen",
+	"Rename titles":                                             "Titel umbenennen",
+	"Rename titles matching (regular expression)":               "Titel umbenennen, die passen auf (regulärer Ausdruck)",
+	"Renamed %d notes and updated %d links":                     "%d Notizen umbenannt und %d Links angepasst",
+	"Renamed %d notes before failing":                           "%d Notizen umbenannt, dann fehlgeschlagen",
+	"Replace with ($1 for a group)":                             "Ersetzen durch ($1 für eine Gruppe)",
+	"Skipped: %s":                                               "Übersprungen: %s",
+	"remove %s":                                                 "%s entfernen",
+	"Nothing to repeat yet: set a label, status or field first": "Noch nichts zu wiederholen: setze zuerst ein Label, einen Status oder ein Feld",
+	"Repeat":         "Wiederholen",
+	"Macro is empty": "Makro ist leer",
+	"Recorded a macro of %d keys; alt+P plays it":        "Makro mit %d Tasten aufgezeichnet; alt+P spielt es ab",
+	"Recording a macro; alt+W stops":                     "Makro wird aufgezeichnet; alt+W beendet",
+	"Stop recording with alt+W before playing the macro": "Beende die Aufzeichnung mit alt+W, bevor du das Makro abspielst",
+	"No macro recorded; alt+W starts recording":          "Kein Makro aufgezeichnet; alt+W startet die Aufzeichnung",
+	"Selected note":            "Ausgewählte Notiz",
+	"Play %d keys once":        "%d Tasten einmal abspielen",
+	"All %d notes in the list": "Alle %d Notizen der Liste",
+	"Select each note in turn and play the keys on it": "Jede Notiz nacheinander auswählen und die Tasten darauf abspielen",
+	"Play macro on": "Makro abspielen auf",
+	"The macro does not end back in the list, so it was played once": "Das Makro endet nicht wieder in der Liste und wurde daher nur einmal abgespielt",
+	"Played the macro on %d notes":                                   "Makro auf %d Notizen abgespielt",
+	"● Recording macro":                                              "● Makro wird aufgezeichnet",
+	"Record macro":                                                   "Makro aufzeichnen",
+	"Play macro":                                                     "Makro abspielen",
+	"New from template":                                              "Neu aus Vorlage",
+	"Template note":                                                  "Vorlagennotiz",
+	"Built in":                                                       "Mitgeliefert",
+	"Daily note":                                                     "Tagesnotiz",
+	"Project":                                                        "Projekt",
+	"Book notes":                                                     "Buchnotizen",
+	"Decision record":                                                "Entscheidungsprotokoll",
+	"Moved %s":                                                       "Verschoben: %s",
+	"%s to %s":                                                       "%s nach %s",
+	"Email":                                                          "E-Mail",
+	"Phone":                                                          "Telefon",
+	"Company":                                                        "Firma",
+	"Birthday":                                                       "Geburtstag",
+	"not set":                                                        "nicht gesetzt",
+	"Mentioned here":                                                 "Hier erwähnt",
+	"Enter an email address like name@example.com": "Gib eine E-Mail-Adresse wie name@example.com ein",
+	"Enter the birthday as YYYY-MM-DD or MM-DD":    "Gib den Geburtstag als JJJJ-MM-TT oder MM-TT ein",
+	"Sharing %s...":                           "Teile %s...",
+	"Sharing failed: %v":                      "Teilen fehlgeschlagen: %v",
+	"Updated gist %s":                         "Gist %s aktualisiert",
+	"Shared as %s":                            "Geteilt als %s",
+	"Uploading to %s...":                      "Lade zu %s hoch...",
+	"Upload failed: %v":                       "Hochladen fehlgeschlagen: %v",
+	"Uploaded to %s":                          "Hochgeladen nach %s",
+	"Note is too long for a QR code":          "Die Notiz ist zu lang für einen QR-Code",
+	"Window is too small for this QR code":    "Das Fenster ist zu klein für diesen QR-Code",
+	"Creating backup...":                      "Erstelle Sicherung...",
+	"Backup failed: %v":                       "Sicherung fehlgeschlagen: %v",
+	"Backup written to %s":                    "Sicherung gespeichert in %s",
+	"Running plugin %s...":                    "Führe Plugin %s aus...",
+	"Plugin %s failed: %v":                    "Plugin %s fehlgeschlagen: %v",
+	"Plugin %s finished":                      "Plugin %s beendet",
+	"No plugins found in %s":                  "Keine Plugins in %s gefunden",
+	"Opened vault %s":                         "Tresor %s geöffnet",
+	"Could not save split ratio: %v":          "Aufteilung konnte nicht gespeichert werden: %v",
+	"Could not save list density: %v":         "Listendichte konnte nicht gespeichert werden: %v",
+	"Could not save scratchpad: %v":           "Notizblock konnte nicht gespeichert werden: %v",
+	"Showing %s alongside; alt+b closes it":   "%s wird daneben angezeigt; alt+b schließt",
+	"Marked %s; mark another note to compare": "%s markiert; zum Vergleichen eine weitere Notiz markieren",
+	"Notes are too long to compare":           "Die Notizen sind zu lang zum Vergleichen",
+	"The notes are identical":                 "Die Notizen sind identisch",
+	"%d unchanged lines":                      "%d unveränderte Zeilen",
+	"Could not read notes: %v":                "Notizen konnten nicht gelesen werden: %v",
+
+	// Statistics
+	"Vault":             "Tresor",
+	"Counting…":         "Wird gezählt…",
+	"Words":             "Wörter",
+	"Tags":              "Tags",
+	"Active days":       "Aktive Tage",
+	"Writing activity":  "Schreibaktivität",
+	"Words written":     "Geschriebene Wörter",
+	"less":              "weniger",
+	"more":              "mehr",
+	"Today %d words":    "Heute %d Wörter",
+	"Today %d/%d words": "Heute %d/%d Wörter",
+	"%d-day streak":     "%d Tage in Folge",
+	"Current streak":    "Aktuelle Serie",
+	"Longest streak":    "Längste Serie",
+	"%d days":           "%d Tage",
+
+	"Sorted by last modified":       "Nach letzter Änderung sortiert",
+	"Sorted by creation time":       "Nach Erstellung sortiert",
+	"Sorted by priority":            "Nach Priorität sortiert",
+	"priority %s":                   "Priorität %s",
+	"Status":                        "Status",
+	"%s is now %s":                  "%s ist jetzt %s",
+	"draft":                         "Entwurf",
+	"review":                        "Prüfung",
+	"published":                     "veröffentlicht",
+	"high":                          "hoch",
+	"medium":                        "mittel",
+	"low":                           "niedrig",
+	"Could not save sort order: %v": "Sortierung konnte nicht gespeichert werden: %v",
+
+	// Screen reader
+	"QR code, press any key to close": "QR-Code, eine beliebige Taste schließt ihn",
+	"New note":                        "Neue Notiz",
+	"Editing %s":                      "Bearbeite %s",
+	"Choosing from %s":                "Auswahl aus %s",
+	"Comparing notes":                 "Vergleich von Notizen",
+	"Statistics":                      "Statistik",
+	"Reading %s":                      "Lese %s",
+	"Notes list":                      "Notizliste",
+	"%s, %d of %d":                    "%s, %d von %d",
+	"Title:":                          "Titel:",
+	"Content:":                        "Inhalt:",
+	"Search:":                         "Suche:",
+	"Status:":                         "Status:",
+
+	// Relative times and details
+	"just now":         "gerade eben",
+	"%dm ago":          "vor %d Min.",
+	"%dh ago":          "vor %d Std.",
+	"%dd ago":          "vor %d T.",
+	"%dw ago":          "vor %d Wo.",
+	"%dmo ago":         "vor %d Mon.",
+	"%dy ago":          "vor %d J.",
+	"Details":          "Details",
+	"Created":          "Erstellt",
+	"Modified":         "Geändert",
+	"File":             "Datei",
+	"any key to close": "beliebige Taste schließt",
+
+	"Fields":                        "Felder",
+	"Fields of %s":                  "Felder von %s",
+	"Add field":                     "Feld hinzufügen",
+	"key: value":                    "Schlüssel: Wert",
+	"This note has no fields":       "Diese Notiz hat keine Felder",
+	"New field (key: value)":        "Neues Feld (Schlüssel: Wert)",
+	"Enter the field as key: value": "Feld als Schlüssel: Wert eingeben",
+
+	// Dates
+	"January": "Januar", "February": "Februar", "March": "März", "April": "April",
+	"May": "Mai", "June": "Juni", "July": "Juli", "August": "August",
+	"September": "September", "October": "Oktober", "November": "November", "December": "Dezember",
+	"Jan": "Jan", "Feb": "Feb", "Mar": "Mär", "Apr": "Apr", "Jun": "Jun", "Jul": "Jul",
+	"Aug": "Aug", "Sep": "Sep", "Oct": "Okt", "Nov": "Nov", "Dec": "Dez",
+	"Mon": "Mo", "Tue": "Di", "Wed": "Mi", "Thu": "Do", "Fri": "Fr", "Sat": "Sa", "Sun": "So",
+	"Monday": "Montag", "Tuesday": "Dienstag", "Wednesday": "Mittwoch", "Thursday": "Donnerstag",
+	"Friday": "Freitag", "Saturday": "Samstag", "Sunday": "Sonntag",
+}
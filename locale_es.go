@@ -0,0 +1,459 @@
+package main
+
+// Spanish translations of the UI
+var catalogES = map[string]string{
+	// Help line
+	"Navigation: ":                "Navegación: ",
+	"[read-only] ":                "[solo lectura] ",
+	"Navigate":                    "Moverse",
+	"View":                        "Ver",
+	"Back":                        "Volver",
+	"New":                         "Nueva",
+	"Save":                        "Guardar",
+	"Edit":                        "Editar",
+	"Delete":                      "Borrar",
+	"Refresh":                     "Recargar",
+	"Density":                     "Densidad",
+	"Sort order":                  "Orden",
+	"Search scope":                "Ámbito de búsqueda",
+	"Date filter":                 "Filtro de fecha",
+	"Timeline":                    "Cronología",
+	"Stats":                       "Estadísticas",
+	"Resize":                      "Redimensionar",
+	"Switch pane":                 "Cambiar panel",
+	"Hide list/content":           "Ocultar lista/contenido",
+	"Zen":                         "Zen",
+	"Scratchpad":                  "Borrador",
+	"Outline":                     "Esquema",
+	"Preview":                     "Vista previa",
+	"Side by side":                "Lado a lado",
+	"Mark to compare":             "Marcar para comparar",
+	"Copy as HTML":                "Copiar como HTML",
+	"Copy code":                   "Copiar código",
+	"New bookmark":                "Nuevo marcador",
+	"Open bookmark":               "Abrir marcador",
+	"New meeting":                 "Nueva reunión",
+	"Tasks":                       "Tareas",
+	"Review flashcards":           "Repasar tarjetas",
+	"Quiz":                        "Cuestionario",
+	"Surprise me":                 "Sorpréndeme",
+	"On this day":                 "Tal día como hoy",
+	"Share gist":                  "Compartir gist",
+	"Share paste":                 "Compartir paste",
+	"QR code":                     "Código QR",
+	"Copy link":                   "Copiar enlace",
+	"Line numbers/wrap/tab width": "Números de línea/ajuste/tabulación",
+	"Table row/column/format":     "Fila/columna/formato de tabla",
+	"Backup":                      "Copia de seguridad",
+	"Vaults":                      "Bóvedas",
+	"Plugins":                     "Complementos",
+	"Quit":                        "Salir",
+
+	"enter:Select | /:Filter | esc:Cancel":         "enter:Elegir | /:Filtrar | esc:Cancelar",
+	"enter:Confirm | esc:Cancel":                   "enter:Confirmar | esc:Cancelar",
+	"esc:Back":                                     "esc:Volver",
+	"↑/↓/pgup/pgdn:Scroll | esc:Back":              "↑/↓/pgup/pgdn:Desplazar | esc:Volver",
+	"tab:Switch field | ctrl+s:Save | esc:Discard": "tab:Cambiar campo | ctrl+s:Guardar | esc:Descartar",
+
+	// Editor and list
+	"Notes":     "Notas",
+	"No notes.": "No hay notas.",
+	"pinned":    "fijada",
+	"archived":  "archivada",
+	"locked":    "cifrada",
+	"Note title (Press Tab to enter content)": "Título de la nota (Tab para escribir el contenido)",
+	"Enter note content (Ctrl+S to save)...":  "Escribe el contenido (Ctrl+S para guardar)...",
+	"Title":                                   "Título",
+	"Note (Ctrl+S to save)":                   "Nota (Ctrl+S para guardar)",
+	"A title is required":                     "Hace falta un título",
+	"Saved %s":                                "%s guardada",
+	"Scratchpad: saved as you type":           "Borrador: se guarda mientras escribes",
+	"Line numbers %s":                         "Números de línea %s",
+	"Soft wrap %s":                            "Ajuste de línea %s",
+	"Tab width %d":                            "Tabulación de %d",
+	"on":                                      "activado",
+	"off":                                     "desactivado",
+	"line %d":                                 "línea %d",
+	"No headings in this note":                "Esta nota no tiene encabezados",
+	"Not inside a table":                      "No estás dentro de una tabla",
+	"%d words":                                "%d palabras",
+	"%d/%d words":                             "%d/%d palabras",
+	"%d/%d words (%d%%)":                      "%d/%d palabras (%d%%)",
+	"space:Toggle | a:Add | e:Edit | d:Delete | shift+↑/↓:Move | esc:Back": "espacio:Marcar | a:Añadir | e:Editar | d:Borrar | mayús+↑/↓:Mover | esc:Volver",
+	"New item":         "Elemento nuevo",
+	"Item":             "Elemento",
+	"open":             "pendiente",
+	"done":             "hecho",
+	"%s, %s, %d of %d": "%s, %s, %d de %d",
+
+	// Search and filters
+	"search %s (ctrl+t to change)": "buscar en %s (ctrl+t para cambiar)",
+	"Searching %s":                 "Buscando en %s",
+	"titles":                       "títulos",
+	"contents":                     "contenidos",
+	"tags":                         "etiquetas",
+	"everything":                   "todo",
+	"Filter by date":               "Filtrar por fecha",
+	"Any time":                     "Cualquier fecha",
+	"Show all notes":               "Mostrar todas las notas",
+	"created %s":                   "creadas %s",
+	"modified %s":                  "modificadas %s",
+	"in a custom range…":           "en un intervalo a medida…",
+	"between":                      "entre",
+	"e.g. 2024-03, 2024-03-01..2024-03-15 or 2024-01..": "p. ej. 2024-03, 2024-03-01..2024-03-15 o 2024-01..",
+	"Showing notes from any time":                       "Mostrando notas de cualquier fecha",
+	"Showing notes %s":                                  "Mostrando notas %s",
+	"today":                                             "hoy",
+	"yesterday":                                         "ayer",
+	"this week":                                         "esta semana",
+	"last week":                                         "la semana pasada",
+	"this month":                                        "este mes",
+	"last month":                                        "el mes pasado",
+	"this year":                                         "este año",
+	"Today":                                             "Hoy",
+	"Yesterday":                                         "Ayer",
+	"This week":                                         "Esta semana",
+
+	"Label":                        "Color",
+	"Label filter":                 "Filtro de color",
+	"No label":                     "Sin color",
+	"Label for %s":                 "Color de %s",
+	"All labels":                   "Todos los colores",
+	"Filter by label":              "Filtrar por color",
+	"Showing notes with any label": "Mostrando notas de cualquier color",
+	"Showing %s notes":             "Mostrando notas en %s",
+	"red":                          "rojo",
+	"orange":                       "naranja",
+	"yellow":                       "amarillo",
+	"green":                        "verde",
+	"blue":                         "azul",
+	"purple":                       "morado",
+	"gray":                         "gris",
+
+	// Status messages
+	"Copying...":                                       "Copiando...",
+	"Copy failed: %v":                                  "No se pudo copiar: %v",
+	"Copied %s":                                        "Copiado %s",
+	"Copied note as HTML":                              "Nota copiada como HTML",
+	"Copied HTML source as text":                       "Código HTML copiado como texto",
+	"No code in this note":                             "Esta nota no tiene código",
+	"Copied %d lines of code":                          "%d líneas de código copiadas",
+	"URL":                                              "URL",
+	"Fetching %s...":                                   "Descargando %s...",
+	"Saved bookmark %s":                                "Marcador %s guardado",
+	"Saved %s without page details: %v":                "%s guardado sin detalles de la página: %v",
+	"Could not save the bookmark: %v":                  "No se pudo guardar el marcador: %v",
+	"This note has no web address":                     "Esta nota no tiene dirección web",
+	"Could not open the browser: %v":                   "No se pudo abrir el navegador: %v",
+	"Opened %s":                                        "%s abierto",
+	"Attendees (comma-separated)":                      "Asistentes (separados por comas)",
+	"Agenda (separate items with ;)":                   "Orden del día (separa los puntos con ;)",
+	"Meeting %s":                                       "Reunión %s",
+	"No open tasks":                                    "No hay tareas pendientes",
+	"Open tasks (%d)":                                  "Tareas pendientes (%d)",
+	"The note is hidden by the current filter":         "La nota está oculta por el filtro actual",
+	"%d flashcards due, alt+R to review":               "%d tarjetas pendientes, alt+R para repasar",
+	"No flashcards due":                                "No hay tarjetas pendientes",
+	"%d flashcards left for later":                     "%d tarjetas quedan para más tarde",
+	"Could not save the review: %v":                    "No se pudo guardar el repaso: %v",
+	"Reviewed %d flashcards":                           "%d tarjetas repasadas",
+	"Card %d of %d · %s":                               "Tarjeta %d de %d · %s",
+	"0-5:Grade (0 forgot, 5 easy) | esc:Back":          "0-5:Calificar (0 olvidada, 5 fácil) | esc:Volver",
+	"space:Show answer | esc:Back":                     "espacio:Ver respuesta | esc:Volver",
+	"Reviewing flashcards":                             "Repasando tarjetas",
+	"No sections with text under a heading to quiz on": "No hay secciones con texto bajo un encabezado para el cuestionario",
+	"Could not save the score: %v":                     "No se pudo guardar la puntuación: %v",
+	"Recalled %d of %d sections":                       "Recordaste %d de %d secciones",
+	"%d of %d times":                                   "%d de %d veces",
+	"Section %d of %d":                                 "Sección %d de %d",
+	"recalled %d of %d times":                          "recordada %d de %d veces",
+	"y:Recalled | n:Forgot | esc:Back":                 "y:Recordada | n:Olvidada | esc:Volver",
+	"How about %s?":                                    "¿Qué tal %s?",
+	"1 year ago":                                       "hace 1 año",
+	"%d years ago":                                     "hace %d años",
+	"1 month ago":                                      "hace 1 mes",
+	"%d months ago":                                    "hace %d meses",
+	"Nothing was written on this day before":           "No se escribió nada este día en el pasado",
+	"On this day, %s %d":                               "Tal día como hoy, %[2]d de %[1]s",
+	"private key":                                      "clave privada",
+	"AWS access key":                                   "clave de acceso de AWS",
+	"AWS secret key":                                   "clave secreta de AWS",
+	"GitHub token":                                     "token de GitHub",
+	"Slack token":                                      "token de Slack",
+	"Stripe key":                                       "clave de Stripe",
+	"Google API key":                                   "clave de API de Google",
+	"API key":                                          "clave de API",
+	"password or token":                                "contraseña o token",
+	"This note looks like it contains: %s. Type yes to share it anyway":              "Parece que esta nota contiene: %s. Escribe sí para compartirla igualmente",
+	"This note looks like it contains: %s. Type yes to publish it anyway":            "Parece que esta nota contiene: %s. Escribe sí para publicarla igualmente",
+	"These notes look like they contain credentials: %s. Type yes to back up anyway": "Parece que estas notas contienen credenciales: %s. Escribe sí para hacer la copia igualmente",
+	"yes":                            "sí",
+	"Not shared":                     "No se compartió",
+	"No backup written":              "No se escribió ninguna copia",
+	"Not published":                  "No se publicó",
+	"and %d more":                    "y %d más",
+	"%s created %s":                  "%s creó %s",
+	"%s deleted %s":                  "%s eliminó %s",
+	"%s restored %s":                 "%s restauró %s",
+	"%s renamed a note to %s":        "%s renombró una nota a %s",
+	"%s edited %s":                   "%s editó %s",
+	"word":                           "palabra",
+	"No changes logged yet":          "Aún no hay cambios registrados",
+	"Change log":                     "Registro de cambios",
+	"That note no longer exists":     "Esa nota ya no existe",
+	"words":                          "palabras",
+	"The trash is empty":             "La papelera está vacía",
+	"Empty trash now":                "Vaciar la papelera ahora",
+	"Delete %d notes for good":       "Eliminar %d notas definitivamente",
+	"Deleted %s":                     "Eliminada %s",
+	"purged in %d days":              "se eliminará en %d días",
+	"Trash (%s)":                     "Papelera (%s)",
+	"Purged %d notes (%s)":           "%d notas eliminadas definitivamente (%s)",
+	"Restored %s":                    "%s restaurada",
+	"purged within a day":            "se eliminará en menos de un día",
+	"Trash":                          "Papelera",
+	"Identical":                      "Idénticas",
+	"%d%% similar":                   "%d%% similares",
+	"No duplicate notes found":       "No se encontraron notas duplicadas",
+	"%d possible duplicates":         "%d posibles duplicados",
+	"Compare":                        "Comparar",
+	"Merge into %s":                  "Combinar en %s",
+	"Append %s and delete it":        "Añadir %s y eliminarla",
+	"Delete %s":                      "Eliminar %s",
+	"Merged":                         "Combinadas",
+	"Duplicates":                     "Duplicados",
+	"No broken links":                "No hay enlaces rotos",
+	"In %s, line %d":                 "En %s, línea %d",
+	"%d broken links":                "%d enlaces rotos",
+	"Create %s":                      "Crear %s",
+	"New empty note":                 "Nueva nota vacía",
+	"Link to %s":                     "Enlazar a %s",
+	"Open %s":                        "Abrir %s",
+	"Line %d":                        "Línea %d",
+	"Created %s":                     "%s creada",
+	"Linked to %s":                   "Enlazado a %s",
+	"Broken links":                   "Enlaces rotos",
+	"Every note is linked or tagged": "Todas las notas están enlazadas o etiquetadas",
+	"%d orphaned notes":              "%d notas huérfanas",
+	"Orphans":                        "Notas huérfanas",
+	"Every attachment is used":       "Todos los adjuntos se usan",
+	"Delete all":                     "Eliminar todos",
+	"Type yes to delete every trashed note for good":      "Escribe sí para eliminar definitivamente todas las notas de la papelera",
+	"Type yes to delete every unused attachment for good": "Escribe sí para eliminar definitivamente todos los adjuntos sin usar",
+	"Nothing deleted":                     "No se eliminó nada",
+	"Free %s":                             "Liberar %s",
+	"Archive all":                         "Archivar todos",
+	"Move them into a zip in %s":          "Moverlos a un zip en %s",
+	"%d unused attachments (%s)":          "%d adjuntos sin usar (%s)",
+	"Archive":                             "Archivar",
+	"Move it into a zip in %s":            "Moverlo a un zip en %s",
+	"Archived %d files to %s, freeing %s": "%d archivos archivados en %s, %s liberados",
+	"Deleted %d files, freeing %s":        "%d archivos eliminados, %s liberados",
+	"Unused attachments":                  "Adjuntos sin usar",
+	"Your habits":                         "Tus hábitos",
+	"Set \"insights\": true in the config file to see your habits here":        "Pon \"insights\": true en el archivo de configuración para ver aquí tus hábitos",
+	"%d today, %d this week, %d this month":                                    "%d hoy, %d esta semana, %d este mes",
+	"Most used":                                                                "Más usados",
+	"Error details":                                                            "Detalles del error",
+	"No errors so far":                                                         "Ningún error por ahora",
+	"alt+E:Details":                                                            "alt+E:Detalles",
+	"Could not save the note":                                                  "No se pudo guardar la nota",
+	"Could not delete the note":                                                "No se pudo eliminar la nota",
+	"This note cannot be read: %v":                                             "Esta nota no se puede leer: %v",
+	"unreadable":                                                               "ilegible",
+	"Opened read-only because notes cannot be written: %s":                     "Abierto en solo lectura porque no se pueden escribir notas: %s",
+	"Your text is still in the editor; press ctrl+s to try again":              "Tu texto sigue en el editor; pulsa ctrl+s para volver a intentarlo",
+	"check the owner and permissions of the notes directory":                   "comprueba el propietario y los permisos del directorio de notas",
+	"the disk is full, free some space and try again":                          "el disco está lleno, libera espacio y vuelve a intentarlo",
+	"the file system is mounted read-only":                                     "el sistema de archivos está montado en solo lectura",
+	"Nothing saved; the note on disk is unchanged":                             "No se guardó nada; la nota en el disco no ha cambiado",
+	"Merged text in the editor; press ctrl+s to save it":                       "Texto combinado en el editor; pulsa ctrl+s para guardarlo",
+	"%d conflicts still to settle":                                             "Quedan %d conflictos por resolver",
+	"%s changed on disk while you were editing it; the changes merge cleanly":  "%s cambió en el disco mientras la editabas; los cambios se combinan sin conflictos",
+	"%s changed on disk while you were editing it: %d of %d conflicts settled": "%s cambió en el disco mientras la editabas: %d de %d conflictos resueltos",
+	"mine": "mío",
+	"disk": "disco",
+	"n/p:Next/previous conflict | m/d/b:Keep mine/disk/both | M/D:All mine/disk | e:Edit merge | enter:Save | esc:Back": "n/p:Conflicto siguiente/anterior | m/d/b:Conservar mío/disco/ambos | M/D:Todo mío/disco | e:Editar combinación | enter:Guardar | esc:Volver",
+	"Merging %s":                                  "Combinando %s",
+	"Image not found: %s":                         "Imagen no encontrada: %s",
+	"Diagram not drawn, shown as written":         "Diagrama no dibujado, se muestra tal como está escrito",
+	"Footnote":                                    "Nota al pie",
+	"Footnote %s has no definition":               "La nota al pie %s no tiene definición",
+	"Footnote %s is never referenced":             "La nota al pie %s no se cita en ningún sitio",
+	"No footnote reference on this line":          "No hay ninguna referencia a nota al pie en esta línea",
+	"%s embeds itself":                            "%s se incrusta a sí misma",
+	"%s is embedded too deeply to show":           "%s está incrustada a demasiada profundidad para mostrarla",
+	"%s is encrypted":                             "%s está cifrada",
+	"No note to embed: %s":                        "No hay nota que incrustar: %s",
+	"Aliases":                                     "Alias",
+	"Updated %d links to the renamed note":        "%d enlaces a la nota renombrada actualizados",
+	"%d of %d matches accepted":                   "%d de %d coincidencias aceptadas",
+	"Enter a regular expression, such as colou?r": "Escribe una expresión regular, como colou?r",
+	"Find and replace":                            "Buscar y reemplazar",
+	"Find in all notes (regular expression)":      "Buscar en todas las notas (expresión regular)",
+	"No notes contain %s":                         "Ninguna nota contiene %s",
+	"Nothing replaced":                            "No se reemplazó nada",
+	"Replaced %d matches in %d notes; the notes as they were are in %s":                                       "%d coincidencias reemplazadas en %d notas; las versiones anteriores están en %s",
+	"y:Accept | n:Reject | space:Toggle | a/N:Accept/reject note | A:Accept all | enter:Replace | esc:Cancel": "y:Aceptar | n:Rechazar | espacio:Alternar | a/N:Aceptar/rechazar nota | A:Aceptar todo | enter:Reemplazar | esc:Cancelar",
+	"%d notes in the list":           "%d notas de la lista",
+	"%s changes none of these notes": "%s no cambia ninguna de estas notas",
+	"%s in %d notes":                 "%s en %d notas",
+	"All %d notes":                   "Las %d notas",
+	"Change %d notes":                "Cambiar %d notas",
+	"Change tags":                    "Cambiar etiquetas",
+	"Changes %d notes":               "Cambia %d notas",
+	"Apply %s to":                    "Aplicar %s a",
+	"No tags changed":                "No se cambió ninguna etiqueta",
+	"Selected note: %s":              "Nota seleccionada: %s",
+	"Tag change (+tag adds, -tag removes, old>new renames)":     "Cambio de etiqueta (+etiqueta añade, -etiqueta quita, vieja>nueva renombra)",
+	"Enter a regular expression, such as ^Draft ":               "Escribe una expresión regular, como ^Borrador ",
+	"Links to them are updated too":                             "Los enlaces a ellas también se actualizan",
+	"No titles in the list match":                               "Ningún título de la lista coincide",
+	"Nothing renamed":                                           "No se renombró nada",
+	"Rename %d notes":                                           "Renombrar %d notas",
+	"Rename titles":                                             "Renombrar títulos",
+	"Rename titles matching (regular expression)":               "Renombrar títulos que coincidan con (expresión regular)",
+	"Renamed %d notes and updated %d links":                     "%d notas renombradas y %d enlaces actualizados",
+	"Renamed %d notes before failing":                           "Se renombraron %d notas antes del fallo",
+	"Replace with ($1 for a group)":                             "Reemplazar por ($1 para un grupo)",
+	"Skipped: %s":                                               "Omitida: %s",
+	"remove %s":                                                 "quitar %s",
+	"Nothing to repeat yet: set a label, status or field first": "Nada que repetir todavía: primero pon una etiqueta, un estado o un campo",
+	"Repeat":         "Repetir",
+	"Macro is empty": "La macro está vacía",
+	"Recorded a macro of %d keys; alt+P plays it":        "Macro de %d teclas grabada; alt+P la reproduce",
+	"Recording a macro; alt+W stops":                     "Grabando una macro; alt+W la detiene",
+	"Stop recording with alt+W before playing the macro": "Detén la grabación con alt+W antes de reproducir la macro",
+	"No macro recorded; alt+W starts recording":          "No hay macro grabada; alt+W empieza a grabar",
+	"Selected note":            "Nota seleccionada",
+	"Play %d keys once":        "Reproducir %d teclas una vez",
+	"All %d notes in the list": "Las %d notas de la lista",
+	"Select each note in turn and play the keys on it": "Seleccionar cada nota por turno y reproducir las teclas en ella",
+	"Play macro on": "Reproducir macro en",
+	"The macro does not end back in the list, so it was played once": "La macro no termina de vuelta en la lista, así que se reprodujo una vez",
+	"Played the macro on %d notes":                                   "Macro reproducida en %d notas",
+	"● Recording macro":                                              "● Grabando macro",
+	"Record macro":                                                   "Grabar macro",
+	"Play macro":                                                     "Reproducir macro",
+	"New from template":                                              "Nueva desde plantilla",
+	"Template note":                                                  "Nota de plantilla",
+	"Built in":                                                       "Incluida",
+	"Daily note":                                                     "Nota diaria",
+	"Project":                                                        "Proyecto",
+	"Book notes":                                                     "Notas de libro",
+	"Decision record":                                                "Registro de decisión",
+	"Moved %s":                                                       "Movido: %s",
+	"%s to %s":                                                       "%s a %s",
+	"Email":                                                          "Correo",
+	"Phone":                                                          "Teléfono",
+	"Company":                                                        "Empresa",
+	"Birthday":                                                       "Cumpleaños",
+	"not set":                                                        "sin definir",
+	"Mentioned here":                                                 "Mencionado aquí",
+	"Enter an email address like name@example.com": "Introduce un correo como nombre@ejemplo.com",
+	"Enter the birthday as YYYY-MM-DD or MM-DD":    "Introduce el cumpleaños como AAAA-MM-DD o MM-DD",
+	"Sharing %s...":                           "Compartiendo %s...",
+	"Sharing failed: %v":                      "No se pudo compartir: %v",
+	"Updated gist %s":                         "Gist %s actualizado",
+	"Shared as %s":                            "Compartida como %s",
+	"Uploading to %s...":                      "Subiendo a %s...",
+	"Upload failed: %v":                       "No se pudo subir: %v",
+	"Uploaded to %s":                          "Subida a %s",
+	"Note is too long for a QR code":          "La nota es demasiado larga para un código QR",
+	"Window is too small for this QR code":    "La ventana es demasiado pequeña para este código QR",
+	"Creating backup...":                      "Creando copia de seguridad...",
+	"Backup failed: %v":                       "Falló la copia de seguridad: %v",
+	"Backup written to %s":                    "Copia de seguridad guardada en %s",
+	"Running plugin %s...":                    "Ejecutando el complemento %s...",
+	"Plugin %s failed: %v":                    "Falló el complemento %s: %v",
+	"Plugin %s finished":                      "El complemento %s ha terminado",
+	"No plugins found in %s":                  "No hay complementos en %s",
+	"Opened vault %s":                         "Bóveda %s abierta",
+	"Could not save split ratio: %v":          "No se pudo guardar la división: %v",
+	"Could not save list density: %v":         "No se pudo guardar la densidad de la lista: %v",
+	"Could not save scratchpad: %v":           "No se pudo guardar el borrador: %v",
+	"Showing %s alongside; alt+b closes it":   "Mostrando %s al lado; alt+b la cierra",
+	"Marked %s; mark another note to compare": "%s marcada; marca otra nota para compararlas",
+	"Notes are too long to compare":           "Las notas son demasiado largas para compararlas",
+	"The notes are identical":                 "Las notas son idénticas",
+	"%d unchanged lines":                      "%d líneas sin cambios",
+	"Could not read notes: %v":                "No se pudieron leer las notas: %v",
+
+	// Statistics
+	"Vault":             "Bóveda",
+	"Counting…":         "Contando…",
+	"Words":             "Palabras",
+	"Tags":              "Etiquetas",
+	"Active days":       "Días activos",
+	"Writing activity":  "Actividad de escritura",
+	"Words written":     "Palabras escritas",
+	"less":              "menos",
+	"more":              "más",
+	"Today %d words":    "Hoy %d palabras",
+	"Today %d/%d words": "Hoy %d/%d palabras",
+	"%d-day streak":     "racha de %d días",
+	"Current streak":    "Racha actual",
+	"Longest streak":    "Racha más larga",
+	"%d days":           "%d días",
+
+	"Sorted by last modified":       "Ordenadas por última modificación",
+	"Sorted by creation time":       "Ordenadas por fecha de creación",
+	"Sorted by priority":            "Ordenadas por prioridad",
+	"priority %s":                   "prioridad %s",
+	"Status":                        "Estado",
+	"%s is now %s":                  "%s: ahora %s",
+	"draft":                         "borrador",
+	"review":                        "revisión",
+	"published":                     "publicada",
+	"high":                          "alta",
+	"medium":                        "media",
+	"low":                           "baja",
+	"Could not save sort order: %v": "No se pudo guardar el orden: %v",
+
+	// Screen reader
+	"QR code, press any key to close": "Código QR, pulsa cualquier tecla para cerrarlo",
+	"New note":                        "Nota nueva",
+	"Editing %s":                      "Editando %s",
+	"Choosing from %s":                "Elegir en %s",
+	"Comparing notes":                 "Comparando notas",
+	"Statistics":                      "Estadísticas",
+	"Reading %s":                      "Leyendo %s",
+	"Notes list":                      "Lista de notas",
+	"%s, %d of %d":                    "%s, %d de %d",
+	"Title:":                          "Título:",
+	"Content:":                        "Contenido:",
+	"Search:":                         "Búsqueda:",
+	"Status:":                         "Estado:",
+
+	// Relative times and details
+	"just now":         "ahora mismo",
+	"%dm ago":          "hace %d min",
+	"%dh ago":          "hace %d h",
+	"%dd ago":          "hace %d d",
+	"%dw ago":          "hace %d sem",
+	"%dmo ago":         "hace %d meses",
+	"%dy ago":          "hace %d años",
+	"Details":          "Detalles",
+	"Created":          "Creada",
+	"Modified":         "Modificada",
+	"File":             "Archivo",
+	"any key to close": "cualquier tecla para cerrar",
+
+	"Fields":                        "Campos",
+	"Fields of %s":                  "Campos de %s",
+	"Add field":                     "Añadir campo",
+	"key: value":                    "clave: valor",
+	"This note has no fields":       "Esta nota no tiene campos",
+	"New field (key: value)":        "Campo nuevo (clave: valor)",
+	"Enter the field as key: value": "Escribe el campo como clave: valor",
+
+	// Dates
+	"January": "Enero", "February": "Febrero", "March": "Marzo", "April": "Abril",
+	"May": "Mayo", "June": "Junio", "July": "Julio", "August": "Agosto",
+	"September": "Septiembre", "October": "Octubre", "November": "Noviembre", "December": "Diciembre",
+	"Jan": "Ene", "Feb": "Feb", "Mar": "Mar", "Apr": "Abr", "Jun": "Jun", "Jul": "Jul",
+	"Aug": "Ago", "Sep": "Sep", "Oct": "Oct", "Nov": "Nov", "Dec": "Dic",
+	"Mon": "Lun", "Tue": "Mar", "Wed": "Mié", "Thu": "Jue", "Fri": "Vie", "Sat": "Sáb", "Sun": "Dom",
+	"Monday": "Lunes", "Tuesday": "Martes", "Wednesday": "Miércoles", "Thursday": "Jueves",
+	"Friday": "Viernes", "Saturday": "Sábado", "Sunday": "Domingo",
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Size at which the log is moved aside to gleaner.log.1 on startup
+const maxLogSize = 5 << 20
+
+// logPath returns the file gleaner logs to
+func logPath() string {
+	if portableDir != "" {
+		return filepath.Join(portableDir, "cache", "gleaner.log")
+	}
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = filepath.Join(homeDir(), ".cache")
+	}
+	return filepath.Join(dir, "gleaner", "gleaner.log")
+}
+
+// setupLogging sends log records to the log file, which survives the
+// alternate screen where printed errors would vanish. Warnings and errors
+// are always logged; with debug, key presses, file writes and the steps of
+// backups, hooks and sharing are too. Logging is dropped silently when the
+// file cannot be opened. The returned function closes the file.
+func setupLogging(debug bool) func() {
+	slog.SetDefault(slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	path := logPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return func() {}
+	}
+	if info, err := os.Stat(path); err == nil && info.Size() > maxLogSize {
+		os.Rename(path, path+".1")
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return func() {}
+	}
+
+	level := slog.LevelInfo
+	if debug {
+		level = slog.LevelDebug
+	}
+	slog.SetDefault(slog.New(slog.NewTextHandler(f, &slog.HandlerOptions{Level: level})))
+	return func() { f.Close() }
+}
+
+// logMsg records the key presses and the results of background work the
+// interface receives
+func logMsg(m model, msg tea.Msg) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		// Text typed into notes, prompts and the filter stays out of the log
+		key := msg.String()
+		if msg.Type == tea.KeyRunes && (m.mode != "list" || m.list.FilterState() == list.Filtering) {
+			key = "text"
+		}
+		slog.Debug("key", "key", key, "mode", m.mode)
+	case notesErrMsg:
+		slog.Warn("could not read notes", "dir", notesDir, "err", msg.err)
+	case hookErrMsg:
+		slog.Error("hook failed", "err", msg.err)
+	case backupMsg:
+		if msg.err != nil {
+			slog.Error("backup failed", "scheduled", msg.scheduled, "err", msg.err)
+		} else {
+			slog.Info("backup written", "path", msg.path, "scheduled", msg.scheduled)
+		}
+	case gistMsg:
+		if msg.err != nil {
+			slog.Error("sharing gist failed", "err", msg.err)
+		} else {
+			slog.Info("shared gist", "url", msg.url, "updated", msg.updated)
+		}
+	case pasteMsg:
+		if msg.err != nil {
+			slog.Error("sharing paste failed", "err", msg.err)
+		} else {
+			slog.Info("shared paste", "url", msg.url)
+		}
+	case pluginMsg:
+		if msg.err != nil {
+			slog.Error("plugin failed", "plugin", msg.name, "err", msg.err)
+		}
+	case bookmarkMsg:
+		if msg.err != nil {
+			slog.Warn("bookmark incomplete", "title", msg.title, "saved", msg.saved, "err", msg.err)
+		}
+	case clipboardMsg:
+		if msg.err != nil {
+			slog.Warn("copying failed", "err", msg.err)
+		}
+	}
+}
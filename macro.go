@@ -0,0 +1,102 @@
+package main
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Marks the help line while a macro is being recorded
+var recordingStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("203")).Bold(true)
+
+// Keys that start and stop recording a macro and play it back; they are
+// never recorded themselves
+const (
+	macroRecordKey = "alt+W"
+	macroPlayKey   = "alt+P"
+)
+
+// recordKey adds a key press to the macro being recorded
+func (m model) recordKey(msg tea.KeyMsg) model {
+	if m.recording && !m.replaying && msg.String() != macroRecordKey && msg.String() != macroPlayKey {
+		m.macro = append(m.macro, msg)
+	}
+	return m
+}
+
+// toggleRecording starts recording a new macro, or stops the recording
+func (m model) toggleRecording() model {
+	if m.recording {
+		m.recording = false
+		if len(m.macro) == 0 {
+			m.status = tr("Macro is empty")
+			return m
+		}
+		m.status = trf("Recorded a macro of %d keys; alt+P plays it", len(m.macro))
+		return m
+	}
+	m.recording = true
+	m.macro = nil
+	m.status = tr("Recording a macro; alt+W stops")
+	return m
+}
+
+// chooseMacroTarget asks whether to play the macro on the selected note or
+// on every note the list shows
+func (m model) chooseMacroTarget() model {
+	switch {
+	case m.recording:
+		m.status = tr("Stop recording with alt+W before playing the macro")
+		return m
+	case len(m.macro) == 0:
+		m.status = tr("No macro recorded; alt+W starts recording")
+		return m
+	}
+	items := []pickerItem{
+		{title: tr("Selected note"), desc: trf("Play %d keys once", len(m.macro)), value: "one"},
+		{title: trf("All %d notes in the list", len(m.list.VisibleItems())), desc: tr("Select each note in turn and play the keys on it"), value: "all"},
+	}
+	return m.openPicker("macro", tr("Play macro on"), items)
+}
+
+// playMacro plays the recorded keys as if typed, once on the selected note
+// or once on each note in the list, selecting each in turn. Saves and
+// other background work of every run go ahead together afterwards.
+func (m model) playMacro(target string) (tea.Model, tea.Cmd) {
+	var paths []string
+	if target == "all" {
+		for _, item := range m.list.VisibleItems() {
+			paths = append(paths, item.(note).path)
+		}
+	} else if m.selectedNote != nil {
+		paths = append(paths, m.selectedNote.path)
+	}
+	if len(paths) == 0 {
+		paths = append(paths, "")
+	}
+
+	var cmds []tea.Cmd
+	runs := 0
+	m.replaying = true
+	for _, path := range paths {
+		// A macro that leaves an editor or prompt open cannot move on
+		if runs > 0 && m.mode != "list" {
+			break
+		}
+		if path != "" {
+			m = m.selectPath(path)
+		}
+		for _, key := range m.macro {
+			next, cmd := m.update(key)
+			m = next.(model)
+			cmds = append(cmds, cmd)
+		}
+		runs++
+	}
+	m.replaying = false
+	if runs < len(paths) {
+		m.status = tr("The macro does not end back in the list, so it was played once")
+	} else if m.mode == "list" {
+		m.status = trf("Played the macro on %d notes", runs)
+	}
+	return m, tea.Batch(cmds...)
+}
@@ -1,7 +1,10 @@
 package main
 
 import (
+	"errors"
+	"flag"
 	"fmt"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"sort"
@@ -9,10 +12,12 @@ import (
 	"strings"
 	"time"
 	"unicode"
+	"unicode/utf8"
 
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
@@ -22,12 +27,30 @@ type note struct {
 	title     string  // Title of the note
 	path      string  // File path of the note
 	createdAt int64   // Timestamp of note creation
+	modifiedAt int64  // Timestamp of the last change to the file
+	excerpt   string  // First line of the body, cached for the list
+	body      string  // Content without frontmatter, for searching
+	tags      []string // Tags from the frontmatter
+	aliases   []string // Other names the note is found and linked by
+	pinned    bool     // Pinned notes are listed first
+	archived  bool     // Archived notes are kept but de-emphasized
+	encrypted bool     // Body is stored encrypted
+	tasksOpen int      // Unchecked checkboxes in the body
+	tasksDone int      // Checked checkboxes in the body
+	label     string   // Colour label from the frontmatter
+	priority  int      // Priority from 1 (highest) to 5, 0 when unset
+	status    string   // Workflow state, such as draft or published
+	kind      string   // Note type from the "type" field, e.g. checklist
+	language  string   // Language of a snippet's code
+	url       string   // Web address a bookmark points to
+	icon      string   // Emoji standing in for a bookmark's favicon
+	readErr   error    // Why the note could not be read, nil when it could
 }
 
 // Implement list.Item interface methods for seamless list integration
 func (n note) Title() string       { return n.title }
-func (n note) Description() string { return time.Unix(n.createdAt, 0).Format("2006-01-02 15:04:05") }
-func (n note) FilterValue() string { return n.title }
+func (n note) Description() string { return noteDate(n.listStamp()) }
+func (n note) FilterValue() string { return n.searchText() }
 
 // Model defines the entire application state
 type model struct {
@@ -38,13 +61,81 @@ type model struct {
 	mode          string          // Current application mode (list/new/edit)
 	selectedNote  *note           // Currently selected note
 	width, height int             // Window dimensions
+	splitRatio    float64         // Fraction of the width used by the list pane
+	hideList      bool            // List pane hidden, content uses the full width
+	hideContent   bool            // Content pane hidden, list uses the full width
+	contentFocus  bool            // Content pane has focus while browsing
+	zen           bool            // Distraction-free writing mode
+	wrap          bool            // Soft wrap long lines while reading
+	tabWidth      int             // Spaces inserted by the tab key while editing
+	preview       bool            // Show rendered markdown next to the editor
+	editorTop     int             // First note line visible in the editor, for preview sync
+	qrCode        string          // Rendered QR code shown over the screen, empty when closed
+	popup         string          // Text of the popup shown over the screen, empty when closed
+	search        string          // Filter to apply once the notes have loaded (--search)
+	scratch       textarea.Model  // Editor for the scratchpad
+	scratchReturn string          // Mode to return to when the scratchpad closes
+	resumeLine    int             // Content line to restore once the notes have loaded
+	sideNote      *note           // Note kept open next to the content pane
+	sideContent   string          // Text of the side note
+	side          viewport.Model  // Scrollable view of the side note
+	marked        *note           // Note marked to be compared with another
+	diff          viewport.Model  // Differences between two compared notes
+	diffTitle     string          // Names of the compared notes
+	dateFilter    dateRange       // Limits the list to notes from a period
+	labelFilter   string          // Limits the list to notes with this colour label
+	prompt        textinput.Model // One-line question shown in place of the help
+	promptKind    string          // What the open prompt is asking for
+	promptReturn  string          // Mode to return to when the prompt closes
+	timeline      bool            // List pane groups notes under date headers
+	timelineCursor int            // Row under the cursor in the timeline
+	collapsed     map[string]bool // Timeline groups that are folded
+	statsText     string          // Rendered statistics screen
+	wordsByDay    map[string]int  // Words written per day in this vault
 	titleEntered  bool            // Tracks title input state
+	status        string          // Result of the last background operation
+	toast         toast           // Transient notice of how an operation went
+	lastError     string          // Full text of the last failure, shown by alt+E
+	picker        list.Model      // Popup list for choosing vaults and other options
+	pickerKind    string          // What the open picker is choosing
+	pickerReturn  string          // Mode to return to when the picker closes
+	fieldKey      string          // Frontmatter field being edited
+	checkCursor   int             // Item under the cursor in a checklist note
+	attendees     string          // Attendees entered for the meeting note being started
+	reviewCards   []flashcard     // Flashcards left in the review, the current one first
+	reviewTotal   int             // Flashcards due when the review started
+	reviewGraded  map[string]bool // Flashcards whose schedule this review has changed
+	reviewShown   bool            // Answer of the current flashcard is shown
+	started       bool            // Notes have loaded since startup, for the startup messages
+	pendingShare  string          // Share waiting for confirmation because the note holds secrets
+	quizSections  []quizSection   // Sections of the note being quizzed
+	quizIndex     int             // Section being quizzed
+	quizRight     int             // Sections recalled in this quiz
+	quizShown     bool            // Text of the current section is shown
+	quizScores    map[string]quizScore // Recall scores of the sections, loaded when the quiz starts
+	macro         []tea.KeyMsg    // Keys of the last recorded macro
+	recording     bool            // Keys are being recorded into the macro
+	replaying     bool            // The macro is being played back
+	lastAction    *fieldAction    // Last change to a note's fields, repeated by "."
+	renamePattern string          // Pattern the listed notes' titles are being renamed by
+	renamePlans   []renamePlan    // Renames shown for confirmation
+	tagEdit       *tagEdit        // Tag change being made across notes
+	tagNotes      []note          // Notes the tag change is shown to alter, for confirmation
+	replaceFind   string          // Pattern of the last find and replace across the vault
+	replaceFound  []replaceMatch  // Matches of the find and replace, in the order shown
+	replaceTexts  map[string]string // Text of each note with matches, as it was searched
+	replaceCursor int             // Match under the cursor
+	footnoteFrom  int             // Line the last footnote was followed from, for going back
+	editBase      string          // Text of the note being edited as it was when editing began
+	merge         []mergeChunk    // Merge of the editor's text with a copy changed on disk
+	mergeDisk     string          // Text on disk the merge was made with
+	mergeCursor   int             // Conflict under the cursor in the merge
 }
 
 // Define application-wide styling for consistent UI
 var (
-	// Directory to store notes
-	notesDir = filepath.Join(os.Getenv("HOME"), ".notes")
+	// Directory to store notes, overridable with --dir, GLEANER_DIR or the config file
+	notesDir = defaultNotesDir()
 
 	// Document container style
 	docStyle = lipgloss.NewStyle().Padding(1, 2)
@@ -76,50 +167,69 @@ var (
 			MarginTop(1)
 )
 
-// Help text provides quick reference for user interactions
-const helpText = `Navigation: ↑/↓:Navigate | enter:View | esc:Back | ctrl+n:New | ctrl+s:Save | ctrl+e:Edit | ctrl+d:Delete | ctrl+u:Refresh | ctrl+c:Quit`
-
 // initialModel sets up the initial application state
 func initialModel() model {
 	// Create text input for note titles
 	ti := textinput.New()
-	ti.Placeholder = "Note title (Press Tab to enter content)"
+	ti.Placeholder = tr("Note title (Press Tab to enter content)")
 	ti.CharLimit = 50
 	ti.Focus()
 
 	// Create text area for note content
 	ta := textarea.New()
-	ta.Placeholder = "Enter note content (Ctrl+S to save)..."
+	ta.Placeholder = tr("Enter note content (Ctrl+S to save)...")
 	ta.ShowLineNumbers = false
-	ta.Prompt = "┃ "
-
-	// Configure list with a custom delegate
-	delegate := list.NewDefaultDelegate()
-	delegate.ShowDescription = true  // Show creation timestamps
+	ta.CharLimit = 0 // Long notes must not be truncated
+	ta.MaxHeight = 0
+	ta.Prompt = editorPrompt()
 
-	l := list.New([]list.Item{}, delegate, 0, 0)
-	l.Title = "Notes"
+	// Configure list with a custom delegate showing body excerpts
+	l := list.New([]list.Item{}, newNoteDelegate(cfg.ListDensity), 0, 0)
+	l.Title = listTitle()
 	l.SetShowStatusBar(false)
 	l.SetFilteringEnabled(true)
+	l.Filter = scopeFilter
+	l.FilterInput.Placeholder = scopePlaceholder()
 
-	return model{
+	m := model{
 		list:      l,
 		textInput: ti,
 		textarea:  ta,
+		picker:    list.New(nil, list.NewDefaultDelegate(), 0, 0),
+		scratch:   newScratchArea(),
 		mode:      "list",
+		splitRatio: clampSplit(cfg.SplitRatio),
 	}
+	return m.configureEditor()
 }
 
 // Init prepares initial commands when the application starts
 func (m model) Init() tea.Cmd {
+	interval, _ := backupInterval()
 	return tea.Batch(
 		loadNotes,  // Load existing notes
 		textarea.Blink,  // Enable text area cursor blinking
+		scheduleBackup(interval), // Start automatic backups when configured
+		scheduleInsightFlush(),   // Write out usage counts from time to time
 	)
 }
 
-// Update handles all application state changes and user interactions
+// Update remaps and records a message, applies it, and reports the change
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	msg = m.remapMsg(msg)
+	logMsg(m, msg)
+	m.countKey(msg)
+	next, cmd := m.update(msg)
+	after, ok := next.(model)
+	if !ok {
+		return next, cmd
+	}
+	after = after.clearCoveredToast(m)
+	return after, tea.Batch(cmd, announce(m, after))
+}
+
+// update handles all application state changes and user interactions
+func (m model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 	var cmds []tea.Cmd
 
@@ -128,20 +238,329 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Adjust UI components based on window size
 		m.width = msg.Width
 		m.height = msg.Height
-		m.list.SetSize(msg.Width/2-4, msg.Height-10)
-		m.textarea.SetWidth(msg.Width/2 - 4)
-		m.textarea.SetHeight(msg.Height - 12)
+		m = m.resize()
 
 	case tea.KeyMsg:
+		// Record keys into a macro, and start or stop recording one
+		m = m.recordKey(msg)
+		if msg.String() == macroRecordKey && !m.replaying {
+			return m.toggleRecording(), nil
+		}
+
+		// Any key closes a QR code or popup
+		if (m.qrCode != "" || m.popup != "") && msg.Type != tea.KeyCtrlC {
+			m.qrCode, m.popup = "", ""
+			return m, nil
+		}
+
+		// An open prompt takes over the keyboard
+		if m.mode == "prompt" && msg.Type != tea.KeyCtrlC {
+			return m.updatePrompt(msg)
+		}
+
+		// The statistics screen takes over the keyboard while open
+		if m.mode == "stats" && msg.Type != tea.KeyCtrlC {
+			return m.updateStats(msg)
+		}
+
+		// A flashcard review takes over the keyboard while open
+		if m.mode == "review" && msg.Type != tea.KeyCtrlC {
+			return m.updateReview(msg)
+		}
+
+		// A quiz takes over the keyboard while open
+		if m.mode == "quiz" && msg.Type != tea.KeyCtrlC {
+			return m.updateQuiz(msg)
+		}
+
+		// Find and replace takes over the keyboard while open
+		if m.mode == "replace" && msg.Type != tea.KeyCtrlC {
+			return m.updateReplace(msg)
+		}
+
+		// Merging a note changed on disk takes over the keyboard
+		if m.mode == "merge" && msg.Type != tea.KeyCtrlC {
+			return m.updateMerge(msg)
+		}
+
+		// The diff takes over the keyboard while open
+		if m.mode == "diff" && msg.Type != tea.KeyCtrlC {
+			return m.updateDiff(msg)
+		}
+
+		// The scratchpad takes over the keyboard while open
+		if m.mode == "scratch" && msg.Type != tea.KeyCtrlC {
+			return m.updateScratch(msg)
+		}
+
+		// An open picker takes over the keyboard
+		if m.mode == "pick" && msg.Type != tea.KeyCtrlC {
+			return m.updatePicker(msg)
+		}
+
 		switch {
 		// Quit application
 		case msg.Type == tea.KeyCtrlC:
 			return m, tea.Quit
 
+		// Resize the list and content panes
+		case msg.Type == tea.KeyCtrlLeft:
+			return m.adjustSplit(-splitStep), nil
+		case msg.Type == tea.KeyCtrlRight:
+			return m.adjustSplit(splitStep), nil
+
+		// Hide or show the list and content panes
+		case msg.String() == "alt+l":
+			return m.toggleListPane(), nil
+		case msg.String() == "alt+c":
+			return m.toggleContentPane(), nil
+
+		// Editor display toggles
+		case msg.String() == "alt+n":
+			return m.toggleLineNumbers(), nil
+		case msg.String() == "alt+w":
+			return m.toggleWrap(), nil
+		case msg.String() == "alt+t":
+			return m.cycleTabWidth(), nil
+
+		// Continue markdown lists on enter
+		case msg.Type == tea.KeyEnter && (m.mode == "new" || m.mode == "edit") && m.textarea.Focused():
+			if next, ok := m.continueList(); ok {
+				return next, nil
+			}
+
+		// Expand a snippet abbreviation typed before a space. The space is
+		// typed after it, unless the snippet places the cursor itself.
+		case msg.Type == tea.KeySpace && (m.mode == "new" || m.mode == "edit") && m.textarea.Focused():
+			if body, _, ok := m.snippetBeforeCursor(); ok {
+				m, _ = m.expandSnippet()
+				if strings.Contains(body, snippetCursor) {
+					return m, nil
+				}
+			}
+
+		// Expand snippets, move between table cells, or indent with spaces, in the content editor
+		case msg.Type == tea.KeyTab && (m.mode == "new" || m.mode == "edit") && m.textarea.Focused():
+			if next, ok := m.expandSnippet(); ok {
+				return next, nil
+			}
+			if next, ok := m.tableCell(true); ok {
+				return next, nil
+			}
+			return m.insertTab(), nil
+		case msg.Type == tea.KeyShiftTab && (m.mode == "new" || m.mode == "edit") && m.textarea.Focused():
+			m, _ = m.tableCell(false)
+			return m, nil
+
+		// Table editing
+		case msg.String() == "alt+r" && (m.mode == "new" || m.mode == "edit"):
+			return m.addTableRow(), nil
+		case msg.String() == "alt+i" && (m.mode == "new" || m.mode == "edit"):
+			return m.addTableColumn(), nil
+		case msg.String() == "alt+f" && (m.mode == "new" || m.mode == "edit"):
+			row, col := cursorPos(m.textarea)
+			replaceValue(&m.textarea, m.editorText(), row, col)
+			return m, nil
+
+		// Rendered preview
+		case msg.String() == "alt+v":
+			return m.togglePreview(), nil
+
+		// Mark notes to compare
+		case msg.String() == "alt+m" && m.mode == "list" && m.selectedNote != nil:
+			return m.markForCompare(), nil
+
+		// Keep a note open alongside the others
+		case msg.String() == "alt+b" && m.mode != "scratch":
+			return m.toggleSideNote(), nil
+		case msg.String() == "alt+up" && m.sideNote != nil:
+			m.side.LineUp(1)
+			return m, nil
+		case msg.String() == "alt+down" && m.sideNote != nil:
+			m.side.LineDown(1)
+			return m, nil
+
+		// Copy the current note to the clipboard as HTML
+		case msg.String() == "alt+y" && (m.selectedNote != nil || m.mode == "new" || m.mode == "edit"):
+			return m.share("html", false)
+
+		// Publish the selected note as a secret gist
+		case msg.String() == "alt+s" && m.mode == "list" && m.selectedNote != nil && !readOnly:
+			return m.share("gist", false)
+
+		// Upload the selected note to a paste service
+		case msg.String() == "alt+p" && m.mode == "list" && m.selectedNote != nil:
+			return m.share("paste", false)
+
+		case msg.String() == "alt+Y" && m.mode == "list" && m.selectedNote != nil:
+			return m.copyCode(), nil
+
+		// Repeat the last change to a note's fields on the selected note
+		case msg.String() == repeatKey && m.mode == "list" && !m.contentFocus && !m.list.SettingFilter():
+			return m.repeatAction()
+
+		// Play the recorded macro
+		case msg.String() == macroPlayKey && m.mode == "list" && !m.replaying:
+			return m.chooseMacroTarget(), nil
+
+		// Rename the titles of the listed notes by a pattern
+		case msg.String() == "alt+X" && m.mode == "list" && !readOnly:
+			return m.startBulkRename()
+
+		// Find and replace across all notes
+		case msg.String() == "alt+A" && m.mode == "list" && !readOnly:
+			return m.startReplace()
+
+		// Add, remove or rename a tag across many notes
+		case msg.String() == "alt+G" && m.mode == "list" && !readOnly:
+			return m.startTagEdit()
+
+		// Start a note from a template
+		case msg.String() == "alt+C" && m.mode == "list" && !readOnly:
+			return m.startFromTemplate(), nil
+
+		// Start a meeting note, and list the open tasks of all notes
+		case msg.String() == "alt+M" && m.mode == "list" && !readOnly:
+			return m.startMeeting()
+		case msg.String() == "alt+T" && m.mode == "list":
+			return m.showTasks(), nil
+
+		// Review the flashcards that are due
+		case msg.String() == "alt+R" && m.mode == "list":
+			return m.startReview(), nil
+
+		// Notes written on this day in earlier months and years
+		case msg.String() == "alt+H" && m.mode == "list":
+			return m.openOnThisDay(), nil
+
+		// Attachments no note uses any more
+		case msg.String() == "alt+F" && m.mode == "list":
+			return m.openAttachments(), nil
+
+		// Full text of the last error
+		case msg.String() == "alt+E" && m.mode == "list":
+			return m.showErrorDetails(), nil
+
+		// Notes with no links in or out and no tags
+		case msg.String() == "alt+O" && m.mode == "list":
+			return m.openOrphans(), nil
+
+		// Links that lead to notes that do not exist
+		case msg.String() == "alt+K" && m.mode == "list":
+			return m.openBrokenLinks(), nil
+
+		// Pairs of identical or nearly identical notes
+		case msg.String() == "alt+U" && m.mode == "list":
+			return m.openDuplicates(), nil
+
+		// Deleted notes, to restore or purge
+		case msg.String() == "alt+D" && m.mode == "list":
+			return m.openTrash(), nil
+
+		// Who changed what in the vault, newest first
+		case msg.String() == "alt+L" && m.mode == "list":
+			return m.openAuditLog(), nil
+
+		// Jump to a random note in the list
+		case msg.String() == "alt+I" && m.mode == "list":
+			return m.surpriseMe(), nil
+
+		// Quiz yourself on the sections of the selected note
+		case msg.String() == "alt+Q" && m.mode == "list" && m.selectedNote != nil:
+			return m.startQuiz(), nil
+
+		// Save a web page as a bookmark note, or open the selected one
+		case msg.String() == "alt+N" && m.mode == "list" && !readOnly:
+			return m.openPrompt("bookmark", tr("URL"), "")
+		case msg.String() == "alt+B" && m.mode == "list" && m.selectedNote != nil:
+			return m.openBookmark(), nil
+
+		// Copy a gleaner:// link to the selected note
+		case msg.String() == "alt+k" && m.mode == "list" && m.selectedNote != nil:
+			return m.copyNoteLink(), nil
+
+		// Cycle the order the list is sorted in
+		case msg.String() == "alt+o" && m.mode == "list":
+			return m.cycleSortOrder()
+
+		case msg.String() == "alt+e" && m.mode == "list" && m.selectedNote != nil:
+			return m.showDetails(), nil
+
+		case msg.String() == "alt+j" && m.mode == "list" && m.selectedNote != nil && !readOnly:
+			return m.openLabelPicker(), nil
+
+		case msg.String() == "alt+J" && m.mode == "list":
+			return m.openLabelFilter(), nil
+
+		case msg.String() == "alt+S" && m.mode == "list" && m.selectedNote != nil && !readOnly:
+			return m.cycleStatus(false)
+
+		case msg.String() == "alt+u" && m.mode == "list" && m.selectedNote != nil && m.selectedNote.kind == noteTypePerson:
+			return m.showPerson(), nil
+		case msg.String() == "alt+u" && m.mode == "list" && m.selectedNote != nil:
+			return m.showFields(), nil
+
+		// Show the selected note as a QR code
+		case msg.String() == "alt+q" && m.mode == "list" && m.selectedNote != nil:
+			return m.share("qr", false)
+
+		// Jump between a footnote reference and its definition
+		case msg.String() == footnoteKey && (m.mode == "list" || m.mode == "new" || m.mode == "edit"):
+			return m.followFootnote(), nil
+
+		// Jump to a heading of the current note
+		case msg.String() == "alt+g" && (m.mode == "list" || m.mode == "new" || m.mode == "edit"):
+			return m.openOutline(), nil
+
+		// Open the scratchpad from anywhere
+		case msg.String() == "alt+x":
+			return m.toggleScratch()
+
+		// Distraction-free writing
+		case msg.String() == "alt+z":
+			return m.toggleZen(), nil
+
+		// Move focus between the list and content panes while browsing
+		case msg.Type == tea.KeyTab && m.mode == "list" && !m.list.SettingFilter():
+			return m.focusContent(!m.contentFocus), nil
+
+		// Show vault statistics
+		case msg.String() == "alt+a" && m.mode == "list":
+			return m.openStats()
+
+		// Switch the list pane to the timeline
+		case msg.String() == "alt+h" && m.mode == "list":
+			return m.toggleTimeline(), nil
+
+		// Limit the list to notes from a period
+		case msg.String() == "alt+d" && m.mode == "list":
+			return m.openDatePicker(), nil
+
+		// Change what the list filter searches
+		case msg.Type == tea.KeyCtrlT && m.mode == "list":
+			return m.cycleSearchScope()
+
+		// Toggle between detailed and compact list rows
+		case msg.Type == tea.KeyCtrlL && m.mode == "list":
+			return m.toggleDensity(), nil
+
+		// Run a plugin against the selected note
+		case msg.Type == tea.KeyCtrlP && m.mode == "list":
+			return m.openPluginPicker(), nil
+
+		// Switch to another vault
+		case msg.Type == tea.KeyCtrlO && m.mode == "list" && len(baseCfg.Vaults) > 0:
+			return m.openVaultPicker(), nil
+
 		// Refresh notes list
 		case msg.String() == "ctrl+u":
 			return m, loadNotes
 
+		// Back up the whole vault
+		case msg.Type == tea.KeyCtrlB && m.mode == "list":
+			m.status = tr("Creating backup...")
+			return m, backupVault(false)
+
 		// Switch from title input to content input for both new and edit modes
 		case msg.Type == tea.KeyTab && (m.mode == "new" || m.mode == "edit") && m.textInput.Focused():
 			m.titleEntered = true
@@ -150,41 +569,64 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 
 		// Enter new note mode
-		case msg.Type == tea.KeyCtrlN:
+		case msg.Type == tea.KeyCtrlN && !readOnly:
 			m.mode = "new"
 			m.textInput.Reset()
 			m.textarea.Reset()
 			m.titleEntered = false
+			// Only one field has focus, so tab reaches the title's case
+			m.textarea.Blur()
 			m.textInput.Focus()
 			m.selectedNote = nil
+			m.contentFocus = false
 
-		// Save note (new or edited)
-		case msg.Type == tea.KeyCtrlS && (m.mode == "new" || m.mode == "edit"):
+		// Save note (new or edited), merging first if the file changed on
+		// disk since editing began
+		case msg.Type == tea.KeyCtrlS && (m.mode == "new" || m.mode == "edit") && !readOnly:
 			if m.textInput.Value() != "" {
-				cmd = saveNote(m.textInput.Value(), m.textarea.Value(), m.selectedNote)
-				m.mode = "list"
-				m.textInput.Reset()
-				m.textarea.Reset()
-				m.titleEntered = false
-				m.selectedNote = nil
-				return m, tea.Batch(cmd, loadNotes)
+				if m.mode == "edit" && m.selectedNote != nil {
+					if disk, err := readNoteText(m.selectedNote.path); err == nil && disk != m.editBase {
+						return m.startMerge(disk), nil
+					}
+				}
+				return m.saveEdits(m.editorText())
 			}
 
 		// Delete selected note
-		case msg.Type == tea.KeyCtrlD && m.selectedNote != nil:
+		case msg.Type == tea.KeyCtrlD && m.selectedNote != nil && !readOnly:
 			return m, tea.Batch(deleteNote(m.selectedNote.path), loadNotes)
 
 		// Edit selected note
-		case msg.Type == tea.KeyCtrlE && m.selectedNote != nil:
+		case msg.Type == tea.KeyCtrlE && m.selectedNote != nil && !readOnly:
+			// Saving what an unreadable note shows would overwrite it
+			content, err := readNoteText(m.selectedNote.path)
+			if err != nil {
+				m.status = trf("This note cannot be read: %v", err)
+				return m, nil
+			}
 			m.mode = "edit"
+			m.editBase = content
 			m.textInput.SetValue(m.selectedNote.title)
-			content, _ := os.ReadFile(m.selectedNote.path)
-			m.textarea.SetValue(string(content))
+			m.textarea.SetValue(content)
+			m.textarea.Blur()
 			m.textInput.Focus()
 			m.titleEntered = true
+			m.contentFocus = false
+
+		// Work through a checklist note item by item
+		case m.checklistActive():
+			if next, cmd, ok := m.updateChecklist(msg); ok {
+				return next, cmd
+			}
+
+		// Move through the timeline instead of the flat list
+		case m.timeline && m.mode == "list" && !m.contentFocus && !m.list.SettingFilter():
+			if next, ok := m.updateTimeline(msg); ok {
+				return next, nil
+			}
 
 		// Enhanced list navigation
-		case (msg.Type == tea.KeyUp || msg.Type == tea.KeyDown) && m.mode == "list":
+		case (msg.Type == tea.KeyUp || msg.Type == tea.KeyDown) && m.mode == "list" && !m.contentFocus:
 			m.list, cmd = m.list.Update(msg)
 			
 			// Update selected note content immediately
@@ -192,21 +634,17 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				currentNote := selected.(note)
 				m.selectedNote = &currentNote
 				
-				content, err := os.ReadFile(currentNote.path)
-				if err == nil {
-					m.textarea.SetValue(string(content))
-				}
+				m.textarea.SetValue(noteText(currentNote.path))
 			}
 			
 			return m, cmd
 
 		// View note details
-		case msg.Type == tea.KeyEnter && m.mode == "list":
+		case msg.Type == tea.KeyEnter && m.mode == "list" && !m.contentFocus:
 			if selected := m.list.SelectedItem(); selected != nil {
 				note := selected.(note)
 				m.selectedNote = &note
-				content, _ := os.ReadFile(note.path)
-				m.textarea.SetValue(string(content))
+				m.textarea.SetValue(noteText(note.path))
 			}
 
 		// Return to list mode
@@ -218,24 +656,162 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.textInput.Blur()
 			m.textarea.Blur()
 			m.selectedNote = nil
+			m = m.focusContent(m.hideList)
 		}
 
+	// Report backup results
+	case backupMsg:
+		if len(msg.secrets) > 0 {
+			return m.openPrompt("confirm-backup", trf("These notes look like they contain credentials: %s. Type yes to back up anyway", secretsSummary(msg.secrets)), "")
+		}
+		if msg.err != nil {
+			m, cmd = m.notify(trf("Backup failed: %v", msg.err), msg.err)
+		} else {
+			m, cmd = m.notify(trf("Backup written to %s", msg.path), nil)
+		}
+		if msg.scheduled {
+			interval, _ := backupInterval()
+			return m, tea.Batch(cmd, scheduleBackup(interval))
+		}
+		return m, cmd
+
+	// Report plugin results and pick up any changes
+	case pluginMsg:
+		switch {
+		case msg.err != nil:
+			m, cmd = m.notify(trf("Plugin %s failed: %v", msg.name, msg.err), msg.err)
+		case msg.status != "":
+			m, cmd = m.notify(msg.name+": "+msg.status, nil)
+		default:
+			m, cmd = m.notify(trf("Plugin %s finished", msg.name), nil)
+		}
+		return m, tea.Batch(cmd, loadNotes)
+
+	// Report clipboard copies
+	case clipboardMsg:
+		switch {
+		case msg.err != nil:
+			return m.notify(trf("Copy failed: %v", msg.err), msg.err)
+		case msg.rich:
+			return m.notify(tr("Copied note as HTML"), nil)
+		default:
+			return m.notify(tr("Copied HTML source as text"), nil)
+		}
+
+	// Report shared gists
+	case gistMsg:
+		switch {
+		case msg.err != nil:
+			m, cmd = m.notify(trf("Sharing failed: %v", msg.err), msg.err)
+		case msg.updated:
+			m, cmd = m.notify(trf("Updated gist %s", msg.url), nil)
+		default:
+			m, cmd = m.notify(trf("Shared as %s", msg.url), nil)
+		}
+		return m, tea.Batch(cmd, loadNotes)
+
+	// Report paste uploads
+	case pasteMsg:
+		switch {
+		case msg.err != nil:
+			return m.notify(trf("Upload failed: %v", msg.err), msg.err)
+		case msg.copied:
+			return m.notify(trf("Copied %s", msg.url), nil)
+		default:
+			return m.notify(trf("Uploaded to %s", msg.url), nil)
+		}
+
+	// Report saved bookmarks
+	case bookmarkMsg:
+		switch {
+		case !msg.saved:
+			return m.notify(trf("Could not save the bookmark: %v", msg.err), msg.err)
+		case msg.err != nil:
+			m, cmd = m.notify(trf("Saved %s without page details: %v", msg.title, msg.err), msg.err)
+		default:
+			m, cmd = m.notify(trf("Saved bookmark %s", msg.title), nil)
+		}
+		return m, tea.Batch(cmd, loadNotes)
+
+	// Report failing hooks
+	case hookErrMsg:
+		return m.notify(msg.err.Error(), msg.err)
+
+	// Report the links changed to follow a renamed note
+	case linksUpdatedMsg:
+		m, cmd = m.notify(trf("Updated %d links to the renamed note", msg.count), msg.err)
+		return m, tea.Batch(cmd, loadNotes)
+
+	// Report failed saves and deletes, then show the notes as they are
+	case errMsg:
+		m, cmd = m.notify(tr(msg.summary)+": "+explainError(msg.err), msg.err)
+		if msg.draft != nil {
+			m = m.restoreDraft(*msg.draft)
+		}
+		return m, tea.Batch(cmd, loadNotes)
+
+	// Hide a toast once its time is up, unless a newer one replaced it
+	case toastExpiredMsg:
+		if msg.id == m.toast.id {
+			m.toast = toast{id: m.toast.id}
+		}
+		return m, nil
+
+	// Show whatever could be loaded and say what could not
+	case notesErrMsg:
+		m, cmd = m.notify(trf("Could not read notes: %v", msg.err), msg.err)
+		next, loadCmd := m.update(msg.notes)
+		return next, tea.Batch(cmd, loadCmd)
+
+	// Take a scheduled snapshot
+	case backupTickMsg:
+		return m, scheduledBackup
+
+	// Show the statistics once they are counted, unless the screen was left
+	case statsMsg:
+		if m.mode == "stats" {
+			m.statsText = msg.text
+		}
+		return m, nil
+
+	// Write out the usage counts kept in memory
+	case insightFlushMsg:
+		return m, tea.Batch(flushedInsights, scheduleInsightFlush())
+
 	// Handle notes loading
 	case []note:
-		// Sort notes by creation time (newest first)
+		// Sort notes by creation or modification time (newest first), pinned notes on top
 		sort.Slice(msg, func(i, j int) bool {
-			return msg[i].createdAt > msg[j].createdAt
+			if msg[i].pinned != msg[j].pinned {
+				return msg[i].pinned
+			}
+			if cfg.SortBy == sortPriority && msg[i].priority != msg[j].priority {
+				return morePressing(msg[i].priority, msg[j].priority)
+			}
+			return msg[i].listStamp() > msg[j].listStamp()
 		})
+		setPeople(msg)
+		embedNotes = msg
+		msg = filterByDate(msg, m.dateFilter)
+		msg = filterByLabel(msg, m.labelFilter)
 		m.notes = msg
+		m.wordsByDay = loadWordLog()[notesDir]
 		m.list.SetItems(itemsFromNotes(msg))
+		m.list.Title = listTitle()
+		if m.dateFilter.label != "" {
+			m.list.Title += " · " + m.dateFilter.label
+		}
+		if m.labelFilter != "" {
+			m.list.Title += " · " + tr(m.labelFilter)
+		}
 
-		// Select first note if available
-		if len(msg) > 0 {
+		// Select first note if available, leaving the editor alone while
+		// a note is being written
+		if len(msg) > 0 && m.mode != "new" && m.mode != "edit" {
 			if m.selectedNote == nil {
 				m.list.Select(0)
 				m.selectedNote = &msg[0]
-				content, _ := os.ReadFile(msg[0].path)
-				m.textarea.SetValue(string(content))
+				m.textarea.SetValue(noteText(msg[0].path))
 			} else {
 				// Try to maintain previous note selection
 				found := false
@@ -243,8 +819,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					if n.path == m.selectedNote.path {
 						m.list.Select(i)
 						m.selectedNote = &n
-						content, _ := os.ReadFile(n.path)
-						m.textarea.SetValue(string(content))
+						m.textarea.SetValue(noteText(n.path))
 						found = true
 						break
 					}
@@ -252,11 +827,43 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				if !found {
 					m.list.Select(0)
 					m.selectedNote = &msg[0]
-					content, _ := os.ReadFile(msg[0].path)
-					m.textarea.SetValue(string(content))
+					m.textarea.SetValue(noteText(msg[0].path))
 				}
 			}
 		}
+
+		// Say how many flashcards are waiting when gleaner starts, show
+		// what was written on this day when asked to, and empty old notes
+		// out of the trash
+		if !m.started {
+			m.started = true
+			purgeExpired()
+			if due := dueStatus(msg); due != "" && m.status == "" {
+				m.status = due
+			}
+			if cfg.OnThisDay && len(onThisDay(msg, localNow())) > 0 {
+				m = m.openOnThisDay()
+			}
+		}
+
+		// Return to where the last session left off
+		if m.resumeLine > 0 {
+			m = m.resumePosition()
+		}
+
+		// Run the search given on the command line
+		if m.search != "" && m.list.FilterState() == list.Unfiltered {
+			m, cmd = m.startSearch()
+			cmds = append(cmds, cmd)
+		}
+
+	// Show a result of the search given on the command line
+	case list.FilterMatchesMsg:
+		if m.search != "" {
+			m.list, cmd = m.list.Update(msg)
+			m.search = ""
+			return m.selectVisible(), cmd
+		}
 	}
 
 	// Update input components based on current mode
@@ -268,43 +875,185 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.textarea, cmd = m.textarea.Update(msg)
 			cmds = append(cmds, cmd)
 		}
+	} else if m.contentFocus {
+		// Only scrolling keys reach the content while browsing
+		if key, ok := msg.(tea.KeyMsg); !ok || isScrollKey(key) {
+			m.textarea, cmd = m.textarea.Update(msg)
+			cmds = append(cmds, cmd)
+		}
+	} else if m.mode == "pick" {
+		m.picker, cmd = m.picker.Update(msg)
+		cmds = append(cmds, cmd)
+	} else if m.mode == "scratch" {
+		m.scratch, cmd = m.scratch.Update(msg)
+		cmds = append(cmds, cmd)
+	} else if m.mode == "prompt" {
+		m.prompt, cmd = m.prompt.Update(msg)
+		cmds = append(cmds, cmd)
 	} else {
 		m.list, cmd = m.list.Update(msg)
 		cmds = append(cmds, cmd)
 	}
 
+	if m.preview {
+		m = m.resize() // The editor is narrower while previewing alongside it
+	}
+	m = m.trackEditorScroll()
 	return m, tea.Batch(cmds...)
 }
 
 // View renders the entire application UI
 func (m model) View() string {
+	if screenReader {
+		return m.linearView()
+	}
+
+	// Show only the picker while one is open
+	if m.mode == "pick" {
+		return docStyle.Render(lipgloss.JoinVertical(lipgloss.Top,
+			splitStyle.Width(m.width-8).Height(m.height-6).Render(m.picker.View()),
+			helpStyle.Render(tr("enter:Select | /:Filter | esc:Cancel")),
+		))
+	}
+
+	// Show only the statistics while they are open
+	if m.mode == "stats" {
+		return m.statsView()
+	}
+
+	// Show only the flashcard under review
+	if m.mode == "review" {
+		return m.reviewView()
+	}
+
+	// Show only the section being quizzed
+	if m.mode == "quiz" {
+		return m.quizView()
+	}
+
+	// Show only the matches of a find and replace
+	if m.mode == "replace" {
+		return m.replaceView()
+	}
+
+	// Show only the merge while settling changes made on disk
+	if m.mode == "merge" {
+		return m.mergeView()
+	}
+
+	// Show only the diff while comparing notes
+	if m.mode == "diff" {
+		return m.diffView()
+	}
+
+	// A QR code covers the whole screen until dismissed
+	if m.qrCode != "" {
+		return m.qrView()
+	}
+	if m.popup != "" {
+		return m.popupView()
+	}
+
+	// Zen mode shows nothing but the editor
+	if m.zen {
+		return m.zenView()
+	}
+
 	// Create list view
-	listView := splitStyle.
-		Width(m.width/2 - 36).
+	listWidth, contentWidth := m.paneWidths()
+	listContent := m.list.View()
+	// The list shows the filter being typed, and the timeline its result
+	if m.timeline && !m.list.SettingFilter() {
+		listContent = m.timelineView(listWidth-2, m.height-10)
+	}
+	listView := paneStyle(m.mode == "list" && !m.contentFocus).
+		Width(listWidth).
 		Height(m.height - 6).
-		Render(m.list.View())
+		Render(listContent)
 
 	// Create content view
 	var contentView string
-	if m.mode == "new" || m.mode == "edit" {
-		contentView = splitStyle.Width(m.width/2 +30).Render(
+	if m.mode == "scratch" {
+		contentView = paneStyle(true).Width(contentWidth).Render(
 			lipgloss.JoinVertical(lipgloss.Top,
-				titleStyle.Render(m.textInput.View()),
-				contentStyle.Render(m.textarea.View()),
+				titleStyle.Render(tr("Scratchpad")),
+				contentStyle.Render(m.scratch.View()),
+			),
+		)
+	} else if m.mode == "new" || m.mode == "edit" {
+		editor := m.textarea.View()
+		switch {
+		case m.sideNote != nil:
+			editor = lipgloss.JoinHorizontal(lipgloss.Top, editor, divider(lipgloss.Height(editor)), m.sideView())
+		case m.preview:
+			previewWidth := contentWidth - 2 - lipgloss.Width(editor) - 3
+			editor = lipgloss.JoinHorizontal(lipgloss.Top, editor, divider(lipgloss.Height(editor)),
+				m.previewView(previewWidth, m.textarea.Height()))
+		}
+		// A word goal's progress fills the gap below the title
+		header := titleStyle.Render(m.textInput.View())
+		if bar := m.goalBar(contentWidth - 4); bar != "" {
+			header = titleStyle.MarginBottom(0).Render(m.textInput.View()) + "\n" + bar
+		}
+		contentView = paneStyle(true).Width(contentWidth).Render(
+			lipgloss.JoinVertical(lipgloss.Top,
+				header,
+				contentStyle.Render(editor),
 			),
 		)
 	} else {
-		contentView = splitStyle.
-			Width(m.width/2 +30).
+		content := m.textarea.View()
+		switch {
+		case m.selectedNote != nil && m.selectedNote.kind == noteTypeChecklist && !m.preview:
+			content = m.checklistView(contentWidth-4, m.textarea.Height())
+		case m.selectedNote != nil && m.selectedNote.kind == noteTypeSnippet && !m.preview:
+			content = m.snippetView(contentWidth-4, m.textarea.Height())
+		case m.preview:
+			content = m.previewView(contentWidth-4, m.textarea.Height())
+		case !m.wrap:
+			content = m.unwrappedView()
+		}
+		if m.sideNote != nil {
+			content = lipgloss.JoinHorizontal(lipgloss.Top, content, divider(lipgloss.Height(content)), m.sideView())
+		}
+		contentView = paneStyle(m.contentFocus).
+			Width(contentWidth).
 			Height(m.height - 6).
-			Render(contentStyle.Render(m.textarea.View()))
+			Render(contentStyle.Render(content))
 	}
 
-	// Render help text
-	helpView := helpStyle.Render(helpText)
+	// Render help text, prefixed by the latest status message
+	help := helpText()
+	if m.checklistActive() && !readOnly {
+		help = tr("space:Toggle | a:Add | e:Edit | d:Delete | shift+↑/↓:Move | esc:Back")
+	}
+	if progress := writingProgress(m.wordsByDay, localNow()); progress != "" {
+		help = progress + " | " + help
+	}
+	if m.recording {
+		help = recordingStyle.Render(tr("● Recording macro")) + " | " + help
+	}
+	switch {
+	case m.toast.text != "":
+		help = m.toastView(max(1, m.width-8)) + "\n" + help
+	case m.status != "":
+		help = m.status + "\n" + help
+	}
+	if m.mode == "prompt" {
+		help = m.prompt.View() + "\n" + tr("enter:Confirm | esc:Cancel")
+	}
+	helpView := helpStyle.Width(max(0, m.width-4)).Render(help)
 	
-	// Combine all views
-	mainView := lipgloss.JoinHorizontal(lipgloss.Top, listView, contentView)
+	// Combine all visible panes
+	var mainView string
+	switch {
+	case m.hideList:
+		mainView = contentView
+	case m.hideContent:
+		mainView = listView
+	default:
+		mainView = lipgloss.JoinHorizontal(lipgloss.Top, listView, contentView)
+	}
 	return docStyle.Render(
 		lipgloss.JoinVertical(lipgloss.Top, mainView, helpView),
 	)
@@ -312,19 +1061,215 @@ func (m model) View() string {
 
 // Main application entry point
 func main() {
-	// Ensure notes directory exists
-	if _, err := os.Stat(notesDir); os.IsNotExist(err) {
-		os.Mkdir(notesDir, 0755)
+	dirFlag := flag.String("dir", "", "notes directory (overrides GLEANER_DIR and the config file)")
+	vaultFlag := flag.String("vault", "", "name of a vault from the config file to open")
+	noteFlag := flag.String("note", "", "title or id of a note to open on launch")
+	searchFlag := flag.String("search", "", "filter the notes list on launch")
+	flag.BoolVar(&readOnlyFlag, "read-only", false, "browse notes without allowing changes")
+	noColorFlag := flag.Bool("no-color", false, "style with bold and underline only (also set by NO_COLOR)")
+	screenReaderFlag := flag.Bool("screen-reader", false, "plain labeled output without borders, for screen readers")
+	headlessFlag := flag.Bool("headless", false, "drive the interface from a script instead of the terminal")
+	scriptFlag := flag.String("script", "", "script for --headless to run (default standard input)")
+	debugFlag := flag.Bool("debug", false, "log key presses, file writes and background steps to the log file")
+	portableFlag := flag.Bool("portable", false, "keep notes, config and logs in gleaner-data next to the executable")
+	flag.Parse()
+	if err := setupPortable(*portableFlag); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	closeLog := setupLogging(*debugFlag)
+	defer closeLog()
+
+	// Load user settings
+	if err := loadConfig(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading config: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Move notes and backups out of the home folder, where older versions
+	// kept them
+	var moved string
+	explicitDir := *dirFlag != "" || os.Getenv("GLEANER_DIR") != ""
+	if !readOnlyFlag && !baseCfg.ReadOnly {
+		var err error
+		if moved, err = migrateDataDirs(explicitDir); err != nil {
+			slog.Warn("migrating data directories failed", "err", err)
+			fmt.Fprintf(os.Stderr, "Warning: %s\n", explainError(err))
+		}
+	}
+	notesDir = resolveNotesDir(*dirFlag)
+	readOnly = readOnlyFlag || baseCfg.ReadOnly
+	setLocale()
+	if err := setTimezone(baseCfg.Timezone); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	screenReader = *screenReaderFlag || baseCfg.ScreenReader
+	applyTheme(baseCfg.Theme, *noColorFlag)
+	if !*headlessFlag && !screenReader {
+		imageProtocol = detectImageProtocol(baseCfg.ImageProtocol)
+	}
+
+	// Pick the vault: explicit flag, then configured default; with several
+	// vaults and no choice made, ask once the TUI starts
+	vault := *vaultFlag
+	if vault == "" && !explicitDir {
+		vault = baseCfg.DefaultVault
+	}
+	askVault := vault == "" && !explicitDir && baseCfg.NotesDir == "" && len(baseCfg.Vaults) > 1
+	if askVault {
+		vault = vaultNames()[0]
+	}
+	if vault != "" {
+		if err := useVault(vault); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	var dirWarning string
+	if !readOnly {
+		warning, err := ensureNotesDir()
+		if err != nil {
+			slog.Error("cannot create notes directory", "dir", notesDir, "err", err)
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if warning != "" {
+			slog.Warn("notes directory is not writable", "dir", notesDir)
+		}
+		dirWarning = warning
+	}
+	slog.Debug("start", "dir", notesDir, "vault", activeVault, "read_only", readOnly, "command", flag.Arg(0))
+
+	// Run a subcommand instead of the TUI when one is given
+	if flag.NArg() > 0 {
+		if moved != "" {
+			fmt.Fprintln(os.Stderr, moved)
+		}
+		if dirWarning != "" {
+			fmt.Fprintf(os.Stderr, "Warning: %s\n", dirWarning)
+		}
+		err := runCommand(flag.Arg(0), flag.Args()[1:])
+		if err == nil {
+			countCommand("gleaner " + flag.Arg(0))
+		}
+		flushInsights()
+		if err != nil {
+			slog.Error("command failed", "command", flag.Arg(0), "err", err)
+			fmt.Fprintf(os.Stderr, "Error: %s\n", explainError(err))
+			os.Exit(1)
+		}
+		return
 	}
 
 	// Start the Bubble Tea program
-	p := tea.NewProgram(initialModel(), tea.WithAltScreen())
-	if _, err := p.Run(); err != nil {
+	m := initialModel()
+	if *noteFlag != "" {
+		notes, err := notesForCommand()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		n, err := findNote(notes, *noteFlag)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		m.selectedNote = &n
+	}
+	m.search = *searchFlag
+	m.status = dirWarning
+	if m.status == "" {
+		m.status = moved
+	}
+	if *headlessFlag {
+		if err := runHeadless(m, *scriptFlag); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if *noteFlag == "" && *searchFlag == "" {
+		m = m.restoreSession()
+	}
+	if askVault {
+		m = m.openVaultPicker()
+	}
+	if err := runTUI(m); err != nil {
 		fmt.Printf("Error: %v", err)
 		os.Exit(1)
 	}
 }
 
+// runTUI runs the interface until the user quits, then remembers where they
+// left off
+func runTUI(m model) error {
+	// Screen readers follow output printed line by line, which the
+	// alternate screen would hide
+	var options []tea.ProgramOption
+	if !screenReader {
+		options = append(options, tea.WithAltScreen())
+	}
+	final, err := tea.NewProgram(m, options...).Run()
+	flushInsights()
+	if err != nil {
+		return err
+	}
+	if err := final.(model).saveSession(); err != nil {
+		fmt.Fprintf(os.Stderr, "Could not save session: %v\n", err)
+	}
+	return nil
+}
+
+// Switch list density and remember the choice in the config file
+func (m model) toggleDensity() model {
+	density := densityCompact
+	if cfg.ListDensity == densityCompact {
+		density = densityDetailed
+	}
+	cfg.ListDensity = density
+	baseCfg.ListDensity = density
+	m.list.SetDelegate(newNoteDelegate(density))
+
+	if err := saveConfig(); err != nil {
+		m.status = trf("Could not save list density: %v", err)
+	}
+	return m
+}
+
+// listStamp returns the time the list orders and dates notes by
+func (n note) listStamp() int64 {
+	if cfg.SortBy == sortModified {
+		return n.modifiedAt
+	}
+	return n.createdAt
+}
+
+// Cycle the list order between creation time, last modification and
+// priority, and remember the choice in the config file
+func (m model) cycleSortOrder() (model, tea.Cmd) {
+	var order string
+	switch cfg.SortBy {
+	case sortModified:
+		order = sortPriority
+		m.status = tr("Sorted by priority")
+	case sortPriority:
+		order = sortCreated
+		m.status = tr("Sorted by creation time")
+	default:
+		order = sortModified
+		m.status = tr("Sorted by last modified")
+	}
+	cfg.SortBy = order
+	baseCfg.SortBy = order
+
+	if err := saveConfig(); err != nil {
+		m.status = trf("Could not save sort order: %v", err)
+	}
+	return m, loadNotes
+}
+
 // Convert notes to list items for display
 func itemsFromNotes(notes []note) []list.Item {
 	items := make([]list.Item, len(notes))
@@ -336,31 +1281,45 @@ func itemsFromNotes(notes []note) []list.Item {
 
 // Load notes from the notes directory
 func loadNotes() tea.Msg {
-	files, _ := os.ReadDir(notesDir)
-	var notes []note
-
-	for _, f := range files {
-		if filepath.Ext(f.Name()) == ".md" {
-			nameParts := strings.SplitN(f.Name(), "-", 2)
-			if len(nameParts) < 2 {
-				continue
-			}
+	notes, err := scanNotes()
+	if err != nil {
+		return notesErrMsg{notes, err}
+	}
+	return notes
+}
 
-			timestamp, err := strconv.ParseInt(nameParts[0], 10, 64)
-			if err != nil {
-				continue
-			}
+// Extract the title and creation timestamp from a "<unixtime>-title.md" filename
+func parseNoteFilename(name string) (string, int64, bool) {
+	ext := filepath.Ext(name)
+	if !isNoteExt(ext) {
+		return "", 0, false
+	}
+	nameParts := strings.SplitN(name, "-", 2)
+	if len(nameParts) < 2 {
+		return "", 0, false
+	}
 
-			cleanName := strings.TrimSuffix(nameParts[1], ".md")
-			cleanName = strings.ReplaceAll(cleanName, "-", " ")
-			notes = append(notes, note{
-				title:     cleanName,
-				path:      filepath.Join(notesDir, f.Name()),
-				createdAt: timestamp,
-			})
-		}
+	timestamp, err := strconv.ParseInt(nameParts[0], 10, 64)
+	if err != nil {
+		return "", 0, false
 	}
-	return notes
+
+	cleanName := strings.TrimSuffix(nameParts[1], ext)
+	cleanName = strings.ReplaceAll(cleanName, "-", " ")
+	return cleanName, timestamp, true
+}
+
+// saveEdits saves the note in the editor with the given content and
+// returns to the list
+func (m model) saveEdits(content string) (tea.Model, tea.Cmd) {
+	cmd := saveNote(m.textInput.Value(), content, m.selectedNote)
+	m.mode = "list"
+	m.textInput.Reset()
+	m.textarea.Reset()
+	m.titleEntered = false
+	m.selectedNote = nil
+	m = m.focusContent(m.hideList)
+	return m, tea.Batch(cmd, loadNotes)
 }
 
 // Save a note, preserving original timestamp for existing notes
@@ -374,36 +1333,112 @@ func saveNote(title, content string, existingNote *note) tea.Cmd {
 			filenameParts := strings.SplitN(filepath.Base(existingNote.path), "-", 2)
 			originalTimestamp := filenameParts[0]
 			
-			path = filepath.Join(notesDir, fmt.Sprintf("%s-%s.md", originalTimestamp, sanitized))
-			os.Remove(existingNote.path)
+			// Keep the note in its folder and format
+			path = filepath.Join(filepath.Dir(existingNote.path), fmt.Sprintf("%s-%s%s", originalTimestamp, sanitized, filepath.Ext(existingNote.path)))
 		} else {
-			path = filepath.Join(notesDir, fmt.Sprintf("%d-%s.md", time.Now().Unix(), sanitized))
+			path = filepath.Join(notesDir, fmt.Sprintf("%d-%s%s", time.Now().Unix(), sanitized, newNoteExt()))
 		}
 
-		// Directly save the full content
-		err := os.WriteFile(path, []byte(content), 0644)
+		// Keep a frontmatter title in step with a renamed note
+		if fields, _ := parseFrontmatter(content); metaValue(fields, "title") != "" {
+			content = setFrontmatter(content, "title", yamlString(title))
+		}
+
+		// Let pre-save hooks veto the save, reopening the editor on the
+		// unsaved text
+		if err := runHooks(hookPreSave, path, content); err != nil {
+			return errMsg{"Could not save the note", err, &draft{title, content, existingNote}}
+		}
+		var previous []byte
+		if existingNote != nil {
+			previous, _ = readNoteFile(existingNote.path)
+		}
+
+		// Directly save the full content, removing the old file of a
+		// renamed note only once the new one is written. On filesystems
+		// that ignore case both names may be the same file.
+		data := []byte(content)
+		if existingNote != nil {
+			data = matchLineEndings(existingNote.path, data)
+		}
+		err := storeNoteFile(path, data)
 		if err != nil {
-			fmt.Printf("Error saving note: %v", err)
+			slog.Error("saving note failed", "path", path, "err", err)
+			return errMsg{"Could not save the note", err, &draft{title, content, existingNote}}
+		}
+		if existingNote != nil && existingNote.path != path {
+			oldInfo, oldErr := os.Stat(existingNote.path)
+			newInfo, newErr := os.Stat(path)
+			if oldErr == nil && newErr == nil && !os.SameFile(oldInfo, newInfo) {
+				os.Remove(existingNote.path)
+			}
+		}
+		recordWords(string(previous), content)
+		if existingNote != nil {
+			recordChange(auditEdit, path, string(previous), content)
+		} else {
+			recordChange(auditCreate, path, "", content)
+		}
+		hookErr := runHooks(hookPostSave, path, content)
+
+		// Point links to a renamed note at its new title; the note is saved
+		// under it even when a post-save hook failed
+		if existingNote != nil && existingNote.title != title {
+			if count, err := relinkRenamed(*existingNote, path, title); count > 0 || err != nil {
+				return linksUpdatedMsg{count, errors.Join(err, hookErr)}
+			}
+		}
+		if hookErr != nil {
+			return hookErrMsg{hookErr}
 		}
 		return loadNotes()
 	}
 }
 
-// Delete a note from the filesystem
+// Delete a note by moving it to the trash
 func deleteNote(path string) tea.Cmd {
 	return func() tea.Msg {
-		os.Remove(path)
+		previous, _ := readNoteFile(path)
+		if err := trashNote(path); err != nil {
+			slog.Error("deleting note failed", "path", path, "err", err)
+			return errMsg{"Could not delete the note", err, nil}
+		}
+		recordChange(auditDelete, path, string(previous), "")
+		if err := runHooks(hookPostDelete, path, ""); err != nil {
+			return hookErrMsg{err}
+		}
 		return loadNotes()
 	}
 }
 
-// Sanitize filename to remove invalid characters
+// Longest title kept in a file name, in bytes, leaving room for the
+// timestamp and extension within the 255-byte limit of most file systems
+const maxFileNameBytes = 200
+
+// Names Windows reserves for devices, whatever their extension
+var windowsReservedNames = map[string]bool{
+	"CON": true, "PRN": true, "AUX": true, "NUL": true,
+	"COM1": true, "COM2": true, "COM3": true, "COM4": true, "COM5": true, "COM6": true, "COM7": true, "COM8": true, "COM9": true,
+	"LPT1": true, "LPT2": true, "LPT3": true, "LPT4": true, "LPT5": true, "LPT6": true, "LPT7": true, "LPT8": true, "LPT9": true,
+}
+
+// Sanitize filename to remove invalid characters. Only letters, digits, -
+// and _ are kept, which leaves nothing Windows forbids such as :, \ or a
+// trailing dot; overlong titles are cut short and device names like CON
+// get a trailing _.
 func sanitizeFileName(input string) string {
-	name := strings.TrimSuffix(input, ".md")
-	return strings.Map(func(r rune) rune {
+	name := strings.Map(func(r rune) rune {
 		if unicode.IsLetter(r) || unicode.IsNumber(r) || r == '-' || r == '_' {
 			return r
 		}
 		return '-'
-	}, name)
+	}, trimNoteExt(input))
+	for len(name) > maxFileNameBytes {
+		_, size := utf8.DecodeLastRuneInString(name)
+		name = name[:len(name)-size]
+	}
+	if windowsReservedNames[strings.ToUpper(name)] {
+		name += "_"
+	}
+	return name
 }
\ No newline at end of file
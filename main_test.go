@@ -0,0 +1,26 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSanitizeFileName(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"Hello World", "Hello-World"},
+		{"a/b:c*d?", "a-b-c-d-"},
+		{"Ünïcödé 2024", "Ünïcödé-2024"},
+		{"snake_case-kebab", "snake_case-kebab"},
+		{"notes.md", "notes"},
+		{"CON", "CON_"},
+		{"lpt1", "lpt1_"},
+		{"", ""},
+		{strings.Repeat("x", 300), strings.Repeat("x", maxFileNameBytes)},
+		{strings.Repeat("ü", 150), strings.Repeat("ü", maxFileNameBytes/2)},
+	}
+	for _, tt := range tests {
+		if got := sanitizeFileName(tt.in); got != tt.want {
+			t.Errorf("sanitizeFileName(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,310 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Styling of math in the preview
+var previewMathStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("180"))
+
+// Matches $...$ inline math. The text may not start or end with a space, so
+// "$5 and $10" is not math; the characters around the $ signs are checked
+// separately.
+var inlineMathPattern = regexp.MustCompile(`\$([^$\s](?:[^$]*[^$\s])?)\$`)
+
+// LaTeX commands written as a single character
+var texSymbols = map[string]string{
+	"alpha": "α", "beta": "β", "gamma": "γ", "delta": "δ", "epsilon": "ε", "varepsilon": "ε",
+	"zeta": "ζ", "eta": "η", "theta": "θ", "vartheta": "ϑ", "iota": "ι", "kappa": "κ",
+	"lambda": "λ", "mu": "μ", "nu": "ν", "xi": "ξ", "pi": "π", "rho": "ρ", "sigma": "σ",
+	"tau": "τ", "upsilon": "υ", "phi": "φ", "varphi": "φ", "chi": "χ", "psi": "ψ", "omega": "ω",
+	"Gamma": "Γ", "Delta": "Δ", "Theta": "Θ", "Lambda": "Λ", "Xi": "Ξ", "Pi": "Π",
+	"Sigma": "Σ", "Upsilon": "Υ", "Phi": "Φ", "Psi": "Ψ", "Omega": "Ω",
+
+	"times": "×", "cdot": "·", "div": "÷", "pm": "±", "mp": "∓", "ast": "∗", "star": "⋆",
+	"circ": "∘", "bullet": "•", "oplus": "⊕", "otimes": "⊗",
+	"leq": "≤", "le": "≤", "geq": "≥", "ge": "≥", "neq": "≠", "ne": "≠", "approx": "≈",
+	"equiv": "≡", "sim": "∼", "simeq": "≃", "cong": "≅", "propto": "∝", "ll": "≪", "gg": "≫",
+	"in": "∈", "notin": "∉", "ni": "∋", "subset": "⊂", "subseteq": "⊆", "supset": "⊃",
+	"supseteq": "⊇", "cup": "∪", "cap": "∩", "setminus": "∖", "emptyset": "∅", "varnothing": "∅",
+	"forall": "∀", "exists": "∃", "neg": "¬", "lnot": "¬", "land": "∧", "wedge": "∧",
+	"lor": "∨", "vee": "∨", "implies": "⟹", "iff": "⟺",
+	"to": "→", "rightarrow": "→", "leftarrow": "←", "leftrightarrow": "↔", "Rightarrow": "⇒",
+	"Leftarrow": "⇐", "Leftrightarrow": "⇔", "mapsto": "↦", "uparrow": "↑", "downarrow": "↓",
+	"infty": "∞", "partial": "∂", "nabla": "∇", "sum": "∑", "prod": "∏", "int": "∫",
+	"iint": "∬", "oint": "∮", "prime": "′", "degree": "°", "angle": "∠", "perp": "⊥",
+	"parallel": "∥", "ldots": "…", "cdots": "⋯", "vdots": "⋮", "ddots": "⋱", "dots": "…",
+	"hbar": "ℏ", "ell": "ℓ", "Re": "ℜ", "Im": "ℑ", "aleph": "ℵ",
+	"langle": "⟨", "rangle": "⟩", "lfloor": "⌊", "rfloor": "⌋", "lceil": "⌈", "rceil": "⌉",
+	"lbrace": "{", "rbrace": "}", "vert": "|", "mid": "|", "Vert": "‖",
+	"quad": "  ", "qquad": "    ",
+}
+
+// Commands that only size or place what follows them, and are dropped
+var texIgnored = map[string]bool{
+	"left": true, "right": true, "big": true, "Big": true, "bigg": true, "Bigg": true,
+	"bigl": true, "bigr": true, "Bigl": true, "Bigr": true, "displaystyle": true,
+	"limits": true, "nolimits": true, "nonumber": true,
+}
+
+// Combining marks for accents over a symbol
+var texAccents = map[string]string{
+	"hat": "̂", "bar": "̄", "overline": "̅", "vec": "⃗",
+	"dot": "̇", "ddot": "̈", "tilde": "̃",
+}
+
+// Characters with a superscript or subscript form
+var (
+	superscripts = charMap("0123456789+-=()niabcdefghjklmoprstuvwxyz′∘", "⁰¹²³⁴⁵⁶⁷⁸⁹⁺⁻⁼⁽⁾ⁿⁱᵃᵇᶜᵈᵉᶠᵍʰʲᵏˡᵐᵒᵖʳˢᵗᵘᵛʷˣʸᶻ′°")
+	subscripts   = charMap("0123456789+-=()aehijklmnoprstuvx", "₀₁₂₃₄₅₆₇₈₉₊₋₌₍₎ₐₑₕᵢⱼₖₗₘₙₒₚᵣₛₜᵤᵥₓ")
+	doubleStruck = charMap("ABCDEFGHIJKLMNOPQRSTUVWXYZ", "𝔸𝔹ℂ𝔻𝔼𝔽𝔾ℍ𝕀𝕁𝕂𝕃𝕄ℕ𝕆ℙℚℝ𝕊𝕋𝕌𝕍𝕎𝕏𝕐ℤ")
+)
+
+// charMap pairs the characters of two strings
+func charMap(from, to string) map[rune]rune {
+	m := map[rune]rune{}
+	toRunes := []rune(to)
+	for i, r := range []rune(from) {
+		m[r] = toRunes[i]
+	}
+	return m
+}
+
+// mapChars converts every character of s through m, or returns false when
+// one has no counterpart
+func mapChars(s string, m map[rune]rune) (string, bool) {
+	var b strings.Builder
+	for _, r := range s {
+		mapped, ok := m[r]
+		if !ok {
+			return "", false
+		}
+		b.WriteRune(mapped)
+	}
+	return b.String(), true
+}
+
+// texParser approximates LaTeX math with unicode text
+type texParser struct {
+	src []rune
+	pos int
+}
+
+// texToText renders LaTeX math as unicode text, as near as plain text
+// allows: symbols become their characters, simple superscripts and
+// subscripts their small forms, and fractions a slash. Line breaks (\\)
+// start new lines.
+func texToText(tex string) string {
+	p := &texParser{src: []rune(tex)}
+	var b strings.Builder
+	for p.pos < len(p.src) {
+		b.WriteString(p.group()) // Unbalanced closing braces are skipped
+	}
+	var lines []string
+	for _, line := range strings.Split(b.String(), "\n") {
+		lines = append(lines, strings.Join(strings.Fields(line), " "))
+	}
+	return strings.Trim(strings.Join(lines, "\n"), "\n")
+}
+
+// group renders up to the closing brace of the group, or the end
+func (p *texParser) group() string {
+	var b strings.Builder
+	for p.pos < len(p.src) {
+		if p.src[p.pos] == '}' {
+			p.pos++
+			break
+		}
+		b.WriteString(p.token())
+	}
+	return b.String()
+}
+
+// arg renders the argument of a command: a braced group, a command or a
+// single character
+func (p *texParser) arg() string {
+	for p.pos < len(p.src) && unicode.IsSpace(p.src[p.pos]) {
+		p.pos++
+	}
+	if p.pos >= len(p.src) {
+		return ""
+	}
+	if p.src[p.pos] == '{' {
+		p.pos++
+		return p.group()
+	}
+	return p.token()
+}
+
+// token renders the next character, group or command
+func (p *texParser) token() string {
+	r := p.src[p.pos]
+	p.pos++
+	switch r {
+	case '{':
+		return p.group()
+	case '}':
+		return ""
+	case '^':
+		return raised(p.arg(), superscripts, "^")
+	case '_':
+		return raised(p.arg(), subscripts, "_")
+	case '&', '~':
+		return " "
+	case '\\':
+		return p.command()
+	}
+	return string(r)
+}
+
+// command renders the command after a backslash
+func (p *texParser) command() string {
+	if p.pos >= len(p.src) {
+		return ""
+	}
+	start := p.pos
+	for p.pos < len(p.src) && unicode.IsLetter(p.src[p.pos]) {
+		p.pos++
+	}
+	name := string(p.src[start:p.pos])
+	if name == "" {
+		// A single character: spacing, an escaped character or a line break
+		r := p.src[p.pos]
+		p.pos++
+		switch r {
+		case '\\':
+			return "\n"
+		case ',', ';', ':', ' ':
+			return " "
+		case '!':
+			return ""
+		}
+		return string(r)
+	}
+
+	if symbol, ok := texSymbols[name]; ok {
+		return symbol
+	}
+	if accent, ok := texAccents[name]; ok {
+		return p.arg() + accent
+	}
+	switch {
+	case texIgnored[name]:
+		// \left. and \right. stand for no delimiter at all
+		if p.pos < len(p.src) && p.src[p.pos] == '.' {
+			p.pos++
+		}
+		return ""
+	case name == "frac" || name == "dfrac" || name == "tfrac":
+		return fraction(p.arg(), p.arg())
+	case name == "sqrt":
+		root := "√"
+		if p.pos < len(p.src) && p.src[p.pos] == '[' {
+			end := p.pos
+			for end < len(p.src) && p.src[end] != ']' {
+				end++
+			}
+			switch strings.TrimSpace(string(p.src[p.pos+1 : end])) {
+			case "3":
+				root = "∛"
+			case "4":
+				root = "∜"
+			}
+			p.pos = min(end+1, len(p.src))
+		}
+		return root + parenthesize(p.arg())
+	case name == "mathbb":
+		arg := p.arg()
+		if struck, ok := mapChars(arg, doubleStruck); ok {
+			return struck
+		}
+		return arg
+	case name == "text" || name == "mathrm" || name == "mathit" || name == "mathbf" ||
+		name == "mathsf" || name == "mathcal" || name == "textbf" || name == "textit" ||
+		name == "operatorname" || name == "boldsymbol":
+		return p.arg()
+	case name == "begin" || name == "end":
+		p.arg() // The environment's name
+		return ""
+	}
+	// Function names such as \sin and \log, and anything unknown, as written
+	return name
+}
+
+// raised writes a superscript or subscript in small characters when each
+// has one, and otherwise after ^ or _, bracketed when longer than one
+// character
+func raised(s string, small map[rune]rune, mark string) string {
+	if mapped, ok := mapChars(s, small); ok && s != "" {
+		return mapped
+	}
+	if len([]rune(s)) == 1 {
+		return mark + s
+	}
+	return mark + "(" + s + ")"
+}
+
+// Fractions with a single character of their own
+var vulgarFractions = map[string]string{
+	"1/2": "½", "1/3": "⅓", "2/3": "⅔", "1/4": "¼", "3/4": "¾", "1/5": "⅕", "1/8": "⅛",
+}
+
+// fraction writes a fraction with a slash, bracketing a numerator or
+// denominator of more than one term
+func fraction(num, den string) string {
+	num, den = strings.TrimSpace(num), strings.TrimSpace(den)
+	if single, ok := vulgarFractions[num+"/"+den]; ok {
+		return single
+	}
+	return parenthesize(num) + "/" + parenthesize(den)
+}
+
+// parenthesize brackets an expression of more than one term
+func parenthesize(s string) string {
+	s = strings.TrimSpace(s)
+	if strings.ContainsAny(s, " +-−±·×=,/") {
+		return "(" + s + ")"
+	}
+	return s
+}
+
+// renderInlineMath renders the $...$ math in a line and passes the text
+// around it to render. Dollar signs in code spans are left alone.
+func renderInlineMath(text string, render func(string) string) string {
+	// Blank out code spans, keeping their length, so matches index text
+	outside := inlineCodePattern.ReplaceAllStringFunc(text, func(s string) string {
+		return strings.Repeat("`", len(s))
+	})
+	wordChar := func(c byte) bool {
+		return c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, loc := range inlineMathPattern.FindAllStringSubmatchIndex(outside, -1) {
+		start, end := loc[0], loc[1]
+		// $$ belongs to display math, and a letter or digit touching the
+		// outside of either $ makes it a price or a variable like $HOME
+		if start > 0 && (text[start-1] == '\\' || wordChar(text[start-1])) || end < len(text) && (wordChar(text[end]) || text[end] == '$') {
+			continue
+		}
+		b.WriteString(render(text[last:start]))
+		b.WriteString(previewMathStyle.Render(texToText(text[loc[2]:loc[3]])))
+		last = end
+	}
+	b.WriteString(render(text[last:]))
+	return b.String()
+}
+
+// renderMathBlock renders display math, one output line per \\ line
+func renderMathBlock(tex string, width int) []string {
+	var lines []string
+	for _, line := range strings.Split(texToText(tex), "\n") {
+		lines = append(lines, wrapLines("  "+previewMathStyle.Render(line), width)...)
+	}
+	return lines
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestTexToText(t *testing.T) {
+	tests := []struct{ tex, want string }{
+		{`x^2 + y_i`, "x² + yᵢ"},
+		{`x^{10}`, "x¹⁰"},
+		{`e^{i\pi}`, "e^(iπ)"},
+		{`\frac{a+b}{2}`, "(a+b)/2"},
+		{`\sqrt{x}`, "√x"},
+		{`\alpha \to \infty`, "α → ∞"},
+		{`\sum_{i=1}^n i`, "∑ᵢ₌₁ⁿ i"},
+		{`\mathbf{v}`, "v"},
+		{`a \\ b`, "a\nb"},
+		{`x}`, "x"},
+	}
+	for _, tt := range tests {
+		if got := texToText(tt.tex); got != tt.want {
+			t.Errorf("texToText(%q) = %q, want %q", tt.tex, got, tt.want)
+		}
+	}
+}
+
+func TestRenderInlineMath(t *testing.T) {
+	plain := func(s string) string { return "[" + s + "]" }
+	tests := []struct{ text, want string }{
+		{`area $\pi r^2$.`, "[area ]π r²[.]"},
+		{`($a$)`, "[(]a[)]"},
+		{`costs $5 and $10`, "[costs $5 and $10]"},
+		{`echo $HOME/bin:$PATH`, "[echo $HOME/bin:$PATH]"},
+		{`x$a$ y`, "[x$a$ y]"},
+		{"a `$x$` b $y$ c", "[a `$x$` b ]y[ c]"},
+		{`\$a$`, `[\$a$]`},
+		{`$$x$$`, "[$$x$$]"},
+	}
+	for _, tt := range tests {
+		if got := ansi.Strip(renderInlineMath(tt.text, plain)); got != tt.want {
+			t.Errorf("renderInlineMath(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Text of a new meeting note when "meeting_template" is not set in the
+// config file. Besides the snippet placeholders, {{attendees}} becomes the
+// comma-separated attendees and {{agenda}} a numbered list of the agenda.
+const defaultMeetingTemplate = `---
+type: meeting
+date: {{date}} {{time}}
+attendees: [{{attendees}}]
+---
+**Attendees:** {{attendees}}
+
+## Agenda
+
+{{agenda}}
+
+## Notes
+
+$|
+
+## Action items
+
+- [ ] `
+
+// startMeeting asks for the attendees of a new meeting note
+func (m model) startMeeting() (model, tea.Cmd) {
+	return m.openPrompt("meeting-attendees", tr("Attendees (comma-separated)"), "")
+}
+
+// meetingPrompted collects the answers to the meeting prompts. After the
+// attendees it asks for the agenda, and after the agenda it opens the new
+// note in the editor, filled in from the template and titled with the time.
+func (m model) meetingPrompted(kind, answer string) (tea.Model, tea.Cmd) {
+	if kind == "meeting-attendees" {
+		m.attendees = answer
+		return m.openPrompt("meeting-agenda", tr("Agenda (separate items with ;)"), "")
+	}
+
+	var attendees []string
+	for _, a := range strings.Split(m.attendees, ",") {
+		if a = strings.TrimSpace(a); a != "" {
+			attendees = append(attendees, a)
+		}
+	}
+	var agenda []string
+	for _, item := range strings.Split(answer, ";") {
+		if item = strings.TrimSpace(item); item != "" {
+			agenda = append(agenda, fmt.Sprintf("%d. %s", len(agenda)+1, item))
+		}
+	}
+	if len(agenda) == 0 {
+		agenda = []string{"1. "}
+	}
+
+	template := cfg.MeetingTemplate
+	if template == "" {
+		template = defaultMeetingTemplate
+	}
+	body := strings.NewReplacer(
+		"{{attendees}}", strings.Join(attendees, ", "),
+		"{{agenda}}", strings.Join(agenda, "\n"),
+	).Replace(expandSnippetText(template))
+
+	// Open the note as a new one, with the cursor under "Notes"
+	before, after, hasCursor := strings.Cut(body, snippetCursor)
+	if !hasCursor {
+		before, after = body, ""
+	}
+	m.mode = "new"
+	m.selectedNote = nil
+	m.contentFocus = false
+	m.textInput.Reset()
+	m.textInput.SetValue(trf("Meeting %s", localNow().Format("2006-01-02 15:04")))
+	m.textInput.Blur()
+	m.titleEntered = true
+	m.attendees = ""
+	lines := strings.Split(before, "\n")
+	replaceValue(&m.textarea, before+after, len(lines)-1, len([]rune(lines[len(lines)-1])))
+	return m, m.textarea.Focus()
+}
+
+// showTasks lists the open checkboxes of every note, such as the action
+// items of meetings, whether or not the list is filtered to it; choosing one
+// shows it in its note
+func (m model) showTasks() model {
+	notes, err := scanNotes()
+	if err != nil && len(notes) == 0 {
+		m.status = err.Error()
+		return m
+	}
+	var items []pickerItem
+	for _, n := range notes {
+		if n.tasksOpen == 0 {
+			continue
+		}
+		content, err := readNoteFile(n.path)
+		if err != nil {
+			continue
+		}
+		for i, line := range strings.Split(string(content), "\n") {
+			match := listItemPattern.FindStringSubmatch(line)
+			if match == nil || strings.TrimSpace(match[6]) != "[ ]" || strings.TrimSpace(match[7]) == "" {
+				continue
+			}
+			items = append(items, pickerItem{
+				title: strings.TrimSpace(match[7]),
+				desc:  n.title,
+				value: fmt.Sprintf("%d:%s", i, n.path),
+			})
+		}
+	}
+	if len(items) == 0 {
+		m.status = tr("No open tasks")
+		return m
+	}
+	return m.openPicker("tasks", trf("Open tasks (%d)", len(items)), items)
+}
+
+// taskChosen shows the note of the task chosen in the tasks view with the
+// cursor on its line
+func (m model) taskChosen(value string) (tea.Model, tea.Cmd) {
+	number, path, _ := strings.Cut(value, ":")
+	line, err := strconv.Atoi(number)
+	if err != nil {
+		return m, nil
+	}
+	m = m.selectPath(path)
+	if m.selectedNote == nil || m.selectedNote.path != path {
+		m.status = tr("The note is hidden by the current filter")
+		return m, nil
+	}
+	return m.jumpToLine(line), nil
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMeetingNoteFromPrompts(t *testing.T) {
+	old := cfg
+	cfg = config{}
+	t.Cleanup(func() { cfg = old })
+
+	m, _ := initialModel().startMeeting()
+	next, _ := m.meetingPrompted("meeting-attendees", " Ana, , Bo ")
+	next, _ = next.(model).meetingPrompted("meeting-agenda", "Budget; ;Hiring ")
+	m = next.(model)
+
+	if m.mode != "new" || !strings.HasPrefix(m.textInput.Value(), "Meeting ") {
+		t.Fatalf("mode %q titled %q", m.mode, m.textInput.Value())
+	}
+	body := m.textarea.Value()
+	for _, want := range []string{
+		"attendees: [Ana, Bo]",
+		"**Attendees:** Ana, Bo",
+		"1. Budget\n2. Hiring\n",
+		"## Action items\n\n- [ ] ",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("body lacks %q:\n%s", want, body)
+		}
+	}
+	if strings.Contains(body, snippetCursor) {
+		t.Errorf("cursor marker left in the body:\n%s", body)
+	}
+	if row, _ := cursorPos(m.textarea); strings.Split(body, "\n")[row-2] != "## Notes" {
+		t.Errorf("cursor on line %d, not under Notes", row)
+	}
+}
+
+func TestShowTasksListsEveryNote(t *testing.T) {
+	old := notesDir
+	notesDir = t.TempDir()
+	t.Cleanup(func() { notesDir = old })
+	files := map[string]string{
+		"1700000000-Standup.md": "- [ ] Send the notes\n- [x] Book a room\n- [ ]\n",
+		"1700000001-Plan.md":    "* [ ] Draft the plan\n",
+		"1700000002-Done.md":    "- [x] Nothing left\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(notesDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The list is filtered down to nothing, but the tasks view shows all
+	m := initialModel().showTasks()
+	if m.mode != "pick" {
+		t.Fatalf("no tasks listed: %q", m.status)
+	}
+	got := map[string]string{}
+	for _, item := range m.picker.Items() {
+		got[item.(pickerItem).title] = item.(pickerItem).value
+	}
+	want := map[string]string{
+		"Send the notes": "0:" + filepath.Join(notesDir, "1700000000-Standup.md"),
+		"Draft the plan": "0:" + filepath.Join(notesDir, "1700000001-Plan.md"),
+	}
+	if len(got) != len(want) {
+		t.Errorf("got tasks %v, want %v", got, want)
+	}
+	for title, value := range want {
+		if got[title] != value {
+			t.Errorf("task %q is %q, want %q", title, got[title], value)
+		}
+	}
+}
@@ -0,0 +1,352 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Styling of text that came from the copy on disk while merging
+var mergeDiskStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("75"))
+
+// Words, runs of spacing and single other characters: the pieces texts
+// are merged in. Lines are used instead when a text is too long to compare
+// word by word.
+var (
+	mergeWordPattern = regexp.MustCompile(`[\p{L}\p{N}_']+|\s+|.`)
+	mergeLinePattern = regexp.MustCompile(`[^\n]*\n|[^\n]+`)
+)
+
+// mergeChunk is a stretch of a merged text: either text both sides agree
+// on, or a conflict between the editor's text and the copy on disk
+type mergeChunk struct {
+	text       string // The agreed text, when there is no conflict
+	conflict   bool
+	mine, disk string
+	choice     byte // How the conflict is settled: 'm' mine, 'd' disk, 'b' both, 0 not yet
+}
+
+// resolved returns the text a chunk contributes to the merge, and whether
+// it is settled
+func (c mergeChunk) resolved() (string, bool) {
+	switch {
+	case !c.conflict:
+		return c.text, true
+	case c.choice == 'm':
+		return c.mine, true
+	case c.choice == 'd':
+		return c.disk, true
+	case c.choice == 'b':
+		return c.both(), true
+	}
+	return c.both(), false
+}
+
+// both joins the two versions of a conflict, mine first, with a space
+// between when nothing already parts them
+func (c mergeChunk) both() string {
+	if c.mine == "" || c.disk == "" || strings.TrimRight(c.mine, " \t\n") != c.mine || strings.TrimLeft(c.disk, " \t\n") != c.disk {
+		return c.mine + c.disk
+	}
+	return c.mine + " " + c.disk
+}
+
+// diffTokens compares two token lists, skipping their common start and end
+// so only the changed middle is fed to the LCS table. It reports false when
+// the middle is still too large to compare.
+func diffTokens(a, b []string) ([]diffOp, bool) {
+	pre := 0
+	for pre < len(a) && pre < len(b) && a[pre] == b[pre] {
+		pre++
+	}
+	suf := 0
+	for suf < len(a)-pre && suf < len(b)-pre && a[len(a)-1-suf] == b[len(b)-1-suf] {
+		suf++
+	}
+	midA, midB := a[pre:len(a)-suf], b[pre:len(b)-suf]
+	if len(midA)*len(midB) > maxDiffCells {
+		return nil, false
+	}
+
+	var ops []diffOp
+	for _, t := range a[:pre] {
+		ops = append(ops, diffOp{' ', t})
+	}
+	ops = append(ops, diffLines(midA, midB)...)
+	for _, t := range a[len(a)-suf:] {
+		ops = append(ops, diffOp{' ', t})
+	}
+	return ops, true
+}
+
+// tokenMatches maps each token of the base to the token it is kept as in
+// the other text, or -1 where it was changed
+func tokenMatches(ops []diffOp, baseLen int) []int {
+	match := make([]int, baseLen)
+	i, j := 0, 0
+	for _, op := range ops {
+		switch op.kind {
+		case ' ':
+			match[i] = j
+			i++
+			j++
+		case '-':
+			match[i] = -1
+			i++
+		case '+':
+			j++
+		}
+	}
+	return match
+}
+
+// mergeTexts merges the editor's text and the copy on disk, both changed
+// from base. Changes made on only one side are taken as they are, and
+// changes to the same words on both sides become conflicts. Texts are
+// compared word by word, or line by line when too long for that.
+func mergeTexts(base, mine, disk string) []mergeChunk {
+	for _, pattern := range []*regexp.Regexp{mergeWordPattern, mergeLinePattern} {
+		b := pattern.FindAllString(base, -1)
+		m := pattern.FindAllString(mine, -1)
+		d := pattern.FindAllString(disk, -1)
+		mineOps, ok := diffTokens(b, m)
+		if !ok {
+			continue
+		}
+		diskOps, ok := diffTokens(b, d)
+		if !ok {
+			continue
+		}
+		return mergeTokens(b, m, d, tokenMatches(mineOps, len(b)), tokenMatches(diskOps, len(b)))
+	}
+	// Too long to compare at all: the two versions conflict as a whole
+	return []mergeChunk{{conflict: true, mine: mine, disk: disk}}
+}
+
+// mergeTokens walks the base from one token kept on both sides to the
+// next, settling the stretch between them
+func mergeTokens(base, mine, disk []string, inMine, inDisk []int) []mergeChunk {
+	var chunks []mergeChunk
+	add := func(c mergeChunk) {
+		if !c.conflict && c.text == "" {
+			return
+		}
+		last := len(chunks) - 1
+		switch {
+		// Agreed text joins the text before it
+		case !c.conflict && last >= 0 && !chunks[last].conflict:
+			chunks[last].text += c.text
+			return
+		// Conflicts only spacing apart become one
+		case c.conflict && last >= 1 && chunks[last-1].conflict && strings.TrimSpace(chunks[last].text) == "":
+			gap := chunks[last].text
+			chunks[last-1].mine += gap + c.mine
+			chunks[last-1].disk += gap + c.disk
+			chunks = chunks[:last]
+			return
+		}
+		chunks = append(chunks, c)
+	}
+
+	i, m, d := 0, 0, 0
+	for {
+		k := i
+		for k < len(base) && (inMine[k] < 0 || inDisk[k] < 0) {
+			k++
+		}
+		mk, dk := len(mine), len(disk)
+		if k < len(base) {
+			mk, dk = inMine[k], inDisk[k]
+		}
+		b, mt, dt := strings.Join(base[i:k], ""), strings.Join(mine[m:mk], ""), strings.Join(disk[d:dk], "")
+		switch {
+		case mt == b || mt == dt:
+			add(mergeChunk{text: dt})
+		case dt == b:
+			add(mergeChunk{text: mt})
+		default:
+			add(mergeChunk{conflict: true, mine: mt, disk: dt})
+		}
+		if k == len(base) {
+			return chunks
+		}
+		add(mergeChunk{text: base[k]})
+		i, m, d = k+1, mk+1, dk+1
+	}
+}
+
+// mergedText joins the merge, and counts the conflicts left unsettled
+func mergedText(chunks []mergeChunk) (string, int) {
+	var b strings.Builder
+	open := 0
+	for _, c := range chunks {
+		text, ok := c.resolved()
+		if !ok {
+			open++
+		}
+		b.WriteString(text)
+	}
+	return b.String(), open
+}
+
+// conflicts returns the positions of the conflicts among the chunks
+func conflicts(chunks []mergeChunk) []int {
+	var at []int
+	for i, c := range chunks {
+		if c.conflict {
+			at = append(at, i)
+		}
+	}
+	return at
+}
+
+// startMerge opens the merge screen for a note that changed on disk while
+// it was being edited
+func (m model) startMerge(disk string) model {
+	m.merge = mergeTexts(m.editBase, m.editorText(), disk)
+	m.mergeDisk = disk
+	m.mergeCursor = 0
+	m.mode = "merge"
+	return m
+}
+
+// updateMerge settles conflicts and saves the merge
+func (m model) updateMerge(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	at := conflicts(m.merge)
+	choose := func(choice byte) {
+		if len(at) == 0 {
+			return
+		}
+		m.merge[at[m.mergeCursor]].choice = choice
+		// Move on to the next conflict still open
+		for next := m.mergeCursor + 1; next < len(at); next++ {
+			if m.merge[at[next]].choice == 0 {
+				m.mergeCursor = next
+				return
+			}
+		}
+	}
+
+	switch msg.String() {
+	case "esc", "q":
+		m.mode = "edit"
+		m.merge = nil
+		m.status = tr("Nothing saved; the note on disk is unchanged")
+	case "up", "k", "p":
+		m.mergeCursor = max(0, m.mergeCursor-1)
+	case "down", "j", "n":
+		m.mergeCursor = max(0, min(len(at)-1, m.mergeCursor+1))
+	case "m":
+		choose('m')
+	case "d":
+		choose('d')
+	case "b":
+		choose('b')
+	case "M", "D":
+		for _, i := range at {
+			if m.merge[i].choice == 0 {
+				m.merge[i].choice = strings.ToLower(msg.String())[0]
+			}
+		}
+	case "e":
+		// Carry on editing the merge; open conflicts keep both versions
+		text, _ := mergedText(m.merge)
+		m.textarea.SetValue(text)
+		m.editBase = m.mergeDisk
+		m.mode = "edit"
+		m.merge = nil
+		m.status = tr("Merged text in the editor; press ctrl+s to save it")
+	case "enter":
+		text, open := mergedText(m.merge)
+		if open > 0 {
+			m.status = trf("%d conflicts still to settle", open)
+			return m, nil
+		}
+		m.merge = nil
+		return m.saveEdits(text)
+	}
+	return m, nil
+}
+
+// mergeLines renders the merged text with its conflicts marked, and
+// returns the line the cursor's conflict starts on
+func (m model) mergeLines(width int) ([]string, int) {
+	at := conflicts(m.merge)
+	empty := previewDimStyle.Render("∅")
+	side := func(text string, style lipgloss.Style) string {
+		if text == "" {
+			return empty
+		}
+		// Styled line by line so each wrapped line keeps its colour
+		var parts []string
+		for _, line := range strings.Split(text, "\n") {
+			parts = append(parts, style.Render(line))
+		}
+		return strings.Join(parts, "\n")
+	}
+
+	var b strings.Builder
+	cursorLine := 0
+	for i, c := range m.merge {
+		if !c.conflict {
+			b.WriteString(c.text)
+			continue
+		}
+		open, mid, end := "⟦", " ┃ ", "⟧"
+		if len(at) > 0 && at[m.mergeCursor] == i {
+			cursorLine = strings.Count(b.String(), "\n")
+			open, mid, end = replaceCursorStyle.Render(open), replaceCursorStyle.Render(mid), replaceCursorStyle.Render(end)
+		}
+		switch c.choice {
+		case 'm':
+			b.WriteString(side(c.mine, diffAddStyle))
+		case 'd':
+			b.WriteString(side(c.disk, mergeDiskStyle))
+		case 'b':
+			b.WriteString(side(c.mine, diffAddStyle) + side(c.disk, mergeDiskStyle))
+		default:
+			b.WriteString(open + side(c.mine, diffAddStyle) + mid + side(c.disk, mergeDiskStyle) + end)
+		}
+	}
+
+	var lines []string
+	cursor := 0
+	for i, line := range strings.Split(b.String(), "\n") {
+		if i == cursorLine {
+			cursor = len(lines)
+		}
+		lines = append(lines, wrapLines(line, width)...)
+	}
+	return lines, cursor
+}
+
+// mergeSummary says what changed on disk and how much is left to settle
+func (m model) mergeSummary() string {
+	title := m.textInput.Value()
+	at := conflicts(m.merge)
+	if len(at) == 0 {
+		return trf("%s changed on disk while you were editing it; the changes merge cleanly", title)
+	}
+	_, open := mergedText(m.merge)
+	return trf("%s changed on disk while you were editing it: %d of %d conflicts settled", title, len(at)-open, len(at)) +
+		"  " + diffAddStyle.Render(tr("mine")) + " " + mergeDiskStyle.Render(tr("disk"))
+}
+
+// mergeHelp describes the keys of the merge screen
+func mergeHelp() string {
+	return tr("n/p:Next/previous conflict | m/d/b:Keep mine/disk/both | M/D:All mine/disk | e:Edit merge | enter:Save | esc:Back")
+}
+
+// mergeView shows the merge full screen
+func (m model) mergeView() string {
+	width := max(m.width-12, 10)
+	lines, cursor := m.mergeLines(width)
+	body := lipgloss.JoinVertical(lipgloss.Left,
+		m.mergeSummary(), "", strings.Join(linearWindow(lines, cursor, m.height-10), "\n"))
+	return docStyle.Render(lipgloss.JoinVertical(lipgloss.Top,
+		splitStyle.Width(m.width-8).Height(m.height-6).Render(body),
+		helpStyle.Render(mergeHelp()),
+	))
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"os"
+	"testing"
+)
+
+func TestMergeTexts(t *testing.T) {
+	tests := []struct {
+		name             string
+		base, mine, disk string
+		want             string // Merged text, open conflicts keeping both sides
+		open             int
+	}{
+		{"only mine changed", "the quick fox", "the quick brown fox", "the quick fox", "the quick brown fox", 0},
+		{"only disk changed", "the quick fox", "the quick fox", "the slow fox", "the slow fox", 0},
+		{"different words changed", "one two three", "ONE two three", "one two THREE", "ONE two THREE", 0},
+		{"same change on both sides", "one two three", "one 2 three", "one 2 three", "one 2 three", 0},
+		{"same word changed differently", "one two three", "one 2 three", "one deux three", "one 2 deux three", 1},
+		{"line added on each side", "a\nb\n", "a\nmine\nb\n", "a\nb\ndisk\n", "a\nmine\nb\ndisk\n", 0},
+		{"nothing in common", "", "mine", "disk", "mine disk", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, open := mergedText(mergeTexts(tt.base, tt.mine, tt.disk))
+			if got != tt.want || open != tt.open {
+				t.Errorf("mergeTexts(%q, %q, %q) = %q with %d open, want %q with %d", tt.base, tt.mine, tt.disk, got, open, tt.want, tt.open)
+			}
+		})
+	}
+}
+
+func TestMergeTokensJoinsConflictsOnlySpacingApart(t *testing.T) {
+	chunks := mergeTexts("a b", "x y", "p q")
+	at := conflicts(chunks)
+	if len(at) != 1 {
+		t.Fatalf("got %d conflicts, want 1: %+v", len(at), chunks)
+	}
+	if c := chunks[at[0]]; c.mine != "x y" || c.disk != "p q" {
+		t.Errorf("conflict is %q / %q, want %q / %q", c.mine, c.disk, "x y", "p q")
+	}
+}
+
+func TestMergeChunkChoices(t *testing.T) {
+	tests := []struct {
+		choice byte
+		want   string
+		ok     bool
+	}{
+		{'m', "one 2 three", true},
+		{'d', "one deux three", true},
+		{'b', "one 2 deux three", true},
+		{0, "one 2 deux three", false},
+	}
+	for _, tt := range tests {
+		chunks := mergeTexts("one two three", "one 2 three", "one deux three")
+		chunks[conflicts(chunks)[0]].choice = tt.choice
+		got, open := mergedText(chunks)
+		if got != tt.want || (open == 0) != tt.ok {
+			t.Errorf("choice %q: got %q with %d open, want %q", tt.choice, got, open, tt.want)
+		}
+	}
+}
+
+// startMergeDriver saves a note through the editor and opens it again, so
+// another program can change it while it is being edited. It returns the
+// note's path.
+func startMergeDriver(t *testing.T) (*driver, string) {
+	t.Helper()
+	d := startDriver(t)
+	must(t, d, d.press("ctrl+n"))
+	d.typeText("Plan")
+	must(t, d, d.press("tab"))
+	d.typeText("one two three")
+	must(t, d, d.press("ctrl+s"))
+	must(t, d, d.expect("Plan", false))
+	notes, err := scanNotes()
+	if err != nil || len(notes) != 1 {
+		t.Fatalf("saved %d notes, %v", len(notes), err)
+	}
+	must(t, d, d.press("ctrl+e", "tab"))
+	return d, notes[0].path
+}
+
+func TestMergeNoteChangedOnDisk(t *testing.T) {
+	d, path := startMergeDriver(t)
+	must(t, d, d.press("ctrl+home"))
+	d.typeText("zero ")
+
+	// Another program changes the end of the note meanwhile
+	if err := os.WriteFile(path, []byte("one two four"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	must(t, d, d.press("ctrl+s"))
+	must(t, d, d.expect("the changes merge cleanly", false))
+	must(t, d, d.press("enter"))
+	must(t, d, d.expect("the changes merge cleanly", true))
+	if got, _ := os.ReadFile(path); string(got) != "zero one two four" {
+		t.Errorf("merged note is %q, want %q", got, "zero one two four")
+	}
+}
+
+func TestMergeConflictKeepsChoice(t *testing.T) {
+	d, path := startMergeDriver(t)
+	must(t, d, d.press("ctrl+end"))
+	d.typeText(" mine")
+	if err := os.WriteFile(path, []byte("one two three disk"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	must(t, d, d.press("ctrl+s"))
+	must(t, d, d.expect("0 of 1 conflicts settled", false))
+	// Saving waits until every conflict is settled
+	must(t, d, d.press("enter"))
+	must(t, d, d.expect("0 of 1 conflicts settled", false))
+	if got, _ := os.ReadFile(path); string(got) != "one two three disk" {
+		t.Fatalf("note saved with a conflict open: %q", got)
+	}
+	must(t, d, d.press("d", "enter"))
+	must(t, d, d.expect("conflicts settled", true))
+	if got, _ := os.ReadFile(path); string(got) != "one two three disk" {
+		t.Errorf("merged note is %q, want the disk side", got)
+	}
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// showFields lists the frontmatter fields of the selected note in a picker;
+// choosing one edits its value and the last entry adds a new field
+func (m model) showFields() model {
+	if m.selectedNote == nil {
+		return m
+	}
+	content, err := readNoteFile(m.selectedNote.path)
+	if err != nil {
+		m.status = err.Error()
+		return m
+	}
+
+	fields, _ := parseFrontmatter(string(content))
+	var items []pickerItem
+	for _, f := range fields {
+		items = append(items, pickerItem{title: f.key, desc: f.value, value: f.key})
+	}
+	if !readOnly {
+		items = append(items, pickerItem{title: tr("Add field"), desc: tr("key: value")})
+	}
+	if len(items) == 0 {
+		m.status = tr("This note has no fields")
+		return m
+	}
+	return m.openPicker("fields", trf("Fields of %s", m.selectedNote.title), items)
+}
+
+// editField asks for a new value of a field, or for a whole "key: value"
+// line when key is empty
+func (m model) editField(key string) (model, tea.Cmd) {
+	if readOnly {
+		m.status = errReadOnly.Error()
+		return m, nil
+	}
+	m.fieldKey = key
+	if key == "" {
+		return m.openPrompt("field-add", tr("New field (key: value)"), "")
+	}
+	content, err := readNoteFile(m.selectedNote.path)
+	if err != nil {
+		m.status = err.Error()
+		return m, nil
+	}
+	fields, _ := parseFrontmatter(string(content))
+	for _, f := range fields {
+		if f.key == key {
+			return m.openPrompt("field", key, unquote(f.value))
+		}
+	}
+	return m.openPrompt("field", key, "")
+}
+
+// saveField writes a field to the selected note, removing it when the value
+// is empty, and shows the fields again
+func (m model) saveField(kind, answer string) (tea.Model, tea.Cmd) {
+	key, value := m.fieldKey, strings.TrimSpace(answer)
+	if kind == "field-add" {
+		k, v, found := strings.Cut(answer, ":")
+		key, value = strings.TrimSpace(k), strings.TrimSpace(v)
+		if !found || key == "" || strings.ContainsAny(key, " \t") {
+			m.status = tr("Enter the field as key: value")
+			return m, nil
+		}
+	}
+	if m.selectedNote == nil || key == "" {
+		return m, nil
+	}
+	if err := writeField(m.selectedNote.path, key, value); err != nil {
+		m.status = err.Error()
+		return m, nil
+	}
+	m.lastAction = &fieldAction{key: key, value: value}
+	if key == "tags" {
+		action := tagsAction(m.selectedNote.tags, metaList(value))
+		m.lastAction = &action
+	}
+	return m.showFields(), loadNotes
+}
+
+// writeField sets a frontmatter field of the note at path, removing it when
+// the value is empty. Values other than [a, b] lists are quoted when YAML
+// would read them differently.
+func writeField(path, key, value string) error {
+	content, err := readNoteFile(path)
+	if err != nil {
+		return err
+	}
+	if value == "" {
+		return writeNoteFile(path, deleteFrontmatter(string(content), key))
+	}
+	if !strings.HasPrefix(value, "[") || !strings.HasSuffix(value, "]") {
+		value = yamlString(value)
+	}
+	return writeNoteFile(path, setFrontmatter(string(content), key, value))
+}
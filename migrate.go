@@ -0,0 +1,121 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Frontmatter fields the migration writes: the note's id, which is its
+// creation time, its exact title, and the creation time as a date
+var migratedFields = []string{"id", "title", "created"}
+
+// Frontmatter field listing the fields the migration added to a note, so
+// undoing it leaves fields the note already had
+const migratedMarker = "migrated"
+
+// migrateNote adds the id, title and created fields of the filename scheme
+// to a note's frontmatter, and records which it added. It reports false
+// when there is nothing to add.
+func migrateNote(n note, content string) (string, bool, error) {
+	fields, _ := parseFrontmatter(content)
+	if id := metaValue(fields, "id"); id != "" && id != strconv.FormatInt(n.createdAt, 10) {
+		return "", false, fmt.Errorf("%s: frontmatter id %s does not match the file name", filepath.Base(n.path), id)
+	}
+	if metaValue(fields, "id") != "" {
+		return content, false, nil
+	}
+	added := []string{"id"}
+	content = setFrontmatter(content, "id", strconv.FormatInt(n.createdAt, 10))
+	if metaValue(fields, "title") == "" {
+		content = setFrontmatter(content, "title", yamlString(n.title))
+		added = append(added, "title")
+	}
+	if metaValue(fields, "created") == "" {
+		content = setFrontmatter(content, "created", noteTime(n.createdAt).Format(time.RFC3339))
+		added = append(added, "created")
+	}
+	content = setFrontmatter(content, migratedMarker, "["+strings.Join(added, ", ")+"]")
+	return content, true, nil
+}
+
+// unmigrateNote removes the fields migrateNote recorded adding, leaving
+// notes it did not migrate and notes whose id is not their own untouched.
+// It reports false when there is nothing to remove.
+func unmigrateNote(n note, content string) (string, bool) {
+	fields, _ := parseFrontmatter(content)
+	added := metaList(metaValue(fields, migratedMarker))
+	if len(added) == 0 || metaValue(fields, "id") != strconv.FormatInt(n.createdAt, 10) {
+		return content, false
+	}
+	for _, key := range added {
+		if slices.Contains(migratedFields, key) {
+			content = deleteFrontmatter(content, key)
+		}
+	}
+	return deleteFrontmatter(content, migratedMarker), true
+}
+
+// runMigrate implements `gleaner migrate [--back] [--dry-run]`, moving notes
+// from the filename-only scheme, where a note's id and title live only in
+// its `<unixtime>-title` file name, to keeping them in its frontmatter too,
+// or with --back removing them again
+func runMigrate(args []string) error {
+	flags := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	back := flags.Bool("back", false, "remove the fields the migration added again")
+	dryRun := flags.Bool("dry-run", false, "only list the notes that would change")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() > 0 {
+		return errors.New("usage: gleaner migrate [--back] [--dry-run]")
+	}
+	if readOnly && !*dryRun {
+		return errReadOnly
+	}
+
+	notes, err := notesForCommand()
+	if err != nil {
+		return err
+	}
+	changed := 0
+	for _, n := range notes {
+		data, err := readNoteFile(n.path)
+		if err != nil {
+			return err
+		}
+		content, ok := string(data), false
+		if *back {
+			content, ok = unmigrateNote(n, content)
+		} else if content, ok, err = migrateNote(n, content); err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+
+		changed++
+		if *dryRun {
+			fmt.Println(n.path)
+			continue
+		}
+		if err := writeNoteFile(n.path, content); err != nil {
+			return err
+		}
+	}
+
+	switch {
+	case *dryRun:
+		fmt.Printf("%d of %d notes would change\n", changed, len(notes))
+	case *back:
+		fmt.Printf("Removed the fields the migration added from %d notes\n", changed)
+	default:
+		fmt.Printf("Added id, title and created fields to %d notes\n", changed)
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMigrateNote(t *testing.T) {
+	oldZone := displayZone
+	t.Cleanup(func() { displayZone = oldZone })
+	displayZone = time.UTC
+	n := note{title: "Plan: Q1", createdAt: 1700000000, path: "/vault/1700000000-Plan-Q1.md"}
+	tests := []struct {
+		name, content, want string
+		changed             bool
+		wantErr             bool
+	}{
+		{
+			"no frontmatter",
+			"body\n",
+			"---\nid: 1700000000\ntitle: \"Plan: Q1\"\ncreated: 2023-11-14T22:13:20Z\nmigrated: [id, title, created]\n---\nbody\n",
+			true, false,
+		},
+		{
+			"title kept",
+			"---\ntitle: Other\ntags: [a]\n---\nbody\n",
+			"---\ntitle: Other\ntags: [a]\nid: 1700000000\ncreated: 2023-11-14T22:13:20Z\nmigrated: [id, created]\n---\nbody\n",
+			true, false,
+		},
+		{"already migrated", "---\nid: 1700000000\n---\nx\n", "---\nid: 1700000000\n---\nx\n", false, false},
+		{"id of another note", "---\nid: 1600000000\n---\nx\n", "", false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, changed, err := migrateNote(n, tt.content)
+			if got != tt.want || changed != tt.changed || (err != nil) != tt.wantErr {
+				t.Fatalf("migrateNote = %q, %v, %v; want %q, %v", got, changed, err, tt.want, tt.changed)
+			}
+			if !changed {
+				return
+			}
+			// Undoing the migration gives back the note as it was
+			if back, ok := unmigrateNote(n, got); back != tt.content || !ok {
+				t.Errorf("unmigrateNote = %q, %v; want %q", back, ok, tt.content)
+			}
+		})
+	}
+
+	other := note{title: "Plan: Q1", createdAt: 1600000000, path: "/vault/1600000000-Plan-Q1.md"}
+	migrated, _, _ := migrateNote(n, "body\n")
+	if got, ok := unmigrateNote(other, migrated); ok || got != migrated {
+		t.Errorf("unmigrated a note whose id is not its own: %q", got)
+	}
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"sort"
+	"time"
+)
+
+// onThisDay returns the notes created on today's day of the month in
+// earlier months and years, most recent first
+func onThisDay(notes []note, now time.Time) []note {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	var found []note
+	for _, n := range notes {
+		created := noteTime(n.createdAt)
+		if created.Day() == now.Day() && created.Before(today) {
+			found = append(found, n)
+		}
+	}
+	sort.Slice(found, func(i, j int) bool { return found[i].createdAt > found[j].createdAt })
+	return found
+}
+
+// agoText describes how long before now a note was written: in years when
+// it is a whole number of them, and in months otherwise
+func agoText(created, now time.Time) string {
+	months := (now.Year()-created.Year())*12 + int(now.Month()-created.Month())
+	switch {
+	case months == 12:
+		return tr("1 year ago")
+	case months%12 == 0:
+		return trf("%d years ago", months/12)
+	case months == 1:
+		return tr("1 month ago")
+	default:
+		return trf("%d months ago", months)
+	}
+}
+
+// openOnThisDay lists the notes written on this day in the past; choosing
+// one selects it
+func (m model) openOnThisDay() model {
+	now := localNow()
+	notes := onThisDay(m.notes, now)
+	if len(notes) == 0 {
+		m.status = tr("Nothing was written on this day before")
+		return m
+	}
+	items := make([]pickerItem, len(notes))
+	for i, n := range notes {
+		items[i] = pickerItem{title: n.title, desc: agoText(noteTime(n.createdAt), now), value: n.path}
+	}
+	return m.openPicker("on-this-day", trf("On this day, %s %d", tr(now.Month().String()), now.Day()), items)
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/atotto/clipboard"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Scheme of the links other tools use to open a note
+const noteURIScheme = "gleaner"
+
+// errNoNote is returned when no note matches a title or id
+var errNoNote = errors.New("no note")
+
+// noteURI returns a link that opens the note in gleaner. Notes are
+// identified by their creation timestamp, which survives renames.
+func noteURI(n note) string {
+	uri := fmt.Sprintf("%s://note/%d", noteURIScheme, n.createdAt)
+	if activeVault != "" {
+		uri += "?vault=" + url.QueryEscape(activeVault)
+	}
+	return uri
+}
+
+// copyNoteLink puts a link to the selected note on the clipboard
+func (m model) copyNoteLink() model {
+	uri := noteURI(*m.selectedNote)
+	if err := clipboard.WriteAll(uri); err != nil {
+		m.status = trf("Copy failed: %v", err)
+	} else {
+		m.status = trf("Copied %s", uri)
+	}
+	return m
+}
+
+// parseNoteURI extracts the note id and optional vault from a gleaner:// link
+func parseNoteURI(uri string) (id, vault string, err error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return "", "", err
+	}
+	if u.Scheme != noteURIScheme || u.Host != "note" {
+		return "", "", fmt.Errorf("unsupported link %q (expected %s://note/<id>)", uri, noteURIScheme)
+	}
+	id = strings.Trim(u.Path, "/")
+	if id == "" {
+		return "", "", fmt.Errorf("link %q names no note", uri)
+	}
+	return id, u.Query().Get("vault"), nil
+}
+
+// findNote looks a note up by id or by title, ignoring case. A note's
+// aliases are tried when no title matches.
+func findNote(notes []note, query string) (note, error) {
+	if id, err := strconv.ParseInt(query, 10, 64); err == nil {
+		for _, n := range notes {
+			if n.createdAt == id {
+				return n, nil
+			}
+		}
+	}
+
+	var matches []note
+	for _, n := range notes {
+		if strings.EqualFold(n.title, query) || strings.EqualFold(n.title, strings.ReplaceAll(sanitizeFileName(query), "-", " ")) {
+			matches = append(matches, n)
+		}
+	}
+	if len(matches) == 0 {
+		for _, n := range notes {
+			if slices.ContainsFunc(n.aliases, func(alias string) bool { return strings.EqualFold(alias, query) }) {
+				matches = append(matches, n)
+			}
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return note{}, fmt.Errorf("%w matching %q", errNoNote, query)
+	case 1:
+		return matches[0], nil
+	default:
+		var ids []string
+		for _, n := range matches {
+			ids = append(ids, strconv.FormatInt(n.createdAt, 10))
+		}
+		return note{}, fmt.Errorf("%q matches several notes, use an id: %s", query, strings.Join(ids, ", "))
+	}
+}
+
+// runOpen implements `gleaner open <title|id|link>`, starting the TUI with
+// the note selected
+func runOpen(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: gleaner open <title|id|%s://note/<id>>", noteURIScheme)
+	}
+
+	query := args[0]
+	if strings.HasPrefix(query, noteURIScheme+"://") {
+		id, vault, err := parseNoteURI(query)
+		if err != nil {
+			return err
+		}
+		if vault != "" {
+			if err := useVault(vault); err != nil {
+				return err
+			}
+		}
+		query = id
+	}
+
+	notes, err := notesForCommand()
+	if err != nil {
+		return err
+	}
+	n, err := findNote(notes, query)
+	if err != nil {
+		return err
+	}
+
+	m := initialModel()
+	m.selectedNote = &n
+	return runTUI(m)
+}
+
+// startSearch filters the notes list by m.search as if it had been typed
+func (m model) startSearch() (model, tea.Cmd) {
+	if len(m.list.Items()) == 0 {
+		m.search = ""
+		return m, nil
+	}
+
+	var cmds []tea.Cmd
+	for _, key := range []tea.KeyMsg{
+		{Type: tea.KeyRunes, Runes: []rune{'/'}},
+		{Type: tea.KeyRunes, Runes: []rune(m.search)},
+		{Type: tea.KeyEnter},
+	} {
+		var cmd tea.Cmd
+		m.list, cmd = m.list.Update(key)
+		cmds = append(cmds, cmd)
+	}
+	return m, tea.Batch(cmds...)
+}
+
+// selectPath selects the note at path when the list shows it
+func (m model) selectPath(path string) model {
+	for i, item := range m.list.VisibleItems() {
+		if n := item.(note); n.path == path {
+			m.list.Select(i)
+			m.selectedNote = &n
+			m.textarea.SetValue(noteText(n.path))
+			return m
+		}
+	}
+	return m
+}
+
+// selectVisible keeps the selected note when the filtered list still shows
+// it, and otherwise shows the first note in the list
+func (m model) selectVisible() model {
+	for i, item := range m.list.VisibleItems() {
+		if n := item.(note); m.selectedNote != nil && n.path == m.selectedNote.path {
+			m.list.Select(i)
+			return m
+		}
+	}
+
+	m.list.Select(0)
+	if selected, ok := m.list.SelectedItem().(note); ok {
+		m.selectedNote = &selected
+		m.textarea.SetValue(noteText(selected.path))
+	}
+	return m
+}
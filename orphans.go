@@ -0,0 +1,89 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"sort"
+)
+
+// findOrphans returns the notes with no tags that neither link to another
+// note nor are linked from one, oldest first
+func findOrphans(notes []note) []note {
+	linked := make(map[string]bool)
+	for _, n := range notes {
+		links := noteLinks(notes, n)
+		if len(links) > 0 {
+			linked[n.path] = true
+		}
+		for _, path := range links {
+			linked[path] = true
+		}
+	}
+
+	var orphans []note
+	for _, n := range notes {
+		if !linked[n.path] && len(n.tags) == 0 {
+			orphans = append(orphans, n)
+		}
+	}
+	sort.Slice(orphans, func(i, j int) bool { return orphans[i].createdAt < orphans[j].createdAt })
+	return orphans
+}
+
+// openOrphans lists the notes not connected to the rest of the vault;
+// choosing one selects it. Links are counted across every note, not only
+// those the list is filtered to.
+func (m model) openOrphans() model {
+	notes, err := scanNotes()
+	if err != nil && len(notes) == 0 {
+		m.status = err.Error()
+		return m
+	}
+	orphans := findOrphans(notes)
+	if len(orphans) == 0 {
+		m.status = tr("Every note is linked or tagged")
+		return m
+	}
+	items := make([]pickerItem, len(orphans))
+	for i, n := range orphans {
+		items[i] = pickerItem{
+			title: n.title,
+			desc:  formatTime(noteTime(n.createdAt)) + " · " + trf("%d words", wordCount(n.body)),
+			value: n.path,
+		}
+	}
+	return m.openPicker("orphans", trf("%d orphaned notes", len(orphans)), items)
+}
+
+// orphanChosen selects the chosen note, when the list shows it
+func (m model) orphanChosen(path string) model {
+	m = m.selectPath(path)
+	if m.selectedNote == nil || m.selectedNote.path != path {
+		m.status = tr("The note is hidden by the current filter")
+	}
+	return m
+}
+
+// runOrphans implements `gleaner orphans`, printing the notes with no links
+// in or out and no tags
+func runOrphans(args []string) error {
+	flags := flag.NewFlagSet("orphans", flag.ContinueOnError)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() > 0 {
+		return errors.New("usage: gleaner orphans")
+	}
+
+	notes, err := notesForCommand()
+	if err != nil {
+		return err
+	}
+	orphans := findOrphans(notes)
+	for _, n := range orphans {
+		fmt.Printf("%s  %6d words  %s\n", noteTime(n.createdAt).Format("2006-01-02"), wordCount(n.body), n.path)
+	}
+	fmt.Printf("%d orphaned notes of %d\n", len(orphans), len(notes))
+	return nil
+}
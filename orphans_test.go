@@ -0,0 +1,39 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestFindOrphans(t *testing.T) {
+	oldDir, oldCfg := notesDir, cfg
+	t.Cleanup(func() { notesDir, cfg = oldDir, oldCfg })
+	notesDir, cfg = t.TempDir(), config{}
+	for name, content := range map[string]string{
+		"1700000003-Lonely.md":  "nobody links here\n",
+		"1700000001-Alone.md":   "nor here\n",
+		"1700000002-Hub.md":     "see [[Linked]]\n",
+		"1700000004-Linked.md":  "linked from Hub\n",
+		"1700000005-Tagged.md":  "---\ntags: [idea]\n---\nkept by its tag\n",
+		"1700000006-Missing.md": "[[No such note]]\n",
+	} {
+		if err := os.WriteFile(filepath.Join(notesDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	notes, err := scanNotes()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var titles []string
+	for _, n := range findOrphans(notes) {
+		titles = append(titles, n.title)
+	}
+	// A link to a note that does not exist connects nothing
+	if want := []string{"Alone", "Lonely", "Missing"}; !slices.Equal(titles, want) {
+		t.Errorf("orphans %q, want %q", titles, want)
+	}
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+)
+
+// heading is a markdown heading found in a note
+type heading struct {
+	level int    // 1 for "#", 2 for "##", ...
+	text  string // Heading text without the markers
+	line  int    // Zero-based line number in the note
+}
+
+// parseHeadings lists the ATX headings of a document, ignoring code blocks
+func parseHeadings(content string) []heading {
+	var headings []heading
+	inCode := false
+	for i, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") {
+			inCode = !inCode
+			continue
+		}
+		if inCode || !strings.HasPrefix(line, "#") {
+			continue
+		}
+		level := len(line) - len(strings.TrimLeft(line, "#"))
+		text := strings.TrimSpace(strings.TrimRight(line[level:], "# "))
+		if level > 6 || text == "" || (len(line) > level && line[level] != ' ') {
+			continue
+		}
+		headings = append(headings, heading{level: level, text: text, line: i})
+	}
+	return headings
+}
+
+// openOutline shows the headings of the current note for quick navigation
+func (m model) openOutline() model {
+	headings := parseHeadings(m.textarea.Value())
+	if len(headings) == 0 {
+		m.status = tr("No headings in this note")
+		return m
+	}
+
+	items := make([]pickerItem, len(headings))
+	for i, h := range headings {
+		items[i] = pickerItem{
+			title: strings.Repeat("  ", h.level-1) + h.text,
+			desc:  trf("line %d", h.line+1),
+			value: strconv.Itoa(h.line),
+		}
+	}
+	return m.openPicker("outline", tr("Outline"), items)
+}
+
+// jumpToLine moves the editor (or the preview while browsing) to a line
+func (m model) jumpToLine(line int) model {
+	wasFocused := m.textarea.Focused()
+	if m.mode == "list" && !m.hideContent {
+		m = m.focusContent(true)
+		wasFocused = m.textarea.Focused()
+	}
+	m.textarea.Focus()
+	moveCursor(&m.textarea, line, 0)
+	m.textarea, _ = m.textarea.Update(nil) // Scroll the line into view
+	if !wasFocused {
+		m.textarea.Blur()
+	}
+	return m
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/atotto/clipboard"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Built-in paste services, selected by name in the config
+const (
+	pasteNullPointer = "0x0.st"
+	pastePasteRs     = "paste.rs"
+)
+
+// pasteMsg reports the outcome of uploading a note to a paste service
+type pasteMsg struct {
+	url    string
+	copied bool // URL was also placed on the clipboard
+	err    error
+}
+
+// pasteService returns the configured paste service, defaulting to 0x0.st
+func pasteService() string {
+	if cfg.PasteService == "" {
+		return pasteNullPointer
+	}
+	return cfg.PasteService
+}
+
+// pasteRequest builds the upload request for a paste service. 0x0.st takes a
+// multipart form; paste.rs and custom endpoints take the raw text.
+func pasteRequest(service, content string) (*http.Request, error) {
+	switch service {
+	case pasteNullPointer:
+		var body bytes.Buffer
+		form := multipart.NewWriter(&body)
+		part, err := form.CreateFormFile("file", "note.md")
+		if err != nil {
+			return nil, err
+		}
+		io.WriteString(part, content)
+		if err := form.Close(); err != nil {
+			return nil, err
+		}
+		req, err := http.NewRequest(http.MethodPost, "https://0x0.st", &body)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", form.FormDataContentType())
+		return req, nil
+
+	case pastePasteRs:
+		service = "https://paste.rs"
+	}
+
+	if !strings.HasPrefix(service, "http://") && !strings.HasPrefix(service, "https://") {
+		return nil, fmt.Errorf("unknown paste service %q", service)
+	}
+	req, err := http.NewRequest(http.MethodPost, service, strings.NewReader(content))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	return req, nil
+}
+
+// sharePaste uploads a note to the paste service and copies the URL it
+// answers with to the clipboard
+func sharePaste(n note) tea.Cmd {
+	return func() tea.Msg {
+		data, err := readNoteFile(n.path)
+		if err != nil {
+			return pasteMsg{err: err}
+		}
+		_, body := parseFrontmatter(string(data))
+
+		req, err := pasteRequest(pasteService(), body)
+		if err != nil {
+			return pasteMsg{err: err}
+		}
+		req.Header.Set("User-Agent", "gleaner")
+
+		client := &http.Client{Timeout: 30 * time.Second}
+		resp, err := client.Do(req)
+		if err != nil {
+			return pasteMsg{err: err}
+		}
+		defer resp.Body.Close()
+
+		reply, err := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		if err != nil {
+			return pasteMsg{err: err}
+		}
+		url := strings.TrimSpace(string(reply))
+		if resp.StatusCode >= 300 {
+			return pasteMsg{err: fmt.Errorf("%s returned %s: %s", pasteService(), resp.Status, url)}
+		}
+		return pasteMsg{url: url, copied: clipboard.WriteAll(url) == nil}
+	}
+}
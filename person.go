@@ -0,0 +1,142 @@
+package main
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Note type, set with "type: person" in the frontmatter, for contacts. The
+// note's title is the person's name, and mentions of it in other notes are
+// shown as links.
+const noteTypePerson = "person"
+
+// Fields the person form offers, in order, with their labels
+var personFields = []struct{ key, label string }{
+	{"email", "Email"},
+	{"phone", "Phone"},
+	{"company", "Company"},
+	{"birthday", "Birthday"},
+}
+
+// Matches the names of the person notes in the vault, nil when there are none
+var mentionPattern *regexp.Regexp
+
+// setPeople rebuilds mentionPattern from the person notes among notes.
+// Longer names come first so that "Ada Lovelace" wins over "Ada".
+func setPeople(notes []note) {
+	var names []string
+	for _, n := range notes {
+		if n.kind == noteTypePerson && len([]rune(n.title)) > 1 {
+			names = append(names, regexp.QuoteMeta(n.title))
+		}
+	}
+	if len(names) == 0 {
+		mentionPattern = nil
+		return
+	}
+	slices.SortFunc(names, func(a, b string) int { return len(b) - len(a) })
+	mentionPattern = regexp.MustCompile(`\b(` + strings.Join(names, "|") + `)\b`)
+}
+
+// mentionsOf returns the other notes whose body mentions a person by name
+func mentionsOf(notes []note, person note) []note {
+	pattern := regexp.MustCompile(`\b` + regexp.QuoteMeta(person.title) + `\b`)
+	var found []note
+	for _, n := range notes {
+		if n.path != person.path && pattern.MatchString(n.body) {
+			found = append(found, n)
+		}
+	}
+	return found
+}
+
+// showPerson opens the form of the selected person note: a picker of its
+// fields, where choosing one edits it, followed by the notes mentioning them
+func (m model) showPerson() model {
+	content, err := readNoteFile(m.selectedNote.path)
+	if err != nil {
+		m.status = err.Error()
+		return m
+	}
+	fields, _ := parseFrontmatter(string(content))
+
+	var items []pickerItem
+	for _, f := range personFields {
+		value := metaValue(fields, f.key)
+		if value == "" {
+			value = tr("not set")
+		}
+		items = append(items, pickerItem{title: tr(f.label), desc: value, value: "field:" + f.key})
+	}
+	for _, n := range mentionsOf(m.notes, *m.selectedNote) {
+		items = append(items, pickerItem{title: n.title, desc: tr("Mentioned here"), value: "note:" + n.path})
+	}
+	return m.openPicker("person", m.selectedNote.title, items)
+}
+
+// personChosen edits the field chosen in the person form, or shows the
+// chosen note mentioning the person
+func (m model) personChosen(value string) (tea.Model, tea.Cmd) {
+	if path, ok := strings.CutPrefix(value, "note:"); ok {
+		return m.selectPath(path), nil
+	}
+	if readOnly {
+		m.status = errReadOnly.Error()
+		return m, nil
+	}
+
+	key := strings.TrimPrefix(value, "field:")
+	content, err := readNoteFile(m.selectedNote.path)
+	if err != nil {
+		m.status = err.Error()
+		return m, nil
+	}
+	fields, _ := parseFrontmatter(string(content))
+	m.fieldKey = key
+	for _, f := range personFields {
+		if f.key == key {
+			return m.openPrompt("person-field", tr(f.label), metaValue(fields, key))
+		}
+	}
+	return m, nil
+}
+
+// savePersonField checks and writes the field edited in the person form,
+// then shows the form again
+func (m model) savePersonField(answer string) (tea.Model, tea.Cmd) {
+	value := strings.TrimSpace(answer)
+	if m.selectedNote == nil {
+		return m, nil
+	}
+	if value != "" {
+		switch m.fieldKey {
+		case "email":
+			if !strings.Contains(value, "@") {
+				m.status = tr("Enter an email address like name@example.com")
+				return m, nil
+			}
+		case "birthday":
+			if _, err := parseBirthday(value); err != nil {
+				m.status = tr("Enter the birthday as YYYY-MM-DD or MM-DD")
+				return m, nil
+			}
+		}
+	}
+	if err := writeField(m.selectedNote.path, m.fieldKey, value); err != nil {
+		m.status = err.Error()
+		return m, nil
+	}
+	return m.showPerson(), loadNotes
+}
+
+// parseBirthday reads a birthday with or without the year
+func parseBirthday(value string) (time.Time, error) {
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, nil
+	}
+	return time.Parse("01-02", value)
+}
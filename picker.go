@@ -0,0 +1,131 @@
+package main
+
+import (
+	"path/filepath"
+	"strconv"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// pickerItem is a single choice in a popup picker
+type pickerItem struct {
+	title string // Text shown for the choice
+	desc  string // Secondary line under the title
+	value string // Value handed back when the choice is selected
+}
+
+// Implement list.Item interface methods for picker entries
+func (i pickerItem) Title() string       { return i.title }
+func (i pickerItem) Description() string { return i.desc }
+func (i pickerItem) FilterValue() string { return i.title }
+
+// openPicker shows a popup list of choices; kind tells updatePicker what to do
+// with the selection
+func (m model) openPicker(kind, title string, items []pickerItem) model {
+	listItems := make([]list.Item, len(items))
+	for i, item := range items {
+		listItems[i] = item
+	}
+
+	delegate := themeDelegate(list.NewDefaultDelegate())
+	m.picker = list.New(listItems, delegate, 0, 0)
+	m.picker.Title = title
+	m.picker.SetShowStatusBar(false)
+	m.picker.SetShowHelp(false)
+	m.picker.SetSize(m.width-8, m.height-10)
+
+	m.pickerKind = kind
+	m.pickerReturn = m.mode
+	m.mode = "pick"
+	return m
+}
+
+// closePicker returns to the mode that was active before the picker opened
+func (m model) closePicker() model {
+	m.mode = m.pickerReturn
+	m.pickerKind = ""
+	return m
+}
+
+// updatePicker handles keys while a picker is open
+func (m model) updatePicker(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.picker.SettingFilter() {
+		var cmd tea.Cmd
+		m.picker, cmd = m.picker.Update(msg)
+		return m, cmd
+	}
+
+	switch msg.Type {
+	case tea.KeyEsc:
+		return m.closePicker(), nil
+
+	case tea.KeyEnter:
+		selected, ok := m.picker.SelectedItem().(pickerItem)
+		kind := m.pickerKind
+		m = m.closePicker()
+		if !ok {
+			return m, nil
+		}
+		return m.pickerSelected(kind, selected.value)
+	}
+
+	var cmd tea.Cmd
+	m.picker, cmd = m.picker.Update(msg)
+	return m, cmd
+}
+
+// pickerSelected acts on the choice made in a picker of the given kind
+func (m model) pickerSelected(kind, value string) (tea.Model, tea.Cmd) {
+	switch kind {
+	case "vault":
+		return m.switchVault(value)
+	case "outline":
+		line, _ := strconv.Atoi(value)
+		return m.jumpToLine(line), nil
+	case "dates":
+		return m.applyDateChoice(value)
+	case "label":
+		return m.setLabel(value)
+	case "label-filter":
+		return m.applyLabelFilter(value)
+	case "fields":
+		return m.editField(value)
+	case "person":
+		return m.personChosen(value)
+	case "tasks":
+		return m.taskChosen(value)
+	case "on-this-day":
+		return m.selectPath(value), nil
+	case "audit":
+		return m.auditChosen(value)
+	case "trash":
+		return m.trashChosen(value)
+	case "duplicates":
+		return m.duplicateChosen(value), nil
+	case "duplicate-action":
+		return m.duplicateAction(value)
+	case "attachments":
+		return m.attachmentChosen(value)
+	case "orphans":
+		return m.orphanChosen(value), nil
+	case "broken-links":
+		return m.brokenLinkChosen(value), nil
+	case "broken-link-action":
+		return m.brokenLinkAction(value)
+	case "macro":
+		return m.playMacro(value)
+	case "template":
+		return m.templateChosen(value)
+	case "rename":
+		return m.renameChosen(value)
+	case "tag-scope":
+		return m.tagScopeChosen(value)
+	case "tag-confirm":
+		return m.tagEditConfirmed(value)
+	case "plugin":
+		m.status = trf("Running plugin %s...", filepath.Base(value))
+		return m, runPlugin(value, m.selectedNote)
+	}
+	return m, nil
+}
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Maximum time a plugin may run before it is killed
+const pluginTimeout = time.Minute
+
+// pluginRequest is the JSON document a plugin receives on stdin
+type pluginRequest struct {
+	Action    string      `json:"action"`
+	Vault     string      `json:"vault"`
+	VaultName string      `json:"vault_name,omitempty"`
+	Note      *pluginNote `json:"note,omitempty"`
+}
+
+// pluginNote describes a note exchanged with a plugin
+type pluginNote struct {
+	Title     string `json:"title"`
+	Path      string `json:"path,omitempty"`
+	CreatedAt int64  `json:"created_at,omitempty"`
+	Content   string `json:"content"`
+}
+
+// pluginResponse is the JSON document a plugin may print on stdout
+type pluginResponse struct {
+	Content *string      `json:"content,omitempty"` // Replacement content for the selected note
+	Create  []pluginNote `json:"create,omitempty"`  // New notes to add to the vault
+	Status  string       `json:"status,omitempty"`  // Message shown in the status line
+}
+
+// pluginMsg reports the outcome of a plugin run
+type pluginMsg struct {
+	name   string
+	status string
+	err    error
+}
+
+// pluginDir returns the directory plugins are discovered in
+func pluginDir() string {
+	return filepath.Join(configDir(), "plugins")
+}
+
+// listPlugins returns the executables in the plugin directory
+func listPlugins() []string {
+	entries, err := os.ReadDir(pluginDir())
+	if err != nil {
+		return nil
+	}
+
+	var plugins []string
+	for _, e := range entries {
+		info, err := e.Info()
+		if err != nil || info.IsDir() || info.Mode().Perm()&0111 == 0 {
+			continue
+		}
+		plugins = append(plugins, filepath.Join(pluginDir(), e.Name()))
+	}
+	sort.Strings(plugins)
+	return plugins
+}
+
+// openPluginPicker lists installed plugins to run against the selected note
+func (m model) openPluginPicker() model {
+	var items []pickerItem
+	for _, path := range listPlugins() {
+		items = append(items, pickerItem{
+			title: filepath.Base(path),
+			desc:  path,
+			value: path,
+		})
+	}
+	if len(items) == 0 {
+		m.status = trf("No plugins found in %s", pluginDir())
+		return m
+	}
+	return m.openPicker("plugin", tr("Plugins"), items)
+}
+
+// runPlugin executes a plugin against the selected note and applies its response
+func runPlugin(path string, selected *note) tea.Cmd {
+	return func() tea.Msg {
+		name := filepath.Base(path)
+		req := pluginRequest{Action: "run", Vault: notesDir, VaultName: activeVault}
+		if selected != nil {
+			content, err := readNoteFile(selected.path)
+			if err != nil {
+				return pluginMsg{name: name, err: err}
+			}
+			req.Note = &pluginNote{
+				Title:     selected.title,
+				Path:      selected.path,
+				CreatedAt: selected.createdAt,
+				Content:   string(content),
+			}
+		}
+
+		resp, err := callPlugin(path, req)
+		if err != nil {
+			return pluginMsg{name: name, err: err}
+		}
+		if err := applyPluginResponse(resp, req.Note); err != nil {
+			return pluginMsg{name: name, err: err}
+		}
+		return pluginMsg{name: name, status: resp.Status}
+	}
+}
+
+// callPlugin sends the request to the plugin and decodes its reply
+func callPlugin(path string, req pluginRequest) (pluginResponse, error) {
+	var resp pluginResponse
+	input, err := json.Marshal(req)
+	if err != nil {
+		return resp, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), pluginTimeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, path)
+	cmd.Dir = notesDir
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if detail := strings.TrimSpace(stderr.String()); detail != "" {
+			return resp, fmt.Errorf("%v: %s", err, detail)
+		}
+		return resp, err
+	}
+
+	if len(bytes.TrimSpace(stdout.Bytes())) == 0 {
+		return resp, nil
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &resp); err != nil {
+		return resp, fmt.Errorf("invalid plugin response: %w", err)
+	}
+	return resp, nil
+}
+
+// applyPluginResponse writes new content and notes returned by a plugin.
+// They are saved as notes edited by hand are, through the save hooks and
+// into the change log.
+func applyPluginResponse(resp pluginResponse, selected *pluginNote) error {
+	if resp.Content == nil && len(resp.Create) == 0 {
+		return nil
+	}
+	if readOnly {
+		return errReadOnly
+	}
+
+	if resp.Content != nil {
+		if selected == nil {
+			return fmt.Errorf("plugin returned content but no note is selected")
+		}
+		if err := writeNoteFile(selected.Path, *resp.Content); err != nil {
+			return err
+		}
+	}
+
+	for _, n := range resp.Create {
+		if strings.TrimSpace(n.Title) == "" {
+			return fmt.Errorf("plugin tried to create a note without a title")
+		}
+		if err := writeNoteFile(newNotePath(n.Title), n.Content); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// newNotePath names a new note in the notes directory after the current
+// time, moving on a second at a time past ids already taken, so notes
+// created together do not overwrite each other
+func newNotePath(title string) string {
+	id := time.Now().Unix()
+	for {
+		if taken, _ := filepath.Glob(filepath.Join(notesDir, fmt.Sprintf("%d-*", id))); len(taken) == 0 {
+			return filepath.Join(notesDir, fmt.Sprintf("%d-%s%s", id, sanitizeFileName(title), newNoteExt()))
+		}
+		id++
+	}
+}
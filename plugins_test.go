@@ -0,0 +1,140 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// installPlugin writes a shell script into the plugin folder
+func installPlugin(t *testing.T, name, script string) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("plugins in the tests are shell scripts")
+	}
+	if err := os.MkdirAll(pluginDir(), 0755); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(pluginDir(), name)
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestRunPlugin(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	oldDir, oldCfg := notesDir, cfg
+	t.Cleanup(func() { notesDir, cfg = oldDir, oldCfg })
+	notesDir = t.TempDir()
+	hookLog := filepath.Join(t.TempDir(), "hooks")
+	cfg = config{Hooks: map[string][]string{
+		hookPostSave: {`echo "$GLEANER_NOTE_TITLE" >> "` + hookLog + `"`},
+	}}
+
+	selected := filepath.Join(notesDir, "1700000000-Inbox.md")
+	if err := os.WriteFile(selected, []byte("hello\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	plugin := installPlugin(t, "split", `
+input=$(cat)
+case "$input" in *'"content":"hello\n"'*) ;; *) echo "unexpected request: $input" >&2; exit 1 ;; esac
+printf '%s\n' '{"content": "hello, world\n", "create": [{"title": "Part", "content": "one"}, {"title": "Part", "content": "two"}], "status": "split up"}'
+`)
+
+	msg := runPlugin(plugin, &note{title: "Inbox", path: selected, createdAt: 1700000000})().(pluginMsg)
+	if msg.err != nil || msg.status != "split up" {
+		t.Fatalf("plugin gave %q, %v", msg.status, msg.err)
+	}
+	if data, _ := os.ReadFile(selected); string(data) != "hello, world\n" {
+		t.Errorf("selected note is %q", data)
+	}
+
+	parts, _ := filepath.Glob(filepath.Join(notesDir, "*-Part.md"))
+	sort.Strings(parts)
+	if len(parts) != 2 {
+		t.Fatalf("created %v, want two notes", parts)
+	}
+	for i, want := range []string{"one", "two"} {
+		if data, _ := os.ReadFile(parts[i]); string(data) != want {
+			t.Errorf("%s holds %q, want %q", filepath.Base(parts[i]), data, want)
+		}
+	}
+
+	hooks, _ := os.ReadFile(hookLog)
+	if got := strings.Fields(string(hooks)); strings.Join(got, ",") != "Inbox,Part,Part" {
+		t.Errorf("post-save hooks ran for %v", got)
+	}
+	log, _ := os.ReadFile(filepath.Join(notesDir, auditLogFile))
+	if strings.Count(string(log), `"action":"create"`) != 2 || strings.Count(string(log), `"action":"edit"`) != 1 {
+		t.Errorf("change log is\n%s", log)
+	}
+}
+
+func TestPluginFailures(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	oldDir, oldCfg := notesDir, cfg
+	t.Cleanup(func() { notesDir, cfg = oldDir, oldCfg })
+	notesDir, cfg = t.TempDir(), config{}
+
+	tests := []struct {
+		name, script, want string
+	}{
+		{"exit status", "echo broken >&2; exit 3", "broken"},
+		{"bad response", "echo 'not json'", "invalid plugin response"},
+		{"content without a note", `echo '{"content": "x"}'`, "no note is selected"},
+		{"untitled note", `echo '{"create": [{"title": " "}]}'`, "without a title"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := runPlugin(installPlugin(t, "p", tt.script), nil)().(pluginMsg)
+			if msg.err == nil || !strings.Contains(msg.err.Error(), tt.want) {
+				t.Errorf("got %v, want an error about %q", msg.err, tt.want)
+			}
+		})
+	}
+
+	// A plugin with nothing to say changes nothing
+	if msg := runPlugin(installPlugin(t, "quiet", "cat > /dev/null"), nil)().(pluginMsg); msg.err != nil {
+		t.Error(msg.err)
+	}
+}
+
+func TestListPlugins(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	installPlugin(t, "b", "")
+	installPlugin(t, "a", "")
+	if err := os.WriteFile(filepath.Join(pluginDir(), "README"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(filepath.Join(pluginDir(), "lib"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	got := listPlugins()
+	want := []string{filepath.Join(pluginDir(), "a"), filepath.Join(pluginDir(), "b")}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("listPlugins() = %v, want %v", got, want)
+	}
+}
+
+func TestNewNotePathSkipsTakenIds(t *testing.T) {
+	old := notesDir
+	notesDir = t.TempDir()
+	t.Cleanup(func() { notesDir = old })
+
+	ids := map[int64]bool{}
+	for _, title := range []string{"Same", "Same", "Other"} {
+		path := newNotePath(title)
+		name, id, ok := parseNoteFilename(filepath.Base(path))
+		if !ok || name != title || ids[id] {
+			t.Fatalf("%s is not a new note named %q with an id of its own", path, title)
+		}
+		ids[id] = true
+		if err := os.WriteFile(path, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
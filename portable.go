@@ -0,0 +1,38 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// File that, placed next to the gleaner executable, turns on portable mode
+// just as --portable does
+const portableMarker = "gleaner.portable"
+
+// Folder next to the executable that holds notes, backups, config and logs
+// in portable mode; empty otherwise
+var portableDir string
+
+// setupPortable turns on portable mode when the flag is given or the
+// marker file sits next to the executable, so gleaner can run from a USB
+// stick without leaving anything in the home folder
+func setupPortable(flagSet bool) error {
+	exe, err := os.Executable()
+	if err == nil {
+		exe, err = filepath.EvalSymlinks(exe)
+	}
+	if err != nil {
+		if flagSet {
+			return err
+		}
+		return nil
+	}
+	dir := filepath.Dir(exe)
+	if !flagSet {
+		if _, err := os.Stat(filepath.Join(dir, portableMarker)); err != nil {
+			return nil
+		}
+	}
+	portableDir = filepath.Join(dir, "gleaner-data")
+	return nil
+}
@@ -0,0 +1,275 @@
+package main
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Preview styling
+var (
+	previewH1Style    = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("205")).Underline(true)
+	previewHStyle     = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("212"))
+	previewCodeStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("150"))
+	previewQuoteStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("245")).Italic(true)
+	previewRuleStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("238"))
+	previewLinkStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("75")).Underline(true)
+	previewBoldStyle  = lipgloss.NewStyle().Bold(true)
+	previewEmStyle    = lipgloss.NewStyle().Italic(true)
+	previewDimStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+)
+
+// Inline markdown patterns, applied in order
+var (
+	inlineCodePattern = regexp.MustCompile("`([^`]+)`")
+	boldPattern       = regexp.MustCompile(`\*\*([^*]+)\*\*|__([^_]+)__`)
+	emPattern         = regexp.MustCompile(`\*([^*\s][^*]*)\*|\b_([^_\s][^_]*)_\b`)
+	linkPattern       = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`)
+	rulePattern       = regexp.MustCompile(`^\s*([-*_])(\s*[-*_]){2,}\s*$`)
+)
+
+// renderedDoc is a note rendered for the preview pane
+type renderedDoc struct {
+	lines   []string // Rendered, wrapped output lines
+	srcLine []int    // For each source line, the first output line it produced
+}
+
+// renderMarkdown renders a markdown document for a pane of the given width.
+// dir is the folder of the note, which image paths are relative to, and
+// embedding holds the paths of the notes it is shown within, outermost
+// first, for the ![[Title]] embeds in it.
+func renderMarkdown(src, dir string, width int, embedding []string) renderedDoc {
+	var doc renderedDoc
+	width = max(width, 10)
+	inCode := false
+	var code *syntax // Language of the open code block, nil when unknown
+	lines := strings.Split(src, "\n")
+	footnotes := footnoteNumbers(lines)
+	var math []string // Lines of the open $$ display math block, nil when none
+	var diagramLang string
+	var diagramSrc []string // Lines of the open diagram block, nil when none
+
+	var table []string // Lines of the table being read, nil when none
+	flushTable := func() {
+		rendered, starts := renderTable(table, width)
+		for i, start := range starts {
+			doc.srcLine[len(doc.srcLine)-len(table)+i] = len(doc.lines) + start
+		}
+		doc.lines = append(doc.lines, rendered...)
+		table = nil
+	}
+
+	for _, line := range lines {
+		if table != nil && !isTableLine(line) {
+			flushTable()
+		}
+		doc.srcLine = append(doc.srcLine, len(doc.lines))
+		trimmed := strings.TrimSpace(line)
+
+		if strings.HasPrefix(trimmed, "```") {
+			inCode = !inCode
+			lang := strings.TrimPrefix(trimmed, "```")
+			code = syntaxFor(lang)
+			if diagramSrc != nil {
+				doc.lines = append(doc.lines, renderDiagram(diagramLang, diagramSrc, width)...)
+				diagramSrc = nil
+			} else if inCode && isDiagram(lang) {
+				diagramLang, diagramSrc = lang, []string{}
+			}
+			doc.lines = append(doc.lines, previewDimStyle.Render(strings.Repeat("─", min(width, 20))))
+			continue
+		}
+		if diagramSrc != nil {
+			diagramSrc = append(diagramSrc, line)
+			continue
+		}
+		if inCode {
+			doc.lines = append(doc.lines, truncateLine(highlightLine(line, code), width))
+			continue
+		}
+
+		// Display math runs from $$ to $$, on one line or several
+		if math == nil && strings.HasPrefix(trimmed, "$$") {
+			rest := strings.TrimPrefix(trimmed, "$$")
+			if tex, ok := strings.CutSuffix(rest, "$$"); ok {
+				doc.lines = append(doc.lines, renderMathBlock(tex, width)...)
+				continue
+			}
+			math = []string{rest}
+			continue
+		}
+		if math != nil {
+			tex, closed := strings.CutSuffix(trimmed, "$$")
+			math = append(math, tex)
+			if closed {
+				doc.lines = append(doc.lines, renderMathBlock(strings.Join(math, "\n"), width)...)
+				math = nil
+			}
+			continue
+		}
+
+		if isTableLine(line) {
+			table = append(table, line)
+			continue
+		}
+		if match := imagePattern.FindStringSubmatch(line); match != nil {
+			doc.lines = append(doc.lines, renderImage(match[1], match[2], dir, width)...)
+			continue
+		}
+		if match := embedPattern.FindStringSubmatch(line); match != nil {
+			doc.lines = append(doc.lines, renderEmbed(match[1], width, embedding)...)
+			continue
+		}
+		if footnote, ok := renderFootnote(line, footnotes); ok {
+			doc.lines = append(doc.lines, wrapLines(footnote, width)...)
+			continue
+		}
+
+		doc.lines = append(doc.lines, wrapLines(renderLine(renderFootnoteRefs(line, footnotes), width), width)...)
+	}
+	if math != nil {
+		doc.lines = append(doc.lines, renderMathBlock(strings.Join(math, "\n"), width)...)
+	}
+	if diagramSrc != nil {
+		doc.lines = append(doc.lines, renderDiagram(diagramLang, diagramSrc, width)...)
+	}
+	if table != nil {
+		flushTable()
+	}
+	return doc
+}
+
+// renderLine renders a single markdown line outside code blocks
+func renderLine(line string, width int) string {
+	trimmed := strings.TrimSpace(line)
+	switch {
+	case rulePattern.MatchString(line):
+		return previewRuleStyle.Render(strings.Repeat("─", width))
+
+	case strings.HasPrefix(trimmed, "#"):
+		level := len(trimmed) - len(strings.TrimLeft(trimmed, "#"))
+		text := strings.TrimSpace(trimmed[level:])
+		if level == 1 {
+			return previewH1Style.Render(renderInline(text))
+		}
+		return previewHStyle.Render(strings.Repeat("#", level-1) + " " + renderInline(text))
+
+	case strings.HasPrefix(trimmed, ">"):
+		return previewQuoteStyle.Render("│ " + renderInline(strings.TrimSpace(trimmed[1:])))
+	}
+
+	if match := listItemPattern.FindStringSubmatch(line); match != nil {
+		indent, number, delim, checkbox, text := match[1], match[3], match[4], match[6], match[7]
+		marker := "•"
+		if number != "" {
+			marker = number + delim
+		}
+		switch strings.TrimSpace(checkbox) {
+		case "[ ]":
+			marker = "☐"
+		case "[x]", "[X]":
+			marker = "☑"
+			text = previewDimStyle.Strikethrough(true).Render(text)
+		}
+		return indent + marker + " " + renderInline(text)
+	}
+
+	return renderInline(line)
+}
+
+// renderInline applies emphasis, code, link and math styling within a line
+func renderInline(text string) string {
+	return renderInlineMath(text, renderInlineText)
+}
+
+// renderInlineText applies emphasis, code and link styling to text
+// without math
+func renderInlineText(text string) string {
+	text = inlineCodePattern.ReplaceAllStringFunc(text, func(s string) string {
+		return previewCodeStyle.Render(inlineCodePattern.FindStringSubmatch(s)[1])
+	})
+	text = linkPattern.ReplaceAllStringFunc(text, func(s string) string {
+		m := linkPattern.FindStringSubmatch(s)
+		return previewLinkStyle.Render(m[1]) + previewDimStyle.Render(" ("+m[2]+")")
+	})
+	text = boldPattern.ReplaceAllStringFunc(text, func(s string) string {
+		m := boldPattern.FindStringSubmatch(s)
+		return previewBoldStyle.Render(m[1] + m[2])
+	})
+	text = emPattern.ReplaceAllStringFunc(text, func(s string) string {
+		m := emPattern.FindStringSubmatch(s)
+		return previewEmStyle.Render(m[1] + m[2])
+	})
+	if mentionPattern != nil {
+		text = mentionPattern.ReplaceAllStringFunc(text, func(s string) string {
+			return previewLinkStyle.Render(s)
+		})
+	}
+	return text
+}
+
+// wrapLines soft-wraps a rendered line to the pane width
+func wrapLines(line string, width int) []string {
+	if lipgloss.Width(line) <= width {
+		return []string{line}
+	}
+	return strings.Split(lipgloss.NewStyle().Width(width).Render(line), "\n")
+}
+
+// truncateLine cuts a line at the pane width
+func truncateLine(line string, width int) string {
+	return lipgloss.NewStyle().MaxWidth(width).Render(line)
+}
+
+// previewWindow returns height rendered lines positioned so the source line
+// under the cursor sits at the same height as the cursor in the editor
+func (d renderedDoc) previewWindow(cursorLine, cursorOffset, height int) string {
+	top := 0
+	if cursorLine >= 0 && cursorLine < len(d.srcLine) {
+		top = d.srcLine[cursorLine] - cursorOffset
+	}
+	top = max(0, min(top, len(d.lines)-height))
+
+	visible := d.lines[top:min(len(d.lines), top+height)]
+	out := make([]string, height)
+	copy(out, visible)
+	return strings.Join(out, "\n")
+}
+
+// togglePreview switches the rendered preview on or off
+func (m model) togglePreview() model {
+	m.preview = !m.preview
+	m.sideNote = nil
+	return m.resize()
+}
+
+// previewView renders the current note around the editor cursor
+func (m model) previewView(width, height int) string {
+	var embedding []string
+	dir := notesDir
+	if m.selectedNote != nil && m.mode != "new" {
+		embedding = []string{m.selectedNote.path}
+		dir = filepath.Dir(m.selectedNote.path)
+	}
+	doc := renderNote(m.textarea.Value(), m.editorExt(), dir, width, embedding)
+	row, _ := cursorPos(m.textarea)
+
+	// Keep the cursor line at the same distance from the top as in the editor
+	return doc.previewWindow(row, row-m.editorTop, height)
+}
+
+// trackEditorScroll follows the editor's scrolling, which keeps the cursor
+// line in view while moving as little as possible
+func (m model) trackEditorScroll() model {
+	row, _ := cursorPos(m.textarea)
+	height := max(1, m.textarea.Height())
+	switch {
+	case row < m.editorTop:
+		m.editorTop = row
+	case row > m.editorTop+height-1:
+		m.editorTop = row - height + 1
+	}
+	return m
+}
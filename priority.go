@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Ranks of the named priorities; "priority" in the frontmatter may also be
+// a number from 1 (most important) to 5
+var priorityRanks = map[string]int{"high": 1, "medium": 3, "low": 5}
+
+// Badge styles for important and less important notes
+var (
+	priorityBadge    = badgeStyle.Foreground(lipgloss.Color("0")).Background(lipgloss.Color("203"))
+	lowPriorityBadge = badgeStyle.Foreground(lipgloss.Color("250")).Background(lipgloss.Color("237"))
+)
+
+// parsePriority turns a priority field into a rank from 1 to 5, or 0 when
+// the note has no priority
+func parsePriority(value string) int {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if rank, ok := priorityRanks[value]; ok {
+		return rank
+	}
+	if rank, err := strconv.Atoi(value); err == nil && rank >= 1 && rank <= 5 {
+		return rank
+	}
+	return 0
+}
+
+// priorityLabel names a rank, using the words for the ranks that have one
+func priorityLabel(rank int) string {
+	for name, r := range priorityRanks {
+		if r == rank {
+			return tr(name)
+		}
+	}
+	return fmt.Sprintf("P%d", rank)
+}
+
+// renderPriority draws the priority badge of a note with a priority
+func renderPriority(rank int) string {
+	if rank <= 3 {
+		return priorityBadge.Render("! " + priorityLabel(rank))
+	}
+	return lowPriorityBadge.Render(priorityLabel(rank))
+}
+
+// morePressing reports whether priority rank a sorts before b; notes
+// without a priority come last
+func morePressing(a, b int) bool {
+	return a != 0 && (b == 0 || a < b)
+}
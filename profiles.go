@@ -0,0 +1,187 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// profile is a keymap and theme saved under a name, to switch between or
+// to share with other users as a file
+type profile struct {
+	Name   string            `json:"name"`
+	Theme  string            `json:"theme,omitempty"`
+	Keymap map[string]string `json:"keymap,omitempty"`
+}
+
+// profilesDir returns the folder saved profiles are kept in
+func profilesDir() string {
+	return filepath.Join(configDir(), "profiles")
+}
+
+// profilePath returns the file of the named profile
+func profilePath(name string) string {
+	return filepath.Join(profilesDir(), sanitizeFileName(name)+".json")
+}
+
+// readProfile reads a profile file
+func readProfile(path string) (profile, error) {
+	var p profile
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return p, err
+	}
+	if err := json.Unmarshal(data, &p); err != nil {
+		return p, fmt.Errorf("%s: %w", path, err)
+	}
+	return p, nil
+}
+
+// writeProfile writes a profile file
+func writeProfile(path string, p profile) error {
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// profileProblems checks a profile's name, theme and keymap
+func profileProblems(p profile) []string {
+	var problems []string
+	if strings.TrimSpace(p.Name) == "" || sanitizeFileName(p.Name) == "" {
+		problems = append(problems, "the profile has no name")
+	}
+	switch p.Theme {
+	case "", themeHighContrast, themeNoColor:
+	default:
+		problems = append(problems, fmt.Sprintf("unknown theme %q; use %s or %s", p.Theme, themeHighContrast, themeNoColor))
+	}
+	return append(problems, keymapProblems(p.Keymap)...)
+}
+
+// importProfile checks a profile shared by someone else and saves it among
+// the profiles. Nothing is saved while there are problems; a profile of the
+// same name is only replaced with force.
+func importProfile(path string, force bool) (profile, error) {
+	p, err := readProfile(path)
+	if err != nil {
+		return p, err
+	}
+	problems := profileProblems(p)
+	if existing, err := readProfile(profilePath(p.Name)); err == nil && !force &&
+		(existing.Theme != p.Theme || !maps.Equal(existing.Keymap, p.Keymap)) {
+		problems = append(problems, fmt.Sprintf("conflict: a different profile named %q exists; use --force to replace it", p.Name))
+	}
+	if len(problems) > 0 {
+		return p, fmt.Errorf("cannot import %s:\n  %s", path, strings.Join(problems, "\n  "))
+	}
+	return p, writeProfile(profilePath(p.Name), p)
+}
+
+// listProfiles prints the saved profiles
+func listProfiles() error {
+	entries, err := os.ReadDir(profilesDir())
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	count := 0
+	for _, e := range entries {
+		if filepath.Ext(e.Name()) != ".json" {
+			continue
+		}
+		p, err := readProfile(filepath.Join(profilesDir(), e.Name()))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+			continue
+		}
+		theme := p.Theme
+		if theme == "" {
+			theme = "default"
+		}
+		fmt.Printf("%-20s  theme %-13s  %d keys moved\n", p.Name, theme, len(p.Keymap))
+		count++
+	}
+	if count == 0 {
+		fmt.Println("No profiles saved; create one with gleaner profile --save <name>")
+	}
+	return nil
+}
+
+// runProfile implements `gleaner profile`, which lists the saved keymap
+// and theme profiles. --save stores the current ones under a name, or with
+// --out in a file to share; --import checks and saves a shared profile, and
+// --use makes a saved profile current.
+func runProfile(args []string) error {
+	flags := flag.NewFlagSet("profile", flag.ContinueOnError)
+	save := flags.String("save", "", "save the current keymap and theme as the named profile")
+	out := flags.String("out", "", "with --save, write the profile to this file instead")
+	imp := flags.String("import", "", "check a shared profile file and add it to the saved profiles")
+	force := flags.Bool("force", false, "with --import, replace a different profile of the same name")
+	use := flags.String("use", "", "make the named profile's keymap and theme current")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	chosen := 0
+	for _, v := range []string{*save, *imp, *use} {
+		if v != "" {
+			chosen++
+		}
+	}
+	if flags.NArg() > 0 || chosen > 1 {
+		return errors.New("usage: gleaner profile [--save <name> [--out <file>] | --import <file> [--force] | --use <name>]")
+	}
+
+	switch {
+	case *save != "":
+		p := profile{Name: *save, Theme: baseCfg.Theme, Keymap: baseCfg.Keymap}
+		if problems := profileProblems(p); len(problems) > 0 {
+			return fmt.Errorf("cannot save the profile:\n  %s", strings.Join(problems, "\n  "))
+		}
+		path := *out
+		if path == "" {
+			path = profilePath(*save)
+		}
+		if err := writeProfile(path, p); err != nil {
+			return err
+		}
+		fmt.Printf("Saved profile %q to %s\n", p.Name, path)
+
+	case *imp != "":
+		p, err := importProfile(*imp, *force)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Imported profile %q; make it current with gleaner profile --use %q\n", p.Name, p.Name)
+
+	case *use != "":
+		p, err := readProfile(profilePath(*use))
+		if errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("no profile named %q", *use)
+		}
+		if err != nil {
+			return err
+		}
+		if problems := profileProblems(p); len(problems) > 0 {
+			return fmt.Errorf("profile %q has problems:\n  %s", p.Name, strings.Join(problems, "\n  "))
+		}
+		baseCfg.Theme = p.Theme
+		baseCfg.Keymap = p.Keymap
+		if err := saveConfig(); err != nil {
+			return err
+		}
+		fmt.Printf("Using profile %q from the next start\n", p.Name)
+
+	default:
+		return listProfiles()
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// openPrompt asks for a line of text below the panes; kind tells
+// promptSubmitted what to do with the answer
+func (m model) openPrompt(kind, label, value string) (model, tea.Cmd) {
+	m.prompt = textinput.New()
+	m.prompt.Prompt = label + ": "
+	m.prompt.SetValue(value)
+	m.prompt.Width = max(10, m.width-len(label)-10)
+	cmd := m.prompt.Focus()
+
+	m.promptKind = kind
+	m.promptReturn = m.mode
+	m.mode = "prompt"
+	return m, cmd
+}
+
+// answeredYes reports whether a confirmation prompt was answered yes, in
+// English or the interface language
+func answeredYes(answer string) bool {
+	answer = strings.TrimSpace(answer)
+	return strings.EqualFold(answer, "yes") || strings.EqualFold(answer, tr("yes"))
+}
+
+// updatePrompt handles keys while a prompt is open
+func (m model) updatePrompt(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.mode = m.promptReturn
+		return m, nil
+
+	case tea.KeyEnter:
+		m.mode = m.promptReturn
+		return m.promptSubmitted(m.promptKind, m.prompt.Value())
+	}
+
+	var cmd tea.Cmd
+	m.prompt, cmd = m.prompt.Update(msg)
+	return m, cmd
+}
+
+// promptSubmitted acts on the answer given to a prompt of the given kind
+func (m model) promptSubmitted(kind, value string) (tea.Model, tea.Cmd) {
+	switch kind {
+	case "created-range", "modified-range":
+		return m.setCustomDateRange(kind == "modified-range", value)
+	case "checklist-add", "checklist-edit":
+		return m.checklistPrompted(kind, value)
+	case "field", "field-add":
+		return m.saveField(kind, value)
+	case "person-field":
+		return m.savePersonField(value)
+	case "meeting-attendees", "meeting-agenda":
+		return m.meetingPrompted(kind, value)
+	case "rename-pattern", "rename-replacement":
+		return m.renamePrompted(kind, value)
+	case "replace-find", "replace-with":
+		return m.replacePrompted(kind, value)
+	case "tag-edit":
+		return m.tagEditPrompted(value)
+	case "confirm-share":
+		return m.confirmShare(value)
+	case "confirm-backup":
+		return m.confirmBackup(value)
+	case "confirm-publish":
+		return m.confirmPublish(value)
+	case "confirm-attachments":
+		return m.confirmDeleteAttachments(value)
+	case "confirm-empty-trash":
+		return m.confirmEmptyTrash(value)
+	case "bookmark":
+		if strings.TrimSpace(value) == "" {
+			return m, nil
+		}
+		m.status = trf("Fetching %s...", strings.TrimSpace(value))
+		return m, saveBookmark(value)
+	}
+	return m, nil
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+// QR code caption styling
+var qrCaptionStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+
+// showQR renders the selected note as a QR code, or the link it was shared
+// to when there is one, so it can be scanned with a phone
+func (m model) showQR() model {
+	data, err := readNoteFile(m.selectedNote.path)
+	if err != nil {
+		m.status = err.Error()
+		return m
+	}
+	fields, body := parseFrontmatter(string(data))
+
+	payload, caption := body, m.selectedNote.title
+	if url := metaValue(fields, gistKey); url != "" {
+		payload, caption = url, url
+	}
+
+	code, err := qrcode.New(payload, qrcode.Low)
+	if err != nil {
+		m.status = tr("Note is too long for a QR code")
+		return m
+	}
+	// Terminals draw blocks in a light colour on dark, so invert the code to
+	// keep its dark modules dark
+	art := strings.TrimSuffix(code.ToSmallString(true), "\n")
+	if lipgloss.Height(art) > m.height-2 || lipgloss.Width(art) > m.width {
+		m.status = tr("Window is too small for this QR code")
+		return m
+	}
+
+	m.qrCode = lipgloss.JoinVertical(lipgloss.Center, art, qrCaptionStyle.Render(caption+" · "+tr("any key to close")))
+	return m
+}
+
+// qrView shows the QR code centered on screen
+func (m model) qrView() string {
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, m.qrCode)
+}
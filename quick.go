@@ -0,0 +1,126 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// quickModel is the single-pane capture window of `gleaner quick`
+type quickModel struct {
+	title  textinput.Model
+	body   textarea.Model
+	status string
+	saved  bool
+	warn   error // A post-save hook that failed after the note was saved
+}
+
+// newQuickModel sets up an empty capture window with the title focused
+func newQuickModel() quickModel {
+	ti := textinput.New()
+	ti.Placeholder = tr("Title")
+	ti.CharLimit = 50
+	ti.Focus()
+
+	ta := textarea.New()
+	ta.Placeholder = tr("Note (Ctrl+S to save)")
+	ta.ShowLineNumbers = false
+	ta.CharLimit = 0
+	ta.MaxHeight = 0
+	ta.Prompt = editorPrompt()
+
+	return quickModel{title: ti, body: ta}
+}
+
+// Init starts the cursor blinking
+func (q quickModel) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+// Update handles typing, focus changes, saving and quitting
+func (q quickModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		q.title.Width = msg.Width - 4
+		q.body.SetWidth(msg.Width - 2)
+		q.body.SetHeight(max(1, msg.Height-4))
+
+	case tea.KeyMsg:
+		switch msg.Type {
+		case tea.KeyCtrlC, tea.KeyEsc:
+			return q, tea.Quit
+
+		case tea.KeyTab, tea.KeyShiftTab:
+			if q.title.Focused() {
+				q.title.Blur()
+				return q, q.body.Focus()
+			}
+			q.body.Blur()
+			return q, q.title.Focus()
+
+		case tea.KeyCtrlS:
+			title := strings.TrimSpace(q.title.Value())
+			if title == "" {
+				q.status = tr("A title is required")
+				return q, nil
+			}
+			// A failed write or vetoing hook leaves the window open to try
+			// again. A post-save hook fails after the note is written, so
+			// it is still saved, and saving again would make a copy.
+			switch msg := saveNote(title, q.body.Value(), nil)().(type) {
+			case errMsg:
+				q.status = tr(msg.summary) + ": " + explainError(msg.err)
+				return q, nil
+			case hookErrMsg:
+				q.warn = msg.err
+			}
+			q.saved = true
+			return q, tea.Quit
+		}
+	}
+
+	var cmd tea.Cmd
+	if q.title.Focused() {
+		q.title, cmd = q.title.Update(msg)
+	} else {
+		q.body, cmd = q.body.Update(msg)
+	}
+	return q, cmd
+}
+
+// View shows the title, body and a one-line hint
+func (q quickModel) View() string {
+	hint := tr("tab:Switch field | ctrl+s:Save | esc:Discard")
+	if q.status != "" {
+		hint = q.status
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, q.title.View(), "", q.body.View(), helpStyle.Render(hint))
+}
+
+// runQuick implements `gleaner quick`, a capture window that saves one note
+// and exits
+func runQuick(args []string) error {
+	if len(args) > 0 {
+		return fmt.Errorf("usage: gleaner quick")
+	}
+	if readOnly {
+		return errReadOnly
+	}
+
+	final, err := tea.NewProgram(newQuickModel(), tea.WithAltScreen()).Run()
+	if err != nil {
+		return err
+	}
+	if q := final.(quickModel); q.saved {
+		fmt.Println(trf("Saved %s", strings.TrimSpace(q.title.Value())))
+		if q.warn != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", q.warn)
+		}
+	}
+	return nil
+}
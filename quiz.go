@@ -0,0 +1,170 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// quizSection is a heading of the quizzed note and the text under it
+type quizSection struct {
+	heading string
+	body    string
+	id      string // Key of the section's score: the note id and the heading
+}
+
+// quizScore counts how often a section was recalled
+type quizScore struct {
+	Right int `json:"right"`
+	Asked int `json:"asked"`
+}
+
+// Serializes updates to the quiz log
+var quizLogMu sync.Mutex
+
+// quizLogPath returns the file the quiz scores are kept in
+func quizLogPath() string {
+	return filepath.Join(configDir(), "quiz.json")
+}
+
+// loadQuizLog reads the recall scores of every vault, keyed by notes
+// directory and then by section id
+func loadQuizLog() vaultState[quizScore] {
+	return loadState[quizScore](quizLogPath())
+}
+
+// recordQuizAnswer counts an answer towards a section's score in this
+// vault, and returns the new score
+func recordQuizAnswer(id string, right bool) (quizScore, error) {
+	return updateState(&quizLogMu, quizLogPath(), id, func(score quizScore) quizScore {
+		score.Asked++
+		if right {
+			score.Right++
+		}
+		return score
+	})
+}
+
+// quizSections splits a note at its headings, keeping the sections that
+// have text under them
+func quizSections(n note, content string) []quizSection {
+	lines := strings.Split(content, "\n")
+	headings := parseHeadings(content)
+	var sections []quizSection
+	for i, h := range headings {
+		end := len(lines)
+		if i+1 < len(headings) {
+			end = headings[i+1].line
+		}
+		body := strings.Trim(strings.Join(lines[h.line+1:end], "\n"), "\n")
+		if strings.TrimSpace(body) == "" {
+			continue
+		}
+		sections = append(sections, quizSection{
+			heading: h.text,
+			body:    body,
+			id:      fmt.Sprintf("%d/%s", n.createdAt, h.text),
+		})
+	}
+	return sections
+}
+
+// startQuiz quizzes the selected note section by section
+func (m model) startQuiz() model {
+	m.quizSections = quizSections(*m.selectedNote, m.textarea.Value())
+	if len(m.quizSections) == 0 {
+		m.status = tr("No sections with text under a heading to quiz on")
+		return m
+	}
+	m.quizIndex, m.quizRight, m.quizShown = 0, 0, false
+	m.quizScores = loadQuizLog()[notesDir]
+	if m.quizScores == nil {
+		m.quizScores = make(map[string]quizScore)
+	}
+	m.mode = "quiz"
+	return m
+}
+
+// updateQuiz shows the text of the current section on space or enter, and
+// then takes y if it was recalled and n if not
+func (m model) updateQuiz(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	key := msg.String()
+	switch {
+	case msg.Type == tea.KeyEsc || key == "q":
+		m.mode = "list"
+
+	case m.quizIndex >= len(m.quizSections):
+		m.mode = "list"
+
+	case !m.quizShown && (key == " " || key == "enter"):
+		m.quizShown = true
+
+	case m.quizShown && (key == "y" || key == "n"):
+		if key == "y" {
+			m.quizRight++
+		}
+		id := m.quizSections[m.quizIndex].id
+		score, err := recordQuizAnswer(id, key == "y")
+		if err != nil {
+			m.status = trf("Could not save the score: %v", err)
+		} else {
+			m.quizScores[id] = score
+		}
+		m.quizIndex++
+		m.quizShown = false
+	}
+	return m, nil
+}
+
+// quizText renders the current section, or the scores once all are done
+func (m model) quizText(width int) string {
+	scores := m.quizScores
+	if m.quizIndex >= len(m.quizSections) {
+		lines := []string{cardQuestionStyle.Render(trf("Recalled %d of %d sections", m.quizRight, len(m.quizSections))), ""}
+		for _, s := range m.quizSections {
+			score := scores[s.id]
+			lines = append(lines, fmt.Sprintf("%s  %s", s.heading,
+				cardSourceStyle.Render(trf("%d of %d times", score.Right, score.Asked))))
+		}
+		return strings.Join(lines, "\n")
+	}
+
+	section := m.quizSections[m.quizIndex]
+	progress := trf("Section %d of %d", m.quizIndex+1, len(m.quizSections))
+	if score := scores[section.id]; score.Asked > 0 {
+		progress += " · " + trf("recalled %d of %d times", score.Right, score.Asked)
+	}
+	lines := []string{cardSourceStyle.Render(progress), "", cardQuestionStyle.Render(section.heading)}
+	if m.quizShown {
+		lines = append(lines, "")
+		for _, line := range strings.Split(section.body, "\n") {
+			lines = append(lines, wrapLines(renderLine(line, width), width)...)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// quizHelp describes the keys for the current step of the quiz
+func (m model) quizHelp() string {
+	switch {
+	case m.quizIndex >= len(m.quizSections):
+		return tr("esc:Back")
+	case m.quizShown:
+		return tr("y:Recalled | n:Forgot | esc:Back")
+	default:
+		return tr("space:Show answer | esc:Back")
+	}
+}
+
+// quizView shows the quiz full screen
+func (m model) quizView() string {
+	width := max(m.width-12, 10)
+	return docStyle.Render(lipgloss.JoinVertical(lipgloss.Top,
+		splitStyle.Width(m.width-8).Height(m.height-6).Render(m.quizText(width)),
+		helpStyle.Render(m.quizHelp()),
+	))
+}
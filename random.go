@@ -0,0 +1,80 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"strings"
+)
+
+// withTag returns the notes carrying a tag, ignoring case; all notes when
+// tag is empty
+func withTag(notes []note, tag string) []note {
+	tag = strings.TrimPrefix(strings.TrimSpace(tag), "#")
+	if tag == "" {
+		return notes
+	}
+	var tagged []note
+	for _, n := range notes {
+		if slices.ContainsFunc(n.tags, func(t string) bool { return strings.EqualFold(t, tag) }) {
+			tagged = append(tagged, n)
+		}
+	}
+	return tagged
+}
+
+// randomNote picks one of the notes with a tag at random
+func randomNote(notes []note, tag string) (note, error) {
+	notes = withTag(notes, tag)
+	if len(notes) == 0 {
+		if tag != "" {
+			return note{}, fmt.Errorf("%w tagged %q", errNoNote, tag)
+		}
+		return note{}, errNoNote
+	}
+	return notes[rand.N(len(notes))], nil
+}
+
+// surpriseMe selects a random note among those the list shows
+func (m model) surpriseMe() model {
+	var visible []note
+	for _, item := range m.list.VisibleItems() {
+		visible = append(visible, item.(note))
+	}
+	n, err := randomNote(visible, "")
+	if err != nil {
+		m.status = tr("No notes.")
+		return m
+	}
+	m = m.selectPath(n.path)
+	m.status = trf("How about %s?", n.title)
+	return m
+}
+
+// runRandom implements `gleaner random [--tag tag]`, starting the TUI with a
+// random note selected
+func runRandom(args []string) error {
+	flags := flag.NewFlagSet("random", flag.ContinueOnError)
+	tag := flags.String("tag", "", "only pick notes with this tag")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() > 0 {
+		return errors.New("usage: gleaner random [--tag tag]")
+	}
+
+	notes, err := notesForCommand()
+	if err != nil {
+		return err
+	}
+	n, err := randomNote(notes, *tag)
+	if err != nil {
+		return err
+	}
+
+	m := initialModel()
+	m.selectedNote = &n
+	return runTUI(m)
+}
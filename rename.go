@@ -0,0 +1,329 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// renamePlan is one note of a batch rename and the title it gets
+type renamePlan struct {
+	n       note
+	title   string
+	problem string // Why the note cannot be renamed, empty when it can
+}
+
+// renamedPath returns where a note is kept under a new title: the same
+// folder, id and extension with the new name
+func renamedPath(n note, title string) string {
+	id, _, _ := strings.Cut(filepath.Base(n.path), "-")
+	return filepath.Join(filepath.Dir(n.path), id+"-"+sanitizeFileName(title)+filepath.Ext(n.path))
+}
+
+// planRenames works out the new title of each note whose title the
+// pattern matches, and which renames cannot go ahead: an empty title, or
+// a file name another note already has or would get
+func planRenames(notes []note, re *regexp.Regexp, replacement string) []renamePlan {
+	var plans []renamePlan
+	taken := map[string]string{}
+	for _, n := range notes {
+		title := strings.TrimSpace(re.ReplaceAllString(n.title, replacement))
+		if title == n.title {
+			continue
+		}
+		p := renamePlan{n: n, title: title}
+		path := renamedPath(n, title)
+		switch {
+		case sanitizeFileName(title) == "":
+			p.problem = "the title would be empty"
+		case taken[path] != "":
+			p.problem = fmt.Sprintf("clashes with the new title of %s", taken[path])
+		case path != n.path:
+			oldInfo, oldErr := os.Stat(n.path)
+			newInfo, newErr := os.Stat(path)
+			if newErr == nil && (oldErr != nil || !os.SameFile(oldInfo, newInfo)) {
+				p.problem = fmt.Sprintf("%s already exists", filepath.Base(path))
+			}
+		}
+		if p.problem == "" {
+			taken[path] = n.title
+		}
+		plans = append(plans, p)
+	}
+	return plans
+}
+
+// renameNote gives a note a new title. Its file is renamed, keeping its id,
+// folder and extension, and a title in its frontmatter is changed too. The
+// rename is logged like any other change.
+func renameNote(n note, title string) (note, error) {
+	path := renamedPath(n, title)
+	if path != n.path {
+		if err := os.Rename(n.path, path); err != nil {
+			return n, err
+		}
+	}
+	renamed := n
+	renamed.path, renamed.title = path, title
+
+	content, err := readNoteFile(path)
+	if err != nil {
+		return renamed, err
+	}
+	recordChange(auditRename, path, string(content), string(content))
+	if fields, _ := parseFrontmatter(string(content)); metaValue(fields, "title") != "" {
+		if err := writeNoteFile(path, setFrontmatter(string(content), "title", yamlString(title))); err != nil {
+			return renamed, err
+		}
+	}
+	return renamed, nil
+}
+
+// rewriteLinks points the links in a note's text that lead to from at to
+// instead: wiki links by title, keeping their labels, and Markdown links by
+// file name. Links by id or gleaner:// link still lead to the note and are
+// left alone. It returns the new text and how many links changed.
+func rewriteLinks(notes []note, content string, from, to note) (string, int) {
+	count := 0
+	content = wikiLinkPattern.ReplaceAllStringFunc(content, func(link string) string {
+		target := wikiLinkPattern.FindStringSubmatch(link)[1]
+		linked, err := findNote(notes, strings.TrimSpace(target))
+		if err != nil || linked.path != from.path || strings.EqualFold(strings.TrimSpace(target), to.title) ||
+			strings.TrimSpace(target) == fmt.Sprint(from.createdAt) || isAlias(from, target) {
+			return link
+		}
+		count++
+		return strings.Replace(link, target, to.title, 1)
+	})
+	content = linkPattern.ReplaceAllStringFunc(content, func(link string) string {
+		match := linkPattern.FindStringSubmatch(link)
+		target, anchor, _ := strings.Cut(match[2], "#")
+		if !isNoteExt(filepath.Ext(target)) || filepath.Base(from.path) == filepath.Base(to.path) {
+			return link
+		}
+		if linked, ok := resolveLink(notes, target); !ok || linked.path != from.path {
+			return link
+		}
+		dir, _ := strings.CutSuffix(target, url.PathEscape(filepath.Base(from.path)))
+		if dir == target {
+			dir, _ = strings.CutSuffix(target, filepath.Base(from.path))
+		}
+		updated := dir + url.PathEscape(filepath.Base(to.path))
+		if anchor != "" {
+			updated += "#" + anchor
+		}
+		count++
+		return "[" + match[1] + "](" + updated + ")"
+	})
+	return content, count
+}
+
+// isAlias reports whether a link target is one of a note's aliases, which
+// keep leading to it under a new title
+func isAlias(n note, target string) bool {
+	target = strings.TrimSpace(target)
+	return !strings.EqualFold(target, n.title) &&
+		slices.ContainsFunc(n.aliases, func(alias string) bool { return strings.EqualFold(alias, target) })
+}
+
+// updateLinks rewrites the links leading to a renamed note in every note,
+// returning how many links changed
+func updateLinks(notes []note, from, to note) (int, error) {
+	total := 0
+	var errs []error
+	for _, n := range notes {
+		path := n.path
+		if path == from.path {
+			path = to.path
+		}
+		content, err := readNoteFile(path)
+		if err != nil {
+			continue
+		}
+		updated, count := rewriteLinks(notes, string(content), from, to)
+		if count == 0 {
+			continue
+		}
+		if err := writeNoteFile(path, updated); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		total += count
+	}
+	return total, errors.Join(errs...)
+}
+
+// linksUpdatedMsg reports the links rewritten after a note was renamed in
+// the editor
+type linksUpdatedMsg struct {
+	count int
+	err   error
+}
+
+// relinkRenamed rewrites the links to a note saved under a new title, at
+// path, in every note
+func relinkRenamed(from note, path, title string) (int, error) {
+	notes, err := scanNotes()
+	if err != nil && len(notes) == 0 {
+		return 0, err
+	}
+	to := from
+	to.path, to.title = path, title
+
+	// Links are looked up among the notes as they were before the rename
+	found := false
+	for i := range notes {
+		if notes[i].path == path {
+			notes[i], found = from, true
+		}
+	}
+	if !found {
+		notes = append(notes, from)
+	}
+	return updateLinks(notes, from, to)
+}
+
+// applyRenames renames the notes of a plan that can be renamed and updates
+// the links to them, returning how many notes and links changed
+func applyRenames(notes []note, plans []renamePlan) (int, int, error) {
+	notes = append([]note(nil), notes...)
+	renamed, links := 0, 0
+	for _, p := range plans {
+		if p.problem != "" {
+			continue
+		}
+		to, err := renameNote(p.n, p.title)
+		if err != nil {
+			return renamed, links, fmt.Errorf("%s: %w", p.n.title, err)
+		}
+		renamed++
+		count, err := updateLinks(notes, p.n, to)
+		links += count
+		if err != nil {
+			return renamed, links, err
+		}
+		// Later renames look links up among the notes as they are now
+		for i := range notes {
+			if notes[i].path == p.n.path {
+				notes[i] = to
+			}
+		}
+	}
+	return renamed, links, nil
+}
+
+// startBulkRename asks for the pattern to rename the listed notes by
+func (m model) startBulkRename() (model, tea.Cmd) {
+	return m.openPrompt("rename-pattern", tr("Rename titles matching (regular expression)"), m.renamePattern)
+}
+
+// renamePrompted takes the pattern and then the replacement, and shows
+// the titles the notes in the list would get
+func (m model) renamePrompted(kind, answer string) (tea.Model, tea.Cmd) {
+	if kind == "rename-pattern" {
+		if _, err := regexp.Compile(answer); err != nil || answer == "" {
+			m.status = tr("Enter a regular expression, such as ^Draft ")
+			return m, nil
+		}
+		m.renamePattern = answer
+		return m.openPrompt("rename-replacement", tr("Replace with ($1 for a group)"), "")
+	}
+
+	var listed []note
+	for _, item := range m.list.VisibleItems() {
+		listed = append(listed, item.(note))
+	}
+	plans := planRenames(listed, regexp.MustCompile(m.renamePattern), answer)
+	m.renamePlans = plans
+	if len(plans) == 0 {
+		m.status = tr("No titles in the list match")
+		return m, nil
+	}
+
+	ok := 0
+	var items []pickerItem
+	for _, p := range plans {
+		desc := filepath.Base(p.n.path) + " → " + filepath.Base(renamedPath(p.n, p.title))
+		if p.problem != "" {
+			desc = trf("Skipped: %s", p.problem)
+		} else {
+			ok++
+		}
+		items = append(items, pickerItem{title: p.n.title + " → " + p.title, desc: desc})
+	}
+	items = append([]pickerItem{{title: trf("Rename %d notes", ok), desc: tr("Links to them are updated too"), value: "apply"}}, items...)
+	return m.openPicker("rename", tr("Rename titles"), items), nil
+}
+
+// renameChosen carries out the shown renames when confirmed
+func (m model) renameChosen(value string) (tea.Model, tea.Cmd) {
+	plans := m.renamePlans
+	m.renamePlans = nil
+	if value != "apply" {
+		m.status = tr("Nothing renamed")
+		return m, nil
+	}
+	// Links are looked for in every note, not only those listed
+	notes, err := scanNotes()
+	if err != nil && len(notes) == 0 {
+		return m.notify(tr("Nothing renamed"), err)
+	}
+	renamed, links, err := applyRenames(notes, plans)
+	if err != nil {
+		next, cmd := m.notify(trf("Renamed %d notes before failing", renamed), err)
+		return next, tea.Batch(cmd, loadNotes)
+	}
+	next, cmd := m.notify(trf("Renamed %d notes and updated %d links", renamed, links), nil)
+	return next, tea.Batch(cmd, loadNotes)
+}
+
+// runRename implements `gleaner rename <pattern> <replacement>`, renaming
+// every note whose title the regular expression matches and updating the
+// links to it. --dry-run only prints the new titles.
+func runRename(args []string) error {
+	flags := flag.NewFlagSet("rename", flag.ContinueOnError)
+	dryRun := flags.Bool("dry-run", false, "print the new titles without renaming anything")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 2 {
+		return errors.New("usage: gleaner rename [--dry-run] <pattern> <replacement>")
+	}
+	re, err := regexp.Compile(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	if readOnly && !*dryRun {
+		return errReadOnly
+	}
+
+	notes, err := notesForCommand()
+	if err != nil {
+		return err
+	}
+	plans := planRenames(notes, re, flags.Arg(1))
+	ok := 0
+	for _, p := range plans {
+		if p.problem != "" {
+			fmt.Printf("%s → %s (skipped: %s)\n", p.n.title, p.title, p.problem)
+		} else {
+			fmt.Printf("%s → %s\n", p.n.title, p.title)
+			ok++
+		}
+	}
+	if *dryRun {
+		fmt.Printf("%d notes would be renamed\n", ok)
+		return nil
+	}
+	renamed, links, err := applyRenames(notes, plans)
+	fmt.Printf("Renamed %d notes and updated %d links\n", renamed, links)
+	return err
+}
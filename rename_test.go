@@ -0,0 +1,133 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+)
+
+func TestPlanRenames(t *testing.T) {
+	oldDir, oldCfg := notesDir, cfg
+	t.Cleanup(func() { notesDir, cfg = oldDir, oldCfg })
+	notesDir, cfg = t.TempDir(), config{}
+	for name, content := range map[string]string{
+		"1700000000-Meeting-2023.md": "minutes\n",
+		"1700000001-Meeting-2024.md": "minutes\n",
+		"1700000002-Notes-2023.md":   "notes\n",
+		"1700000002-Notes-2025.md":   "already here\n",
+	} {
+		if err := os.WriteFile(filepath.Join(notesDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	notes, err := scanNotes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		pattern, replacement string
+		want                 map[string]string // New title or problem of each note renamed
+	}{
+		{`2023`, "2025", map[string]string{
+			"Meeting 2023": "Meeting 2025",
+			"Notes 2023":   "1700000002-Notes-2025.md already exists",
+		}},
+		{`^Meeting (\d+)$`, "$1 meeting", map[string]string{
+			"Meeting 2023": "2023 meeting",
+			"Meeting 2024": "2024 meeting",
+		}},
+		{`.*`, "", map[string]string{
+			"Meeting 2023": "the title would be empty",
+			"Meeting 2024": "the title would be empty",
+			"Notes 2023":   "the title would be empty",
+			"Notes 2025":   "the title would be empty",
+		}},
+		{`nothing`, "x", map[string]string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			got := map[string]string{}
+			for _, p := range planRenames(notes, regexp.MustCompile(tt.pattern), tt.replacement) {
+				got[p.n.title] = p.title
+				if p.problem != "" {
+					got[p.n.title] = p.problem
+				}
+			}
+			if len(got) != len(tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+			for title, want := range tt.want {
+				if got[title] != want {
+					t.Errorf("%s: got %q, want %q", title, got[title], want)
+				}
+			}
+		})
+	}
+}
+
+func TestRewriteLinks(t *testing.T) {
+	oldDir, oldCfg := notesDir, cfg
+	t.Cleanup(func() { notesDir, cfg = oldDir, oldCfg })
+	notesDir, cfg = t.TempDir(), config{}
+	if err := os.MkdirAll(filepath.Join(notesDir, "projects"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range map[string]string{
+		"1700000000-Meeting-2023.md":     "---\naliases: [Kickoff]\n---\nminutes\n",
+		"1700000001-Notes.md":            "notes\n",
+		"projects/1700000002-Project.md": "project\n",
+	} {
+		if err := os.WriteFile(filepath.Join(notesDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	notes, err := scanNotes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	from, err := findNote(notes, "Meeting 2023")
+	if err != nil {
+		t.Fatal(err)
+	}
+	to := from
+	to.title = "Meeting 2025"
+	to.path = renamedPath(from, to.title)
+
+	tests := []struct {
+		name, content, want string
+		count               int
+	}{
+		{"wiki link", "See [[Meeting 2023]].", "See [[Meeting 2025]].", 1},
+		{"wiki link with label", "[[Meeting 2023|the meeting]]", "[[Meeting 2025|the meeting]]", 1},
+		{"link by id", "[[1700000000]]", "[[1700000000]]", 0},
+		{"link by alias", "[[Kickoff]]", "[[Kickoff]]", 0},
+		{"other note", "[[Notes]] and [[Project]]", "[[Notes]] and [[Project]]", 0},
+		{"markdown link", "[minutes](1700000000-Meeting-2023.md)", "[minutes](1700000000-Meeting-2025.md)", 1},
+		{"markdown link with anchor", "[agenda](1700000000-Meeting-2023.md#agenda)", "[agenda](1700000000-Meeting-2025.md#agenda)", 1},
+		{"markdown link to another file", "[x](1700000001-Notes.md)", "[x](1700000001-Notes.md)", 0},
+		{"web link", "[site](https://example.com/Meeting-2023.md)", "[site](https://example.com/Meeting-2023.md)", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, count := rewriteLinks(notes, tt.content, from, to)
+			if got != tt.want || count != tt.count {
+				t.Errorf("rewriteLinks(%q) = %q, %d; want %q, %d", tt.content, got, count, tt.want, tt.count)
+			}
+		})
+	}
+
+	t.Run("link from a folder", func(t *testing.T) {
+		project, err := findNote(notes, "Project")
+		if err != nil {
+			t.Fatal(err)
+		}
+		moved := project
+		moved.title = "Big project"
+		moved.path = filepath.Join(filepath.Dir(project.path), "1700000002-Big-project.md")
+		got, count := rewriteLinks(notes, "[p](projects/1700000002-Project.md)", project, moved)
+		if want := "[p](projects/1700000002-Big-project.md)"; got != want || count != 1 {
+			t.Errorf("got %q, %d; want %q, 1", got, count, want)
+		}
+	})
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Key that repeats the last change to a note's fields on the selected note
+const repeatKey = "."
+
+// fieldAction is a change made to a note's fields, such as a new label or
+// status, kept so it can be repeated on other notes
+type fieldAction struct {
+	key   string // Field changed
+	value string // New value; empty when the field was removed
+	// For tags, the tags added and removed, so repeating the change keeps
+	// the other note's own tags
+	addTags, removeTags []string
+}
+
+// tagsAction records an edit of the tags field as the tags it added and
+// removed
+func tagsAction(before, after []string) fieldAction {
+	a := fieldAction{key: "tags"}
+	for _, tag := range after {
+		if !slices.Contains(before, tag) {
+			a.addTags = append(a.addTags, tag)
+		}
+	}
+	for _, tag := range before {
+		if !slices.Contains(after, tag) {
+			a.removeTags = append(a.removeTags, tag)
+		}
+	}
+	return a
+}
+
+// String describes the change for the status line
+func (a fieldAction) String() string {
+	if a.key == "tags" {
+		var parts []string
+		for _, tag := range a.addTags {
+			parts = append(parts, "+#"+tag)
+		}
+		for _, tag := range a.removeTags {
+			parts = append(parts, "-#"+tag)
+		}
+		return strings.Join(parts, " ")
+	}
+	if a.value == "" {
+		return trf("remove %s", a.key)
+	}
+	return a.key + ": " + a.value
+}
+
+// apply makes the change to the note at path
+func (a fieldAction) apply(path string) error {
+	if a.key != "tags" {
+		return writeField(path, a.key, a.value)
+	}
+	content, err := readNoteFile(path)
+	if err != nil {
+		return err
+	}
+	fields, _ := parseFrontmatter(string(content))
+	var tags []string
+	for _, tag := range metaList(metaValue(fields, "tags")) {
+		if !slices.Contains(a.removeTags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	for _, tag := range a.addTags {
+		if !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	return writeTags(path, tags)
+}
+
+// repeatAction applies the last change to a note's fields, such as a
+// label, status or tag, to the selected note
+func (m model) repeatAction() (model, tea.Cmd) {
+	switch {
+	case m.lastAction == nil:
+		m.status = tr("Nothing to repeat yet: set a label, status or field first")
+		return m, nil
+	case readOnly:
+		m.status = errReadOnly.Error()
+		return m, nil
+	case m.selectedNote == nil:
+		return m, nil
+	}
+	if err := m.lastAction.apply(m.selectedNote.path); err != nil {
+		m.status = err.Error()
+		return m, nil
+	}
+	m.status = m.selectedNote.title + ": " + m.lastAction.String()
+	return m, loadNotes
+}
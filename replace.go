@@ -0,0 +1,290 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Find and replace styling
+var (
+	replaceNoteStyle   = lipgloss.NewStyle().Bold(true)
+	replaceCursorStyle = lipgloss.NewStyle().Reverse(true)
+)
+
+// Characters of a line shown before a match, so the match stays in view
+const replaceLead = 30
+
+// replaceMatch is one match of a vault-wide find and replace, accepted or
+// not for replacing
+type replaceMatch struct {
+	path        string // Note the match is in
+	title       string
+	line        int    // Line number of the match, from 1
+	start, end  int    // Byte offsets of the match in the note's text
+	before      string // Text of the line before and after the match
+	after       string
+	found       string // Text matched
+	replacement string // Text it is replaced with, groups expanded
+	accepted    bool
+}
+
+// findMatches finds every match of the pattern in the notes, in the order
+// the notes are given, with the replacement each would get
+func findMatches(notes []note, re *regexp.Regexp, replacement string) ([]replaceMatch, map[string]string) {
+	var matches []replaceMatch
+	texts := map[string]string{}
+	for _, n := range notes {
+		data, err := readNoteFile(n.path)
+		if err != nil {
+			continue
+		}
+		text := string(data)
+		for _, loc := range re.FindAllStringSubmatchIndex(text, -1) {
+			start, end := loc[0], loc[1]
+			if start == end {
+				continue
+			}
+			lineStart := strings.LastIndex(text[:start], "\n") + 1
+			lineEnd := len(text)
+			if i := strings.Index(text[end:], "\n"); i >= 0 {
+				lineEnd = end + i
+			}
+			matches = append(matches, replaceMatch{
+				path:        n.path,
+				title:       n.title,
+				line:        strings.Count(text[:start], "\n") + 1,
+				start:       start,
+				end:         end,
+				before:      text[lineStart:start],
+				after:       text[end:max(end, lineEnd)],
+				found:       text[start:end],
+				replacement: string(re.ExpandString(nil, replacement, text, loc)),
+			})
+			texts[n.path] = text
+		}
+	}
+	return matches, texts
+}
+
+// replaceBackupDir returns the folder the notes changed by one find and
+// replace are copied to first
+func replaceBackupDir(now time.Time) string {
+	return filepath.Join(backupDir(), "replaced-"+now.Format("20060102-150405"))
+}
+
+// applyReplacements makes the accepted replacements, note by note. Each
+// note is first copied as it is on disk into backup, keeping its folder in
+// the vault. A note changed since the matches were found is left alone.
+func applyReplacements(matches []replaceMatch, texts map[string]string, backup string) (int, int, error) {
+	byPath := map[string][]replaceMatch{}
+	var paths []string
+	for _, match := range matches {
+		if !match.accepted {
+			continue
+		}
+		if byPath[match.path] == nil {
+			paths = append(paths, match.path)
+		}
+		byPath[match.path] = append(byPath[match.path], match)
+	}
+
+	replaced, notes := 0, 0
+	var errs []error
+	for _, path := range paths {
+		current, err := readNoteFile(path)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if string(current) != texts[path] {
+			errs = append(errs, fmt.Errorf("%s changed since the search, so it was left alone", filepath.Base(path)))
+			continue
+		}
+		if err := backupFile(path, backup); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		// Replace from the end so earlier offsets stay right
+		text := texts[path]
+		found := byPath[path]
+		sort.Slice(found, func(i, j int) bool { return found[i].start > found[j].start })
+		for _, match := range found {
+			text = text[:match.start] + match.replacement + text[match.end:]
+		}
+		if err := writeNoteFile(path, text); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		replaced += len(found)
+		notes++
+	}
+	return replaced, notes, errors.Join(errs...)
+}
+
+// backupFile copies a note file as it is on disk, encrypted or not, into
+// the backup folder at its place in the vault
+func backupFile(path, backup string) error {
+	rel, err := filepath.Rel(notesDir, path)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	dst := filepath.Join(backup, rel)
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(dst, data, 0600)
+}
+
+// startReplace asks for the text to find in every note
+func (m model) startReplace() (model, tea.Cmd) {
+	return m.openPrompt("replace-find", tr("Find in all notes (regular expression)"), m.replaceFind)
+}
+
+// replacePrompted takes the pattern and then the replacement, and shows
+// every match for accepting or rejecting
+func (m model) replacePrompted(kind, answer string) (tea.Model, tea.Cmd) {
+	if kind == "replace-find" {
+		if _, err := regexp.Compile(answer); err != nil || answer == "" {
+			m.status = tr("Enter a regular expression, such as colou?r")
+			return m, nil
+		}
+		m.replaceFind = answer
+		return m.openPrompt("replace-with", tr("Replace with ($1 for a group)"), "")
+	}
+
+	// Every note is searched, whatever the list is filtered to
+	notes, err := scanNotes()
+	if err != nil && len(notes) == 0 {
+		return m.notify(tr("Nothing replaced"), err)
+	}
+	m.replaceFound, m.replaceTexts = findMatches(notes, regexp.MustCompile(m.replaceFind), answer)
+	if len(m.replaceFound) == 0 {
+		m.status = trf("No notes contain %s", m.replaceFind)
+		return m, nil
+	}
+	m.replaceCursor = 0
+	m.mode = "replace"
+	return m, nil
+}
+
+// updateReplace moves between the matches, accepts or rejects them, and
+// makes the accepted replacements on enter
+func (m model) updateReplace(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	last := len(m.replaceFound) - 1
+	switch msg.String() {
+	case "esc", "q":
+		m.mode = "list"
+		m.replaceFound, m.replaceTexts = nil, nil
+		m.status = tr("Nothing replaced")
+	case "up", "k":
+		m.replaceCursor = max(0, m.replaceCursor-1)
+	case "down", "j":
+		m.replaceCursor = min(last, m.replaceCursor+1)
+	case "y":
+		m.replaceFound[m.replaceCursor].accepted = true
+		m.replaceCursor = min(last, m.replaceCursor+1)
+	case "n":
+		m.replaceFound[m.replaceCursor].accepted = false
+		m.replaceCursor = min(last, m.replaceCursor+1)
+	case " ":
+		m.replaceFound[m.replaceCursor].accepted = !m.replaceFound[m.replaceCursor].accepted
+	case "a", "N":
+		// Accept or reject every match in the note under the cursor
+		path := m.replaceFound[m.replaceCursor].path
+		for i := range m.replaceFound {
+			if m.replaceFound[i].path == path {
+				m.replaceFound[i].accepted = msg.String() == "a"
+			}
+		}
+	case "A":
+		for i := range m.replaceFound {
+			m.replaceFound[i].accepted = true
+		}
+	case "enter":
+		backup := replaceBackupDir(time.Now())
+		replaced, notes, err := applyReplacements(m.replaceFound, m.replaceTexts, backup)
+		m.mode = "list"
+		m.replaceFound, m.replaceTexts = nil, nil
+		if replaced == 0 && err == nil {
+			m.status = tr("Nothing replaced")
+			return m, nil
+		}
+		next, cmd := m.notify(trf("Replaced %d matches in %d notes; the notes as they were are in %s", replaced, notes, backup), err)
+		return next, tea.Batch(cmd, loadNotes)
+	}
+	return m, nil
+}
+
+// replaceLines renders the matches grouped by note, one line each, and
+// returns the line the cursor is on
+func (m model) replaceLines(width int) ([]string, int) {
+	var lines []string
+	cursor := 0
+	for i, match := range m.replaceFound {
+		if i == 0 || m.replaceFound[i-1].path != match.path {
+			if i > 0 {
+				lines = append(lines, "")
+			}
+			lines = append(lines, replaceNoteStyle.Render(match.title))
+		}
+		mark := "[ ]"
+		if match.accepted {
+			mark = "[✓]"
+		}
+		before := []rune(strings.TrimLeft(match.before, " \t"))
+		if len(before) > replaceLead {
+			before = append([]rune("…"), before[len(before)-replaceLead:]...)
+		}
+		line := fmt.Sprintf("%s %4d: %s%s%s", mark, match.line, string(before),
+			diffRemoveStyle.Render(match.found)+"→"+diffAddStyle.Render(match.replacement), match.after)
+		line = truncateLine(line, width)
+		if i == m.replaceCursor {
+			cursor = len(lines)
+			line = replaceCursorStyle.Render(mark) + strings.TrimPrefix(line, mark)
+		}
+		lines = append(lines, line)
+	}
+	return lines, cursor
+}
+
+// replaceSummary counts the matches and those accepted
+func (m model) replaceSummary() string {
+	accepted := 0
+	for _, match := range m.replaceFound {
+		if match.accepted {
+			accepted++
+		}
+	}
+	return trf("%d of %d matches accepted", accepted, len(m.replaceFound))
+}
+
+// replaceHelp describes the keys of the find and replace screen
+func replaceHelp() string {
+	return tr("y:Accept | n:Reject | space:Toggle | a/N:Accept/reject note | A:Accept all | enter:Replace | esc:Cancel")
+}
+
+// replaceView shows the matches full screen
+func (m model) replaceView() string {
+	width := max(m.width-12, 10)
+	lines, cursor := m.replaceLines(width)
+	body := lipgloss.JoinVertical(lipgloss.Left,
+		m.replaceSummary(), "", strings.Join(linearWindow(lines, cursor, m.height-10), "\n"))
+	return docStyle.Render(lipgloss.JoinVertical(lipgloss.Top,
+		splitStyle.Width(m.width-8).Height(m.height-6).Render(body),
+		helpStyle.Render(replaceHelp()),
+	))
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+)
+
+// writeReplaceVault fills an empty vault of the test's own with notes
+func writeReplaceVault(t *testing.T, files map[string]string) []note {
+	t.Helper()
+	oldDir, oldCfg := notesDir, cfg
+	t.Cleanup(func() { notesDir, cfg = oldDir, oldCfg })
+	notesDir, cfg = t.TempDir(), config{}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(notesDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	notes, err := scanNotes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return notes
+}
+
+func TestFindMatches(t *testing.T) {
+	notes := writeReplaceVault(t, map[string]string{
+		"1700000000-One.md": "color and colour\nno match here\nColor\n",
+		"1700000001-Two.md": "nothing\n",
+	})
+	matches, texts := findMatches(notes, regexp.MustCompile(`(?i)colou?r`), "<$0>")
+	want := []struct {
+		line               int
+		before, found, out string
+	}{
+		{1, "", "color", "<color>"},
+		{1, "color and ", "colour", "<colour>"},
+		{3, "", "Color", "<Color>"},
+	}
+	if len(matches) != len(want) {
+		t.Fatalf("got %d matches, want %d: %+v", len(matches), len(want), matches)
+	}
+	for i, w := range want {
+		m := matches[i]
+		if m.line != w.line || m.before != w.before || m.found != w.found || m.replacement != w.out {
+			t.Errorf("match %d = line %d %q|%q -> %q, want line %d %q|%q -> %q", i, m.line, m.before, m.found, m.replacement, w.line, w.before, w.found, w.out)
+		}
+	}
+	if len(texts) != 1 {
+		t.Errorf("kept the text of %d notes, want only the one with matches", len(texts))
+	}
+
+	if empty, _ := findMatches(notes, regexp.MustCompile(`x*`), ""); len(empty) != 0 {
+		t.Errorf("empty matches were kept: %+v", empty)
+	}
+}
+
+func TestApplyReplacements(t *testing.T) {
+	notes := writeReplaceVault(t, map[string]string{
+		"1700000000-One.md": "a cat, a cat, a cat\n",
+		"1700000001-Two.md": "cat\n",
+	})
+	matches, texts := findMatches(notes, regexp.MustCompile(`cat`), "dog")
+	for i := range matches {
+		// Skip the second match in the first note
+		matches[i].accepted = i != 1
+	}
+	// The second note changes after the search, so it is left alone
+	if err := os.WriteFile(filepath.Join(notesDir, "1700000001-Two.md"), []byte("cat and more\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	backup := t.TempDir()
+	replaced, changed, err := applyReplacements(matches, texts, backup)
+	if replaced != 2 || changed != 1 {
+		t.Errorf("replaced %d matches in %d notes, want 2 in 1", replaced, changed)
+	}
+	if err == nil {
+		t.Error("no error for the note changed since the search")
+	}
+	for name, want := range map[string]string{"1700000000-One.md": "a dog, a cat, a dog\n", "1700000001-Two.md": "cat and more\n"} {
+		if got, _ := os.ReadFile(filepath.Join(notesDir, name)); string(got) != want {
+			t.Errorf("%s is %q, want %q", name, got, want)
+		}
+	}
+	saved, err := os.ReadFile(filepath.Join(backup, "1700000000-One.md"))
+	if err != nil || string(saved) != "a cat, a cat, a cat\n" {
+		t.Errorf("backup of One is %q, %v", saved, err)
+	}
+}
@@ -0,0 +1,315 @@
+package main
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// restorePlan lists the files a restore would touch, relative to the vault
+type restorePlan struct {
+	added    []string
+	modified []string
+	deleted  []string
+}
+
+// empty reports whether applying the plan would change nothing
+func (p restorePlan) empty() bool {
+	return len(p.added) == 0 && len(p.modified) == 0 && len(p.deleted) == 0
+}
+
+// readArchive loads every file of a zip or tar.gz backup into memory,
+// asking for the password of an encrypted one
+func readArchive(archivePath string) (map[string][]byte, error) {
+	data, err := os.ReadFile(archivePath)
+	if err != nil {
+		return nil, err
+	}
+	name := archivePath
+	if isEncryptedArchive(data) {
+		password, err := archivePassword(false)
+		if err != nil {
+			return nil, err
+		}
+		if data, err = decryptArchive(data, password); err != nil {
+			return nil, err
+		}
+		name = strings.TrimSuffix(name, encryptedSuffix)
+	}
+
+	switch {
+	case strings.HasSuffix(name, "."+formatZip):
+		return readZip(data)
+	case strings.HasSuffix(name, "."+formatTarGz), strings.HasSuffix(name, ".tgz"):
+		return readTarGz(data)
+	default:
+		return nil, fmt.Errorf("unrecognised archive %q (expected .zip or .tar.gz)", archivePath)
+	}
+}
+
+// readZip loads the files of a zip archive
+func readZip(archive []byte) (map[string][]byte, error) {
+	zr, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	if err != nil {
+		return nil, err
+	}
+
+	files := make(map[string][]byte)
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		name, err := cleanArchiveName(f.Name)
+		if err != nil {
+			return nil, err
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		data, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return nil, err
+		}
+		files[name] = data
+	}
+	return files, nil
+}
+
+// readTarGz loads the regular files of a gzip-compressed tarball
+func readTarGz(archive []byte) (map[string][]byte, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+
+	files := make(map[string][]byte)
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		name, err := cleanArchiveName(header.Name)
+		if err != nil {
+			return nil, err
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, err
+		}
+		files[name] = data
+	}
+	return files, nil
+}
+
+// cleanArchiveName rejects entries that would escape the vault when extracted
+func cleanArchiveName(name string) (string, error) {
+	clean := path.Clean(strings.TrimPrefix(filepath.ToSlash(name), "./"))
+	if clean == "." || path.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, "../") {
+		return "", fmt.Errorf("refusing unsafe archive entry %q", name)
+	}
+	return clean, nil
+}
+
+// planRestore compares archive contents against the current vault
+func planRestore(archive map[string][]byte) (restorePlan, error) {
+	var plan restorePlan
+	current := make(map[string]bool)
+
+	err := walkVault(backupDir(), func(p, rel string, info fs.FileInfo) error {
+		current[rel] = true
+		if rel == auditLogFile {
+			return nil
+		}
+		data, ok := archive[rel]
+		if !ok {
+			plan.deleted = append(plan.deleted, rel)
+			return nil
+		}
+		existing, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(existing, data) {
+			plan.modified = append(plan.modified, rel)
+		}
+		return nil
+	})
+	if err != nil {
+		return plan, err
+	}
+
+	for name := range archive {
+		if !current[name] && name != auditLogFile {
+			plan.added = append(plan.added, name)
+		}
+	}
+	sort.Strings(plan.added)
+	sort.Strings(plan.modified)
+	sort.Strings(plan.deleted)
+	return plan, nil
+}
+
+// applyRestore writes archive contents into the vault according to plan,
+// logging each note it changes. Notes are stored the way a save stores
+// them, sealed if and only if the vault is encrypted now, whichever way
+// they were archived; other files are copied as they are.
+func applyRestore(archive map[string][]byte, plan restorePlan) error {
+	for _, group := range [][]string{plan.added, plan.modified} {
+		for _, rel := range group {
+			dst := filepath.Join(notesDir, filepath.FromSlash(rel))
+			if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+				return err
+			}
+			data := archive[rel]
+			if !isNoteExt(path.Ext(rel)) {
+				if err := writeFileAtomic(dst, data, 0644); err != nil {
+					return err
+				}
+				continue
+			}
+			if isSealed(data) {
+				key, err := vaultKey()
+				if err != nil {
+					return err
+				}
+				if data, err = unseal(key, data); err != nil {
+					return fmt.Errorf("%s: %w", rel, err)
+				}
+			}
+			previous, _ := readNoteFile(dst)
+			if err := storeNoteFile(dst, data); err != nil {
+				return err
+			}
+			restored, _ := readNoteFile(dst)
+			recordChange(auditRestore, dst, string(previous), string(restored))
+		}
+	}
+	for _, rel := range plan.deleted {
+		path := filepath.Join(notesDir, filepath.FromSlash(rel))
+		previous, _ := readNoteFile(path)
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+		recordChange(auditDelete, path, string(previous), "")
+	}
+	return nil
+}
+
+// findArchivedNote picks the archive entry matching a note title or filename
+func findArchivedNote(archive map[string][]byte, query string) (string, error) {
+	var matches []string
+	for name := range archive {
+		base := path.Base(name)
+		title, _, ok := parseNoteFilename(base)
+		if name == query || base == query || (ok && strings.EqualFold(title, query)) {
+			matches = append(matches, name)
+		}
+	}
+	sort.Strings(matches)
+
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("no note matching %q in archive", query)
+	case 1:
+		return matches[0], nil
+	default:
+		return "", fmt.Errorf("%q is ambiguous, use one of: %s", query, strings.Join(matches, ", "))
+	}
+}
+
+// printRestorePlan shows what a restore would change
+func printRestorePlan(w io.Writer, plan restorePlan) {
+	for _, rel := range plan.added {
+		fmt.Fprintln(w, "  new       ", rel)
+	}
+	for _, rel := range plan.modified {
+		fmt.Fprintln(w, "  modified  ", rel)
+	}
+	for _, rel := range plan.deleted {
+		fmt.Fprintln(w, "  deleted   ", rel)
+	}
+	fmt.Fprintf(w, "%d new, %d modified, %d deleted\n", len(plan.added), len(plan.modified), len(plan.deleted))
+}
+
+// confirm asks a yes/no question on the terminal
+func confirm(question string) bool {
+	fmt.Printf("%s [y/N] ", question)
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
+// runRestore implements the `gleaner restore <archive>` command
+func runRestore(args []string) error {
+	flags := flag.NewFlagSet("restore", flag.ContinueOnError)
+	single := flags.String("note", "", "restore only the note with this title or filename")
+	dryRun := flags.Bool("dry-run", false, "only show what would change")
+	yes := flags.Bool("yes", false, "apply without asking for confirmation")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return fmt.Errorf("usage: gleaner restore [--note title] [--dry-run] [--yes] <archive>")
+	}
+	if readOnly && !*dryRun {
+		return errReadOnly
+	}
+
+	archive, err := readArchive(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	var plan restorePlan
+	if *single != "" {
+		name, err := findArchivedNote(archive, *single)
+		if err != nil {
+			return err
+		}
+		existing, err := os.ReadFile(filepath.Join(notesDir, filepath.FromSlash(name)))
+		switch {
+		case err != nil:
+			plan.added = []string{name}
+		case !bytes.Equal(existing, archive[name]):
+			plan.modified = []string{name}
+		}
+	} else if plan, err = planRestore(archive); err != nil {
+		return err
+	}
+
+	printRestorePlan(os.Stdout, plan)
+	if plan.empty() || *dryRun {
+		return nil
+	}
+	if !*yes && !confirm("Apply these changes?") {
+		fmt.Println("Restore cancelled")
+		return nil
+	}
+	if err := applyRestore(archive, plan); err != nil {
+		return err
+	}
+	fmt.Println("Restore complete")
+	return nil
+}
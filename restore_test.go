@@ -0,0 +1,95 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestPlanAndApplyRestore(t *testing.T) {
+	oldDir, oldCfg := notesDir, cfg
+	t.Cleanup(func() { notesDir, cfg = oldDir, oldCfg })
+	notesDir, cfg = t.TempDir(), config{BackupDir: t.TempDir()}
+	for rel, content := range map[string]string{
+		"1700000000-Same.md":    "same\n",
+		"1700000001-Changed.md": "edited since\n",
+		"1700000002-Newer.md":   "written after the backup\n",
+	} {
+		if err := os.WriteFile(filepath.Join(notesDir, rel), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	archive := map[string][]byte{
+		"1700000000-Same.md":          []byte("same\n"),
+		"1700000001-Changed.md":       []byte("as backed up\n"),
+		"projects/1700000003-Gone.md": []byte("deleted since\n"),
+		"attachments/logo.png":        []byte("png"),
+	}
+
+	plan, err := planRestore(archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(plan.added, []string{"attachments/logo.png", "projects/1700000003-Gone.md"}) ||
+		!slices.Equal(plan.modified, []string{"1700000001-Changed.md"}) ||
+		!slices.Equal(plan.deleted, []string{"1700000002-Newer.md"}) {
+		t.Fatalf("plan is %+v", plan)
+	}
+
+	if err := applyRestore(archive, plan); err != nil {
+		t.Fatal(err)
+	}
+	for rel, want := range archive {
+		if got, err := os.ReadFile(filepath.Join(notesDir, filepath.FromSlash(rel))); string(got) != string(want) {
+			t.Errorf("%s is %q, %v; want %q", rel, got, err, want)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(notesDir, "1700000002-Newer.md")); !os.IsNotExist(err) {
+		t.Errorf("note written after the backup kept: %v", err)
+	}
+	if plan, err := planRestore(archive); err != nil || !plan.empty() {
+		t.Errorf("vault differs from the archive after restoring: %+v, %v", plan, err)
+	}
+}
+
+func TestCleanArchiveName(t *testing.T) {
+	tests := []struct {
+		in, want string
+		wantErr  bool
+	}{
+		{"1700000000-Plan.md", "1700000000-Plan.md", false},
+		{"./projects//1700000001-Site.md", "projects/1700000001-Site.md", false},
+		{"../outside.md", "", true},
+		{"projects/../../outside.md", "", true},
+		{"/etc/passwd", "", true},
+		{".", "", true},
+	}
+	for _, tt := range tests {
+		got, err := cleanArchiveName(tt.in)
+		if got != tt.want || (err != nil) != tt.wantErr {
+			t.Errorf("cleanArchiveName(%q) = %q, %v", tt.in, got, err)
+		}
+	}
+}
+
+func TestFindArchivedNote(t *testing.T) {
+	archive := map[string][]byte{
+		"1700000000-Plan.md":          nil,
+		"projects/1700000001-Plan.md": nil,
+		"1700000002-Site.md":          nil,
+	}
+	if got, err := findArchivedNote(archive, "site"); got != "1700000002-Site.md" || err != nil {
+		t.Errorf("by title: %q, %v", got, err)
+	}
+	if got, err := findArchivedNote(archive, "projects/1700000001-Plan.md"); got != "projects/1700000001-Plan.md" || err != nil {
+		t.Errorf("by path: %q, %v", got, err)
+	}
+	if _, err := findArchivedNote(archive, "Plan"); err == nil || !strings.Contains(err.Error(), "ambiguous") {
+		t.Errorf("two notes titled Plan: %v", err)
+	}
+	if _, err := findArchivedNote(archive, "Nope"); err == nil {
+		t.Error("no error for a missing note")
+	}
+}
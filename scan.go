@@ -0,0 +1,146 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+)
+
+// notesErrMsg carries the notes that could be loaded along with the error
+// for the folders that could not be read
+type notesErrMsg struct {
+	notes []note
+	err   error
+}
+
+// scanNotes reads every note in the vault and its subfolders. The notes
+// directory itself may be a symlink, e.g. to a mounted drive, and symlinked
+// subfolders are followed; each real folder is read only once, so links
+// that loop back on themselves end the walk instead of recursing forever.
+// Hidden folders such as .git and paths matched by a .gleanerignore file
+// are skipped. Folders that cannot be read are reported in the error while
+// the notes found elsewhere are still returned.
+func scanNotes() ([]note, error) {
+	root, err := filepath.EvalSymlinks(notesDir)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open notes directory: %w", err)
+	}
+	if err := checkVaultKey(); err != nil {
+		return nil, err
+	}
+
+	var notes []note
+	var errs []error
+	visited := map[string]bool{}
+
+	var walk func(dir, real, rel string, rules []ignoreRule)
+	walk = func(dir, real, rel string, rules []ignoreRule) {
+		if visited[real] {
+			return
+		}
+		visited[real] = true
+
+		local, err := readIgnoreFile(dir, rel)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		// Copy so sibling folders do not share this folder's rules
+		rules = append(rules[:len(rules):len(rules)], local...)
+
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			errs = append(errs, err)
+			return
+		}
+		for _, entry := range entries {
+			if strings.HasPrefix(entry.Name(), ".") {
+				continue
+			}
+			path := filepath.Join(dir, entry.Name())
+			entryRel := filepath.ToSlash(filepath.Join(rel, entry.Name()))
+
+			// Stat follows symlinks, so linked folders and notes look like
+			// the real thing; a dangling link fails here
+			info, err := os.Stat(path)
+			if err != nil {
+				if !ignored(rules, entryRel, false) {
+					errs = append(errs, err)
+				}
+				continue
+			}
+			if ignored(rules, entryRel, info.IsDir()) {
+				continue
+			}
+			if info.IsDir() {
+				target, err := filepath.EvalSymlinks(path)
+				if err != nil {
+					errs = append(errs, err)
+					continue
+				}
+				walk(path, target, entryRel, rules)
+				continue
+			}
+
+			title, timestamp, ok := parseNoteFilename(entry.Name())
+			if !ok {
+				continue
+			}
+			// A note that cannot be read is still listed, marked with
+			// the reason, rather than failing the whole load
+			content, err := readNoteText(path)
+			n := note{
+				title:      title,
+				path:       path,
+				createdAt:  timestamp,
+				modifiedAt: info.ModTime().Unix(),
+				readErr:    err,
+			}
+			n.readMetadata(content)
+			notes = append(notes, n)
+		}
+	}
+	walk(notesDir, root, "", nil)
+	return notes, errors.Join(errs...)
+}
+
+// errNotText marks notes whose bytes are not UTF-8 text, such as binary
+// files given a note extension
+var errNotText = errors.New("not UTF-8 text")
+
+// readNoteText reads a note as text, failing for files that are not UTF-8
+func readNoteText(path string) (string, error) {
+	content, err := readNoteFile(path)
+	if err != nil {
+		return "", err
+	}
+	if !utf8.Valid(content) {
+		return "", errNotText
+	}
+	return string(content), nil
+}
+
+// noteText returns what the content pane shows for a note: its text, or
+// why it cannot be read
+func noteText(path string) string {
+	content, err := readNoteText(path)
+	if err != nil {
+		return trf("This note cannot be read: %v", err)
+	}
+	return content
+}
+
+// notesForCommand loads the notes for a subcommand. Unreadable folders are
+// only a warning as long as some notes could be read.
+func notesForCommand() ([]note, error) {
+	notes, err := scanNotes()
+	if err != nil && len(notes) == 0 {
+		return nil, err
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	return notes, nil
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// scratchPath returns the file holding the scratchpad. It lives with the
+// config rather than in a vault, so it is shared by every vault and stays
+// writable in read-only mode.
+func scratchPath() string {
+	return filepath.Join(configDir(), "scratchpad.md")
+}
+
+// newScratchArea creates the editor used for the scratchpad
+func newScratchArea() textarea.Model {
+	ta := textarea.New()
+	ta.Placeholder = tr("Scratchpad: saved as you type")
+	ta.ShowLineNumbers = false
+	ta.CharLimit = 0
+	ta.MaxHeight = 0
+	ta.Prompt = editorPrompt()
+	return ta
+}
+
+// toggleScratch opens the scratchpad over whatever is on screen, or closes it
+// and returns to where it was opened from
+func (m model) toggleScratch() (model, tea.Cmd) {
+	if m.mode == "scratch" {
+		m.mode = m.scratchReturn
+		m.scratch.Blur()
+		return m.resize(), nil
+	}
+
+	content, _ := os.ReadFile(scratchPath())
+	m.scratch.SetValue(string(content))
+	m.scratchReturn = m.mode
+	m.mode = "scratch"
+	m.hideContent = false
+	cmd := m.scratch.Focus()
+	return m.resize(), cmd
+}
+
+// updateScratch edits the scratchpad, saving after every change
+func (m model) updateScratch(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if msg.Type == tea.KeyEsc || msg.String() == "alt+x" {
+		return m.toggleScratch()
+	}
+
+	before := m.scratch.Value()
+	var cmd tea.Cmd
+	m.scratch, cmd = m.scratch.Update(msg)
+	if m.scratch.Value() != before {
+		if err := saveScratch(m.scratch.Value()); err != nil {
+			m.status = trf("Could not save scratchpad: %v", err)
+		}
+	}
+	return m, cmd
+}
+
+// saveScratch writes the scratchpad to disk
+func saveScratch(content string) error {
+	if err := os.MkdirAll(configDir(), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(scratchPath(), []byte(content), 0644)
+}
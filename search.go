@@ -0,0 +1,82 @@
+package main
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Parts of a note the list filter searches
+const (
+	scopeTitle = iota
+	scopeContent
+	scopeTags
+	scopeAll
+)
+
+// Names of the search scopes, in the order ctrl+t cycles through them
+var scopeNames = []string{"titles", "contents", "tags", "everything"}
+
+// Scope the list filter currently searches
+var searchScope = scopeTitle
+
+// searchText returns the text of a note the current scope searches
+func (n note) searchText() string {
+	switch searchScope {
+	case scopeTitle:
+		return strings.Join(append([]string{n.title}, n.aliases...), " ")
+	case scopeContent:
+		return n.body
+	case scopeTags:
+		return strings.Join(n.tags, " ")
+	default:
+		return n.title + " " + strings.Join(n.aliases, " ") + "\n" + strings.Join(n.tags, " ") + "\n" + n.body
+	}
+}
+
+// scopePlaceholder describes the active scope in the empty filter input
+func scopePlaceholder() string {
+	return trf("search %s (ctrl+t to change)", tr(scopeNames[searchScope]))
+}
+
+// scopeFilter matches titles and tags fuzzily, and note bodies by substring,
+// since a fuzzy match over a whole note finds nearly everything
+func scopeFilter(term string, targets []string) []list.Rank {
+	if searchScope == scopeTitle || searchScope == scopeTags {
+		return list.DefaultFilter(term, targets)
+	}
+
+	term = strings.ToLower(term)
+	var ranks []list.Rank
+	for i, target := range targets {
+		lower := strings.ToLower(target)
+		at := strings.Index(lower, term)
+		if at < 0 {
+			continue
+		}
+		// Matched positions are counted in runes
+		start := utf8.RuneCountInString(lower[:at])
+		matched := make([]int, utf8.RuneCountInString(term))
+		for k := range matched {
+			matched[k] = start + k
+		}
+		ranks = append(ranks, list.Rank{Index: i, MatchedIndexes: matched})
+	}
+	return ranks
+}
+
+// highlightMatches reports whether filter matches fall on the note title
+func highlightMatches() bool {
+	return searchScope == scopeAll || searchScope == scopeTitle
+}
+
+// cycleSearchScope switches the filter to the next scope and searches again
+func (m model) cycleSearchScope() (model, tea.Cmd) {
+	searchScope = (searchScope + 1) % len(scopeNames)
+	m.list.FilterInput.Placeholder = scopePlaceholder()
+	m.status = trf("Searching %s", tr(scopeNames[searchScope]))
+	cmd := m.list.SetItems(itemsFromNotes(m.notes))
+	return m, cmd
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// secretPattern recognises one kind of credential
+type secretPattern struct {
+	name    string
+	pattern *regexp.Regexp
+}
+
+// Credentials looked for before a note leaves the machine
+var secretPatterns = []secretPattern{
+	{"private key", regexp.MustCompile(`-----BEGIN (?:RSA |EC |DSA |OPENSSH |ENCRYPTED |PGP )?PRIVATE KEY(?: BLOCK)?-----`)},
+	{"AWS access key", regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{"AWS secret key", regexp.MustCompile(`(?i)aws_?secret_?access_?key\s*[:=]\s*["']?[A-Za-z0-9/+]{40}`)},
+	{"GitHub token", regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,})`)},
+	{"Slack token", regexp.MustCompile(`\bxox[abprs]-[A-Za-z0-9-]{10,}`)},
+	{"Stripe key", regexp.MustCompile(`\b[sr]k_live_[A-Za-z0-9]{20,}`)},
+	{"Google API key", regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}`)},
+	{"API key", regexp.MustCompile(`\bsk-[A-Za-z0-9_-]{32,}`)},
+	{"password or token", regexp.MustCompile(`(?i)\b(?:api[_-]?key|secret|token|passw(?:or)?d)\s*[:=]\s*["']?[A-Za-z0-9/+_.-]{12,}`)},
+}
+
+// findSecrets names the kinds of credentials found in content, each once
+func findSecrets(content string) []string {
+	var found []string
+	for _, p := range secretPatterns {
+		if p.pattern.MatchString(content) {
+			found = append(found, tr(p.name))
+		}
+	}
+	return found
+}
+
+// notesWithSecrets names the notes that look like they hold credentials,
+// each with what was found in it. Sealed notes are skipped, since they
+// leave the vault encrypted.
+func notesWithSecrets(notes []note) []string {
+	var found []string
+	for _, n := range notes {
+		data, err := os.ReadFile(n.path)
+		if err != nil || isSealed(data) {
+			continue
+		}
+		if kinds := findSecrets(string(data)); len(kinds) > 0 {
+			found = append(found, fmt.Sprintf("%s (%s)", n.title, strings.Join(kinds, ", ")))
+		}
+	}
+	return found
+}
+
+// secretsSummary lists the first few notes found holding credentials
+func secretsSummary(found []string) string {
+	if len(found) <= 3 {
+		return strings.Join(found, "; ")
+	}
+	return strings.Join(found[:3], "; ") + "; " + trf("and %d more", len(found)-3)
+}
+
+// share sends the selected note out by gist, paste, clipboard HTML or QR
+// code. When the note looks like it holds credentials, it asks for "yes"
+// first; confirmed is set once it has.
+func (m model) share(action string, confirmed bool) (model, tea.Cmd) {
+	if !confirmed {
+		if found := findSecrets(m.textarea.Value()); len(found) > 0 {
+			m.pendingShare = action
+			return m.openPrompt("confirm-share", trf("This note looks like it contains: %s. Type yes to share it anyway", strings.Join(found, ", ")), "")
+		}
+	}
+
+	switch action {
+	case "gist":
+		m.status = trf("Sharing %s...", m.selectedNote.title)
+		return m, shareGist(*m.selectedNote)
+	case "paste":
+		m.status = trf("Uploading to %s...", pasteService())
+		return m, sharePaste(*m.selectedNote)
+	case "html":
+		m.status = tr("Copying...")
+		return m, copyHTML(m.textarea.Value(), m.editorExt())
+	case "qr":
+		return m.showQR(), nil
+	}
+	return m, nil
+}
+
+// confirmShare goes ahead with the share held back by a secrets warning
+// when the answer is yes
+func (m model) confirmShare(answer string) (tea.Model, tea.Cmd) {
+	action := m.pendingShare
+	m.pendingShare = ""
+	if !answeredYes(answer) {
+		m.status = tr("Not shared")
+		return m, nil
+	}
+	return m.share(action, true)
+}
+
+// confirmBackup goes ahead with the backup held back by a secrets warning
+// when the answer is yes
+func (m model) confirmBackup(answer string) (tea.Model, tea.Cmd) {
+	if !answeredYes(answer) {
+		m.status = tr("No backup written")
+		return m, nil
+	}
+	m.status = tr("Creating backup...")
+	return m, backupVault(true)
+}
+
+// confirmPublish publishes the note held back by a secrets warning when the
+// answer is yes
+func (m model) confirmPublish(answer string) (tea.Model, tea.Cmd) {
+	if !answeredYes(answer) {
+		m.status = tr("Not published")
+		return m, nil
+	}
+	return m.cycleStatus(true)
+}
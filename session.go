@@ -0,0 +1,105 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/charmbracelet/bubbles/list"
+)
+
+// sessionState is what gleaner remembers between runs
+type sessionState struct {
+	HideList    bool `json:"hide_list,omitempty"`
+	HideContent bool `json:"hide_content,omitempty"`
+	Preview     bool `json:"preview,omitempty"`
+
+	Vaults map[string]vaultSession `json:"vaults,omitempty"` // Keyed by notes directory
+}
+
+// vaultSession is where the user was in one notes directory
+type vaultSession struct {
+	Note         string `json:"note,omitempty"`          // Path of the selected note
+	Filter       string `json:"filter,omitempty"`        // Text the list was filtered by
+	Line         int    `json:"line,omitempty"`          // Cursor line in the content pane
+	ContentFocus bool   `json:"content_focus,omitempty"` // Content pane had focus
+}
+
+// sessionPath returns the file the session is kept in
+func sessionPath() string {
+	return filepath.Join(configDir(), "session.json")
+}
+
+// loadSession reads the saved session, returning an empty one when there is
+// none or it cannot be read
+func loadSession() sessionState {
+	var state sessionState
+	data, err := os.ReadFile(sessionPath())
+	if err == nil {
+		json.Unmarshal(data, &state)
+	}
+	return state
+}
+
+// saveSession records the layout and the position in the open vault
+func (m model) saveSession() error {
+	state := loadSession()
+	state.HideList = m.hideList
+	state.HideContent = m.hideContent
+	state.Preview = m.preview
+	if state.Vaults == nil {
+		state.Vaults = make(map[string]vaultSession)
+	}
+
+	var current vaultSession
+	if m.selectedNote != nil {
+		current.Note = m.selectedNote.path
+		current.Line, _ = cursorPos(m.textarea)
+	}
+	if m.list.FilterState() != list.Unfiltered {
+		current.Filter = m.list.FilterValue()
+	}
+	current.ContentFocus = m.contentFocus
+	state.Vaults[notesDir] = current
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(configDir(), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(sessionPath(), data, 0644)
+}
+
+// restoreSession brings back the layout and, once the notes have loaded, the
+// note, filter and position the user left the open vault at
+func (m model) restoreSession() model {
+	state := loadSession()
+	m.hideList = state.HideList
+	m.hideContent = state.HideContent
+	m.preview = state.Preview
+
+	if v, ok := state.Vaults[notesDir]; ok {
+		if v.Note != "" {
+			m.selectedNote = &note{path: v.Note}
+		}
+		m.search = v.Filter
+		m.resumeLine = v.Line
+		m = m.focusContent(v.ContentFocus)
+	}
+	return m
+}
+
+// resumePosition moves the content pane back to the line it was left at
+func (m model) resumePosition() model {
+	wasFocused := m.textarea.Focused()
+	m.textarea.Focus()
+	moveCursor(&m.textarea, m.resumeLine, 0)
+	m.textarea, _ = m.textarea.Update(nil) // Scroll the line into view
+	if !wasFocused {
+		m.textarea.Blur()
+	}
+	m.resumeLine = 0
+	return m
+}
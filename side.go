@@ -0,0 +1,61 @@
+package main
+
+import (
+	"github.com/charmbracelet/bubbles/viewport"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Title of the note shown alongside the main one
+var sideTitleStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("212"))
+
+// toggleSideNote keeps the selected note open next to the content pane, so
+// other notes can be read or edited beside it, or closes it again
+func (m model) toggleSideNote() model {
+	if m.sideNote != nil {
+		m.sideNote = nil
+		return m.resize()
+	}
+	if m.selectedNote == nil {
+		return m
+	}
+
+	content, err := readNoteFile(m.selectedNote.path)
+	if err != nil {
+		m.status = err.Error()
+		return m
+	}
+	n := *m.selectedNote
+	m.sideNote = &n
+	m.sideContent = string(content)
+	m.side = viewport.New(0, 0)
+	m.preview = false // Both would claim the right half of the content pane
+	m.hideContent = false
+	m.status = trf("Showing %s alongside; alt+b closes it", n.title)
+	return m.resize()
+}
+
+// sideWidth returns the width left for the side note once the editor has
+// taken half of the content pane
+func sideWidth(contentWidth int) int {
+	return max(0, contentWidth-contentWidth/2-3)
+}
+
+// layoutSide sizes the side note and wraps its text to the new width
+func (m model) layoutSide(contentWidth int) model {
+	if m.sideNote == nil {
+		return m
+	}
+	width := sideWidth(contentWidth)
+	m.side.Width = width
+	m.side.Height = max(0, m.textarea.Height()-1)
+	m.side.SetContent(lipgloss.NewStyle().Width(width).Render(m.sideContent))
+	return m
+}
+
+// sideView renders the side note under its title
+func (m model) sideView() string {
+	return lipgloss.JoinVertical(lipgloss.Left,
+		sideTitleStyle.Render(truncateLine(m.sideNote.title, m.side.Width)),
+		m.side.View(),
+	)
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/atotto/clipboard"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Note type, set with "type: snippet" in the frontmatter, for notes that
+// hold a piece of code in the language named by their "language" field
+const noteTypeSnippet = "snippet"
+
+// Badge showing a snippet's language in the list
+var languageBadge = badgeStyle.Foreground(lipgloss.Color("0")).Background(lipgloss.Color("75"))
+
+// frontmatterLines counts the lines taken by a note's frontmatter block,
+// including both "---" delimiters
+func frontmatterLines(content string) int {
+	fields, body := parseFrontmatter(content)
+	if fields == nil && body == content {
+		return 0
+	}
+	return strings.Count(content, "\n") - strings.Count(body, "\n")
+}
+
+// renderSnippet renders a snippet note for the content pane. The code is
+// highlighted in the note's language: inside the code fences when the body
+// has any, and the whole body otherwise.
+func renderSnippet(src, language string, width int) renderedDoc {
+	var doc renderedDoc
+	width = max(width, 10)
+	syn := syntaxFor(language)
+	header := frontmatterLines(src)
+	_, body := parseFrontmatter(src)
+	fenced := strings.Contains(body, "```")
+	inCode := false
+
+	for i, line := range strings.Split(src, "\n") {
+		doc.srcLine = append(doc.srcLine, len(doc.lines))
+		switch {
+		case i < header:
+			doc.lines = append(doc.lines, previewDimStyle.Render(truncateLine(line, width)))
+		case strings.HasPrefix(strings.TrimSpace(line), "```"):
+			inCode = !inCode
+			doc.lines = append(doc.lines, previewDimStyle.Render(truncateLine(line, width)))
+		case inCode || !fenced:
+			doc.lines = append(doc.lines, truncateLine(highlightLine(line, syn), width))
+		default:
+			doc.lines = append(doc.lines, wrapLines(renderLine(line, width), width)...)
+		}
+	}
+	return doc
+}
+
+// snippetView renders the selected snippet note, following the scrolling
+// of the hidden editor like the preview does
+func (m model) snippetView(width, height int) string {
+	content := m.textarea.Value()
+	fields, _ := parseFrontmatter(content)
+	doc := renderSnippet(content, metaValue(fields, "language"), width)
+	row, _ := cursorPos(m.textarea)
+	return doc.previewWindow(row, row-m.editorTop, height)
+}
+
+// noteCode returns the code of a note: the first fenced code block, or for
+// a snippet without fences its whole body
+func noteCode(content string, snippet bool) string {
+	_, body := parseFrontmatter(content)
+	var code []string
+	inCode := false
+	for _, line := range strings.Split(body, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			if inCode {
+				return strings.Join(code, "\n")
+			}
+			inCode = true
+			continue
+		}
+		if inCode {
+			code = append(code, line)
+		}
+	}
+	if snippet {
+		return strings.Trim(body, "\n")
+	}
+	return ""
+}
+
+// copyCode copies the code of the selected note to the clipboard, read
+// from the file, since the editor turns tabs into spaces
+func (m model) copyCode() model {
+	content, err := readNoteFile(m.selectedNote.path)
+	if err != nil {
+		m.status = err.Error()
+		return m
+	}
+	code := noteCode(string(content), m.selectedNote.kind == noteTypeSnippet)
+	if strings.TrimSpace(code) == "" {
+		m.status = tr("No code in this note")
+		return m
+	}
+	if err := clipboard.WriteAll(code); err != nil {
+		m.status = trf("Copy failed: %v", err)
+		return m
+	}
+	m.status = trf("Copied %d lines of code", strings.Count(code, "\n")+1)
+	return m
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Marks where the cursor lands after a snippet expands
+const snippetCursor = "$|"
+
+// expandSnippetText fills in date placeholders in a snippet body
+func expandSnippetText(text string) string {
+	now := localNow()
+	return strings.NewReplacer(
+		"{{date}}", now.Format("2006-01-02"),
+		"{{time}}", now.Format("15:04"),
+	).Replace(text)
+}
+
+// snippetBeforeCursor returns the snippet whose abbreviation ends at the
+// cursor, and where on the line the abbreviation starts
+func (m model) snippetBeforeCursor() (string, int, bool) {
+	if len(cfg.Snippets) == 0 {
+		return "", 0, false
+	}
+	lines := strings.Split(m.textarea.Value(), "\n")
+	row, col := cursorPos(m.textarea)
+	line := []rune(lines[row])
+	start := col
+	for start > 0 && !unicode.IsSpace(line[start-1]) {
+		start--
+	}
+	body, ok := cfg.Snippets[string(line[start:col])]
+	return body, start, ok
+}
+
+// expandSnippet replaces an abbreviation just before the cursor with its
+// snippet. It reports false when no abbreviation matches.
+func (m model) expandSnippet() (model, bool) {
+	body, start, ok := m.snippetBeforeCursor()
+	if !ok {
+		return m, false
+	}
+	lines := strings.Split(m.textarea.Value(), "\n")
+	row, col := cursorPos(m.textarea)
+	line := []rune(lines[row])
+
+	// Split the expansion around the cursor placeholder
+	body = expandSnippetText(body)
+	before, after, hasCursor := strings.Cut(body, snippetCursor)
+	if !hasCursor {
+		before, after = body, ""
+	}
+
+	prefix := string(line[:start]) + before
+	lines[row] = prefix + after + string(line[col:])
+	value := strings.Join(lines, "\n")
+
+	// Place the cursor at the placeholder, accounting for multi-line snippets
+	prefixLines := strings.Split(prefix, "\n")
+	cursorRow := row + len(prefixLines) - 1
+	cursorCol := len([]rune(prefixLines[len(prefixLines)-1]))
+	replaceValue(&m.textarea, value, cursorRow, cursorCol)
+	return m, true
+}
@@ -0,0 +1,32 @@
+package main
+
+import "testing"
+
+func TestSnippetExpansion(t *testing.T) {
+	d := startDriver(t)
+	cfg.Snippets = map[string]string{
+		"sig":  "Regards",
+		"bold": "**$|**",
+		"todo": "- [ ] $|\n- [ ] ",
+		"day":  "{{date}}",
+	}
+	must(t, d, d.press("ctrl+n", "tab"))
+	// Space both expands an abbreviation and is typed itself
+	d.typeText("a sig b bold x")
+	if got, want := d.m.(model).textarea.Value(), "a Regards b **x**"; got != want {
+		t.Errorf("editor holds %q, want %q", got, want)
+	}
+
+	must(t, d, d.press("ctrl+a", "ctrl+k"))
+	d.typeText("todo first")
+	if got, want := d.m.(model).textarea.Value(), "- [ ] first\n- [ ] "; got != want {
+		t.Errorf("editor holds %q, want %q", got, want)
+	}
+
+	// Abbreviations inside a word are left alone
+	must(t, d, d.press("ctrl+end"))
+	d.typeText("xsig day ")
+	if got, want := d.m.(model).textarea.Value(), "- [ ] first\n- [ ] xsig "+localNow().Format("2006-01-02")+" "; got != want {
+		t.Errorf("editor holds %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// vaultState is state kept in a file of the config directory, keyed by
+// notes directory and then by the note, card or section it belongs to
+type vaultState[V any] map[string]map[string]V
+
+// loadState reads a state file, returning empty state when there is none
+func loadState[V any](path string) vaultState[V] {
+	state := make(vaultState[V])
+	if data, err := os.ReadFile(path); err == nil {
+		json.Unmarshal(data, &state)
+	}
+	return state
+}
+
+// updateState changes one entry of this vault's state and saves the file,
+// holding mu so updates made at the same time are not lost. It returns the
+// new value of the entry.
+func updateState[V any](mu *sync.Mutex, path, id string, update func(V) V) (V, error) {
+	mu.Lock()
+	defer mu.Unlock()
+	state := loadState[V](path)
+	if state[notesDir] == nil {
+		state[notesDir] = make(map[string]V)
+	}
+	value := update(state[notesDir][id])
+	state[notesDir][id] = value
+	return value, writeState(path, state)
+}
+
+// writeState saves a state file in the config directory
+func writeState(path string, state any) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
@@ -0,0 +1,276 @@
+package main
+
+import (
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Stats screen styling
+var (
+	statsHeadingStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("212"))
+	statsLabelStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+
+	// Heatmap shades from no activity to the busiest days
+	heatmapLevels = []lipgloss.Style{
+		lipgloss.NewStyle().Foreground(lipgloss.Color("237")),
+		lipgloss.NewStyle().Foreground(lipgloss.Color("22")),
+		lipgloss.NewStyle().Foreground(lipgloss.Color("28")),
+		lipgloss.NewStyle().Foreground(lipgloss.Color("34")),
+		lipgloss.NewStyle().Foreground(lipgloss.Color("40")),
+	}
+)
+
+// Weeks shown in the activity heatmap
+const heatmapWeeks = 53
+
+// dayKey identifies a calendar day in the display zone
+func dayKey(t time.Time) string {
+	return t.In(displayZone).Format("2006-01-02")
+}
+
+// writingActivity counts note creations and edits per day. Edits come from
+// the git history when the vault is a repository, and otherwise from the
+// last modification time of each note.
+func writingActivity(notes []note) map[string]int {
+	activity := make(map[string]int)
+	for _, n := range notes {
+		activity[dayKey(noteTime(n.createdAt))]++
+	}
+
+	if commits, ok := gitCommitTimes(); ok {
+		for _, t := range commits {
+			activity[dayKey(t)]++
+		}
+		return activity
+	}
+	for _, n := range notes {
+		if n.modifiedAt > 0 && dayKey(noteTime(n.modifiedAt)) != dayKey(noteTime(n.createdAt)) {
+			activity[dayKey(noteTime(n.modifiedAt))]++
+		}
+	}
+	return activity
+}
+
+// gitCommitTimes returns the times of the past year's commits in the vault,
+// reporting false when the vault is not a git repository
+func gitCommitTimes() ([]time.Time, bool) {
+	out, err := exec.Command("git", "-C", notesDir, "log", "--since=1.year", "--format=%at", "--", ".").Output()
+	if err != nil {
+		return nil, false
+	}
+	var times []time.Time
+	for _, line := range strings.Fields(string(out)) {
+		if stamp, err := strconv.ParseInt(line, 10, 64); err == nil {
+			times = append(times, noteTime(stamp))
+		}
+	}
+	return times, true
+}
+
+// heatmapCell returns the mark for a day at an activity level; without
+// colour the levels are told apart by shading, and with it the second
+// level is drawn as a full block
+func heatmapCell(level int) string {
+	switch {
+	case level == 0:
+		return "·"
+	case noColor:
+		return []string{"·", "░", "▒", "▓", "█"}[level]
+	case level == 2:
+		return "█"
+	}
+	return "■"
+}
+
+// renderHeatmap draws a GitHub-style grid of the past year, one column per
+// week and one row per weekday
+func renderHeatmap(activity map[string]int, now time.Time) string {
+	busiest := 0
+	for _, count := range activity {
+		busiest = max(busiest, count)
+	}
+
+	// The last column is the current week, starting on Monday
+	thisWeek, _ := presetRange("this week", now)
+	start := thisWeek.AddDate(0, 0, -7*(heatmapWeeks-1))
+
+	months := []rune(strings.Repeat(" ", heatmapWeeks))
+	for w := 0; w < heatmapWeeks; w++ {
+		day := start.AddDate(0, 0, 7*w)
+		if day.Day() <= 7 && w+3 <= heatmapWeeks {
+			copy(months[w:], []rune(tr(day.Format("Jan"))))
+		}
+	}
+	rows := []string{"    " + string(months)}
+
+	for weekday, label := range []string{"Mon", "", "Wed", "", "Fri", "", "Sun"} {
+		var row strings.Builder
+		row.WriteString(fmt.Sprintf("%-4s", tr(label)))
+		for w := 0; w < heatmapWeeks; w++ {
+			day := start.AddDate(0, 0, 7*w+weekday)
+			if day.After(now) {
+				row.WriteString(" ")
+				continue
+			}
+			count := activity[dayKey(day)]
+			level := 0
+			if count > 0 {
+				level = 1 + (count-1)*(len(heatmapLevels)-1)/max(1, busiest)
+				level = min(level, len(heatmapLevels)-1)
+			}
+			row.WriteString(heatmapLevels[level].Render(heatmapCell(level)))
+		}
+		rows = append(rows, row.String())
+	}
+
+	legend := statsLabelStyle.Render(tr("less") + " ")
+	for level, style := range heatmapLevels {
+		legend += style.Render(heatmapCell(level))
+	}
+	rows = append(rows, "", "    "+legend+statsLabelStyle.Render(" "+tr("more")))
+	return strings.Join(rows, "\n")
+}
+
+// statsSummary lists the vault totals shown above the heatmap
+func statsSummary(notes []note, activity map[string]int) string {
+	words, tags := 0, make(map[string]bool)
+	for _, n := range notes {
+		words += wordCount(n.body)
+		for _, tag := range n.tags {
+			tags[tag] = true
+		}
+	}
+	activeDays := 0
+	for _, count := range activity {
+		if count > 0 {
+			activeDays++
+		}
+	}
+
+	line := func(label string, value int) string {
+		return statsLabelStyle.Render(fmt.Sprintf("%-14s", tr(label))) + strconv.Itoa(value)
+	}
+	return strings.Join([]string{
+		line("Notes", len(notes)),
+		line("Words", words),
+		line("Tags", len(tags)),
+		line("Active days", activeDays),
+	}, "\n")
+}
+
+// statsMsg carries the statistics screen once it is put together
+type statsMsg struct {
+	text string
+}
+
+// openStats shows the statistics screen for the notes in the list. It is
+// put together in the background, since reading the git history of a large
+// vault takes a while.
+func (m model) openStats() (model, tea.Cmd) {
+	m.statsText = statsLabelStyle.Render(tr("Counting…"))
+	m.mode = "stats"
+	notes := m.notes
+	return m, func() tea.Msg { return statsMsg{statsText(notes)} }
+}
+
+// statsText renders the statistics screen for the notes
+func statsText(notes []note) string {
+	activity := writingActivity(notes)
+	return lipgloss.JoinVertical(lipgloss.Left,
+		lipgloss.JoinHorizontal(lipgloss.Top,
+			lipgloss.JoinVertical(lipgloss.Left,
+				statsHeadingStyle.Render(tr("Vault")),
+				statsSummary(notes, activity),
+			),
+			"    ",
+			lipgloss.JoinVertical(lipgloss.Left,
+				statsHeadingStyle.Render(tr("Your habits")),
+				insightsSummary(flushedInsightLog(), localNow()),
+			),
+		),
+		"",
+		statsHeadingStyle.Render(tr("Writing activity")),
+		renderHeatmap(activity, localNow()),
+		"",
+		statsHeadingStyle.Render(tr("Words written")),
+		renderWordHistory(loadWordLog()[notesDir], localNow()),
+	)
+}
+
+// updateStats closes the statistics screen
+func (m model) updateStats(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if msg.Type == tea.KeyEsc || msg.String() == "q" || msg.String() == "alt+a" {
+		m.mode = "list"
+	}
+	return m, nil
+}
+
+// statsView shows the statistics full screen
+func (m model) statsView() string {
+	return docStyle.Render(lipgloss.JoinVertical(lipgloss.Top,
+		splitStyle.Width(m.width-8).Height(m.height-6).Render(m.statsText),
+		helpStyle.Render(tr("esc:Back")),
+	))
+}
+
+// writeStatsCSV writes one row per note with its dates in ISO 8601, size,
+// tags and the number of notes it links to and is linked from
+func writeStatsCSV(w *csv.Writer, notes []note) error {
+	linksOut := make(map[string]int)
+	linksIn := make(map[string]int)
+	for _, n := range notes {
+		links := noteLinks(notes, n)
+		linksOut[n.path] = len(links)
+		for _, path := range links {
+			linksIn[path]++
+		}
+	}
+
+	w.Write([]string{"title", "created", "modified", "words", "tags", "links_out", "links_in"})
+	for _, n := range notes {
+		w.Write([]string{
+			n.title,
+			noteTime(n.createdAt).Format(time.RFC3339),
+			noteTime(n.modifiedAt).Format(time.RFC3339),
+			strconv.Itoa(wordCount(n.body)),
+			strings.Join(n.tags, ", "),
+			strconv.Itoa(linksOut[n.path]),
+			strconv.Itoa(linksIn[n.path]),
+		})
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// runStats implements `gleaner stats [--csv]`, printing the vault totals or
+// a spreadsheet of per-note statistics
+func runStats(args []string) error {
+	flags := flag.NewFlagSet("stats", flag.ContinueOnError)
+	asCSV := flags.Bool("csv", false, "print one CSV row per note instead of the totals")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() > 0 {
+		return fmt.Errorf("usage: gleaner stats [--csv]")
+	}
+
+	notes, err := notesForCommand()
+	if err != nil {
+		return err
+	}
+	setPeople(notes)
+	if *asCSV {
+		return writeStatsCSV(csv.NewWriter(os.Stdout), notes)
+	}
+	fmt.Println(statsSummary(notes, writingActivity(notes)))
+	return nil
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Workflow states a note's "status" field cycles through when "statuses"
+// is not set in the config file
+var defaultStatuses = []string{"draft", "published"}
+
+// Badge style for a note's workflow status
+var statusBadge = badgeStyle.Foreground(lipgloss.Color("230")).Background(lipgloss.Color("97"))
+
+// noteStatuses returns the configured workflow states in order
+func noteStatuses() []string {
+	var statuses []string
+	for _, s := range cfg.Statuses {
+		if s = strings.ToLower(strings.TrimSpace(s)); s != "" {
+			statuses = append(statuses, s)
+		}
+	}
+	if len(statuses) == 0 {
+		return defaultStatuses
+	}
+	return statuses
+}
+
+// nextStatus returns the state after current, starting over at the first
+// once the last is reached or when current is not a known state
+func nextStatus(current string) string {
+	statuses := noteStatuses()
+	i := slices.Index(statuses, current)
+	return statuses[(i+1)%len(statuses)]
+}
+
+// cycleStatus moves the selected note to the next workflow state. Before a
+// note is published it is checked for credentials, and when it looks like
+// it holds some, "yes" is asked for first; confirmed is set once it has.
+func (m model) cycleStatus(confirmed bool) (model, tea.Cmd) {
+	if m.selectedNote == nil {
+		return m, nil
+	}
+	path := m.selectedNote.path
+	content, err := readNoteFile(path)
+	if err != nil {
+		m.status = err.Error()
+		return m, nil
+	}
+	status := nextStatus(m.selectedNote.status)
+	if status == "published" && !confirmed {
+		if found := findSecrets(string(content)); len(found) > 0 {
+			return m.openPrompt("confirm-publish", trf("This note looks like it contains: %s. Type yes to publish it anyway", strings.Join(found, ", ")), "")
+		}
+	}
+	if err := writeNoteFile(path, setFrontmatter(string(content), "status", status)); err != nil {
+		m.status = err.Error()
+		return m, nil
+	}
+	m.lastAction = &fieldAction{key: "status", value: status}
+	m.status = trf("%s is now %s", m.selectedNote.title, tr(status))
+	return m, loadNotes
+}
@@ -0,0 +1,427 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Matches a table separator cell such as "---", ":--" or ":-:"
+var separatorCell = regexp.MustCompile(`^:?-+:?$`)
+
+// isTableLine reports whether a line belongs to a pipe table
+func isTableLine(line string) bool {
+	return strings.HasPrefix(strings.TrimSpace(line), "|")
+}
+
+// tableBounds returns the first and last line of the table containing row
+func tableBounds(lines []string, row int) (int, int, bool) {
+	if row < 0 || row >= len(lines) || !isTableLine(lines[row]) {
+		return 0, 0, false
+	}
+	start, end := row, row
+	for start > 0 && isTableLine(lines[start-1]) {
+		start--
+	}
+	for end+1 < len(lines) && isTableLine(lines[end+1]) {
+		end++
+	}
+	return start, end, true
+}
+
+// splitRow breaks a table line into trimmed cells, honouring escaped pipes
+func splitRow(line string) []string {
+	line = strings.TrimSpace(line)
+	line = strings.TrimPrefix(line, "|")
+	if strings.HasSuffix(line, "|") && !strings.HasSuffix(line, `\|`) {
+		line = line[:len(line)-1]
+	}
+
+	var cells []string
+	var cell strings.Builder
+	escaped := false
+	for _, r := range line {
+		switch {
+		case escaped:
+			cell.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			cell.WriteRune(r)
+			escaped = true
+		case r == '|':
+			cells = append(cells, strings.TrimSpace(cell.String()))
+			cell.Reset()
+		default:
+			cell.WriteRune(r)
+		}
+	}
+	return append(cells, strings.TrimSpace(cell.String()))
+}
+
+// isSeparatorRow reports whether cells form the header separator row
+func isSeparatorRow(cells []string) bool {
+	for _, c := range cells {
+		if !separatorCell.MatchString(c) {
+			return false
+		}
+	}
+	return len(cells) > 0
+}
+
+// formatTable pads every cell so the columns line up
+func formatTable(lines []string) []string {
+	rows := make([][]string, len(lines))
+	columns := 0
+	for i, line := range lines {
+		rows[i] = splitRow(line)
+		columns = max(columns, len(rows[i]))
+	}
+
+	widths := make([]int, columns)
+	for _, cells := range rows {
+		if isSeparatorRow(cells) {
+			continue
+		}
+		for c, cell := range cells {
+			widths[c] = max(widths[c], ansi.StringWidth(cell))
+		}
+	}
+	for c := range widths {
+		widths[c] = max(widths[c], 3)
+	}
+
+	formatted := make([]string, len(rows))
+	for i, cells := range rows {
+		separator := isSeparatorRow(cells)
+		parts := make([]string, columns)
+		for c := range parts {
+			cell := ""
+			if c < len(cells) {
+				cell = cells[c]
+			}
+			if separator {
+				parts[c] = separatorFor(cell, widths[c])
+			} else {
+				parts[c] = cell + strings.Repeat(" ", widths[c]-ansi.StringWidth(cell))
+			}
+		}
+		formatted[i] = "| " + strings.Join(parts, " | ") + " |"
+	}
+	return formatted
+}
+
+// separatorFor widens a separator cell, keeping its alignment colons
+func separatorFor(cell string, width int) string {
+	left := strings.HasPrefix(cell, ":")
+	right := strings.HasSuffix(cell, ":") && len(cell) > 1
+	dashes := width
+	if left {
+		dashes--
+	}
+	if right {
+		dashes--
+	}
+	s := strings.Repeat("-", dashes)
+	if left {
+		s = ":" + s
+	}
+	if right {
+		s += ":"
+	}
+	return s
+}
+
+// formatTables aligns every pipe table in a document. Only blocks with a
+// header and separator row are taken for tables, and code is left alone:
+// fenced blocks, with backticks or tildes, and lines indented as code.
+func formatTables(content string) string {
+	lines := strings.Split(content, "\n")
+	fence := ""
+	for i := 0; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		switch {
+		case fence != "":
+			if strings.HasPrefix(trimmed, fence) && strings.Trim(trimmed, fence[:1]) == "" {
+				fence = ""
+			}
+			continue
+		case isIndentedCode(lines[i]):
+			continue
+		case strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~"):
+			fence = trimmed[:len(trimmed)-len(strings.TrimLeft(trimmed, trimmed[:1]))]
+			continue
+		case !isTableLine(lines[i]):
+			continue
+		}
+		end := i
+		for end+1 < len(lines) && isTableLine(lines[end+1]) && !isIndentedCode(lines[end+1]) {
+			end++
+		}
+		if isTable(lines[i : end+1]) {
+			copy(lines[i:end+1], formatTable(lines[i:end+1]))
+		}
+		i = end
+	}
+	return strings.Join(lines, "\n")
+}
+
+// isTable reports whether lines form a table: a header row followed by a
+// separator row with a cell for each header cell
+func isTable(lines []string) bool {
+	if len(lines) < 2 {
+		return false
+	}
+	separator := splitRow(lines[1])
+	return isSeparatorRow(separator) && len(separator) == len(splitRow(lines[0]))
+}
+
+// isIndentedCode reports whether a line is indented far enough, four
+// columns, to be code
+func isIndentedCode(line string) bool {
+	width := 0
+	for _, r := range line {
+		switch r {
+		case ' ':
+			width++
+		case '\t':
+			width += 4 - width%4
+		default:
+			return width >= 4
+		}
+		if width >= 4 {
+			return true
+		}
+	}
+	return false
+}
+
+// cellStarts returns the rune column where each cell's text begins in a
+// formatted table line
+func cellStarts(line string) []int {
+	var starts []int
+	for i, r := range []rune(line) {
+		if r == '|' && (i == 0 || []rune(line)[i-1] != '\\') {
+			starts = append(starts, i+2)
+		}
+	}
+	if len(starts) > 0 {
+		starts = starts[:len(starts)-1] // The closing pipe starts no cell
+	}
+	return starts
+}
+
+// tableCell aligns the table under the cursor and moves to the next (or
+// previous) cell. It reports false when the cursor is not in a table.
+func (m model) tableCell(forward bool) (model, bool) {
+	lines := strings.Split(m.textarea.Value(), "\n")
+	row, col := cursorPos(m.textarea)
+	start, end, ok := tableBounds(lines, row)
+	if !ok {
+		return m, false
+	}
+	copy(lines[start:end+1], formatTable(lines[start:end+1]))
+
+	// Find the cell the cursor is in, then step to its neighbour, skipping
+	// the separator row
+	starts := cellStarts(lines[row])
+	cell := 0
+	for i, s := range starts {
+		if col >= s-1 {
+			cell = i
+		}
+	}
+	if forward {
+		cell++
+	} else {
+		cell--
+	}
+	for cell < 0 || cell >= len(starts) || isSeparatorRow(splitRow(lines[row])) {
+		if forward {
+			row++
+		} else {
+			row--
+		}
+		if row < start || row > end {
+			// Leaving the table: stay on the edge cell
+			row = max(start, min(end, row))
+			starts = cellStarts(lines[row])
+			cell = max(0, min(len(starts)-1, cell))
+			break
+		}
+		starts = cellStarts(lines[row])
+		if forward {
+			cell = 0
+		} else {
+			cell = len(starts) - 1
+		}
+	}
+
+	replaceValue(&m.textarea, strings.Join(lines, "\n"), row, starts[cell])
+	return m, true
+}
+
+// addTableRow inserts an empty row below the cursor row
+func (m model) addTableRow() model {
+	lines := strings.Split(m.textarea.Value(), "\n")
+	row, _ := cursorPos(m.textarea)
+	start, end, ok := tableBounds(lines, row)
+	if !ok {
+		m.status = tr("Not inside a table")
+		return m
+	}
+
+	columns := len(splitRow(lines[start]))
+	empty := "|" + strings.Repeat(" |", columns)
+	lines = append(lines[:row+1], append([]string{empty}, lines[row+1:]...)...)
+	copy(lines[start:end+2], formatTable(lines[start:end+2]))
+
+	replaceValue(&m.textarea, strings.Join(lines, "\n"), row+1, 2)
+	return m
+}
+
+// addTableColumn appends an empty column to the table under the cursor
+func (m model) addTableColumn() model {
+	lines := strings.Split(m.textarea.Value(), "\n")
+	row, col := cursorPos(m.textarea)
+	start, end, ok := tableBounds(lines, row)
+	if !ok {
+		m.status = tr("Not inside a table")
+		return m
+	}
+
+	for i := start; i <= end; i++ {
+		cells := splitRow(lines[i])
+		if isSeparatorRow(cells) {
+			cells = append(cells, "---")
+		} else {
+			cells = append(cells, "")
+		}
+		lines[i] = "| " + strings.Join(cells, " | ") + " |"
+	}
+	copy(lines[start:end+1], formatTable(lines[start:end+1]))
+
+	replaceValue(&m.textarea, strings.Join(lines, "\n"), row, col)
+	return m
+}
+
+// columnAlign reads a column's alignment from its separator cell: 'l', 'c'
+// or 'r'
+func columnAlign(cell string) byte {
+	left, right := strings.HasPrefix(cell, ":"), strings.HasSuffix(cell, ":")
+	switch {
+	case left && right && len(cell) > 1:
+		return 'c'
+	case right:
+		return 'r'
+	}
+	return 'l'
+}
+
+// alignCell pads rendered text to width as its column is aligned
+func alignCell(text string, width int, align byte) string {
+	gap := max(0, width-ansi.StringWidth(text))
+	switch align {
+	case 'r':
+		return strings.Repeat(" ", gap) + text
+	case 'c':
+		return strings.Repeat(" ", gap/2) + text + strings.Repeat(" ", gap-gap/2)
+	}
+	return text + strings.Repeat(" ", gap)
+}
+
+// renderTable draws a pipe table for the preview with box borders and
+// columns aligned as the separator row asks. A table wider than the pane
+// has its widest columns narrowed and their cells wrapped. It returns the
+// output lines and, for each table line, the first output line it
+// produced.
+func renderTable(lines []string, width int) ([]string, []int) {
+	rows := make([][]string, len(lines))
+	columns := 0
+	for i, line := range lines {
+		rows[i] = splitRow(line)
+		columns = max(columns, len(rows[i]))
+	}
+	header := len(rows) > 1 && isSeparatorRow(rows[1])
+	aligns := make([]byte, columns)
+	for c := range aligns {
+		aligns[c] = 'l'
+		if header && c < len(rows[1]) {
+			aligns[c] = columnAlign(rows[1][c])
+		}
+	}
+
+	// Cells are rendered first so widths count what is shown
+	cells := make([][]string, len(rows))
+	widths := make([]int, columns)
+	for i, row := range rows {
+		if header && i == 1 {
+			continue
+		}
+		cells[i] = make([]string, columns)
+		for c := range cells[i] {
+			if c < len(row) {
+				cells[i][c] = renderInline(strings.ReplaceAll(row[c], `\|`, "|"))
+			}
+			if header && i == 0 {
+				cells[i][c] = previewBoldStyle.Render(cells[i][c])
+			}
+			widths[c] = max(widths[c], ansi.StringWidth(cells[i][c]), 1)
+		}
+	}
+
+	// Narrow the widest column until the table fits, down to 3 columns wide
+	total := 0
+	for _, w := range widths {
+		total += w
+	}
+	for room := width - 3*columns - 1; total > room; total-- {
+		widest := 0
+		for c := range widths {
+			if widths[c] > widths[widest] {
+				widest = c
+			}
+		}
+		if widths[widest] <= 3 {
+			break
+		}
+		widths[widest]--
+	}
+
+	border := func(left, middle, right string) string {
+		parts := make([]string, columns)
+		for c, w := range widths {
+			parts[c] = strings.Repeat("─", w+2)
+		}
+		return truncateLine(previewDimStyle.Render(left+strings.Join(parts, middle)+right), width)
+	}
+	bar := previewDimStyle.Render("│")
+
+	out := []string{border("┌", "┬", "┐")}
+	starts := make([]int, len(rows))
+	for i := range rows {
+		starts[i] = len(out)
+		if header && i == 1 {
+			out = append(out, border("├", "┼", "┤"))
+			continue
+		}
+		wrapped := make([][]string, columns)
+		height := 1
+		for c, cell := range cells[i] {
+			wrapped[c] = wrapLines(cell, widths[c])
+			height = max(height, len(wrapped[c]))
+		}
+		for l := 0; l < height; l++ {
+			line := bar
+			for c := range wrapped {
+				text := ""
+				if l < len(wrapped[c]) {
+					text = wrapped[c][l]
+				}
+				line += " " + alignCell(truncateLine(text, widths[c]), widths[c], aligns[c]) + " " + bar
+			}
+			out = append(out, truncateLine(line, width))
+		}
+	}
+	return append(out, border("└", "┴", "┘")), starts
+}
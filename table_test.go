@@ -0,0 +1,107 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestRenderTable(t *testing.T) {
+	tests := []struct {
+		name   string
+		lines  []string
+		width  int
+		want   []string
+		starts []int
+	}{
+		{
+			"aligned columns",
+			[]string{"| a | b |", "|:--|--:|", "| 1 | 22 |"},
+			40,
+			[]string{
+				"┌───┬────┐",
+				"│ a │  b │",
+				"├───┼────┤",
+				"│ 1 │ 22 │",
+				"└───┴────┘",
+			},
+			[]int{1, 2, 3},
+		},
+		{
+			"wide cells wrapped to fit",
+			[]string{"| a | long cell text here |", "|---|---|", "| 1 | 2 |"},
+			20,
+			[]string{
+				"┌───┬──────────────┐",
+				"│ a │ long cell    │",
+				"│   │ text here    │",
+				"├───┼──────────────┤",
+				"│ 1 │ 2            │",
+				"└───┴──────────────┘",
+			},
+			[]int{1, 3, 4},
+		},
+		{
+			"no header and a missing cell",
+			[]string{"| a | b |", "| c |"},
+			40,
+			[]string{
+				"┌───┬───┐",
+				"│ a │ b │",
+				"│ c │   │",
+				"└───┴───┘",
+			},
+			[]int{1, 2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines, starts := renderTable(tt.lines, tt.width)
+			for i := range lines {
+				lines[i] = ansi.Strip(lines[i])
+			}
+			if !slices.Equal(lines, tt.want) || !slices.Equal(starts, tt.starts) {
+				t.Errorf("renderTable =\n%s\n%v, want\n%s\n%v", strings.Join(lines, "\n"), starts, strings.Join(tt.want, "\n"), tt.starts)
+			}
+		})
+	}
+}
+
+func TestFormatTables(t *testing.T) {
+	tests := []struct {
+		name, in, want string
+	}{
+		{
+			"table",
+			"| a | bb |\n|-|:-:|\n| ccc | d |",
+			"| a   | bb  |\n| --- | :-: |\n| ccc | d   |",
+		},
+		{
+			"table among text",
+			"Intro\n|x|y|\n|---|---|\n|1|2|\nOutro",
+			"Intro\n| x   | y   |\n| --- | --- |\n| 1   | 2   |\nOutro",
+		},
+		{"no separator row", "| quoted | text |\n| more |", "| quoted | text |\n| more |"},
+		{"separator with a cell missing", "| a | b |\n|---|\n| 1 | 2 |", "| a | b |\n|---|\n| 1 | 2 |"},
+		{"single line", "|a|", "|a|"},
+		{"backtick fence", "```\n|a|b|\n|-|-|\n```", "```\n|a|b|\n|-|-|\n```"},
+		{"tilde fence", "~~~sh\n|a|b|\n|-|-|\n~~~", "~~~sh\n|a|b|\n|-|-|\n~~~"},
+		{"longer fence", "````\n```\n|a|b|\n|-|-|\n````", "````\n```\n|a|b|\n|-|-|\n````"},
+		{"indented code", "    |a|b|\n    |-|-|", "    |a|b|\n    |-|-|"},
+		{"tab indented code", "\t|a|b|\n\t|-|-|", "\t|a|b|\n\t|-|-|"},
+		{
+			"after a closed fence",
+			"~~~\ncode\n~~~\n|a|b|\n|-|-|",
+			"~~~\ncode\n~~~\n| a   | b   |\n| --- | --- |",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatTables(tt.in); got != tt.want {
+				t.Errorf("formatTables(%q) =\n%s\nwant\n%s", tt.in, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"embed"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Note type, set with "type: template" in the frontmatter, for notes that
+// new notes can be started from
+const noteTypeTemplate = "template"
+
+// Templates that come with gleaner, offered next to the vault's own
+//
+//go:embed templates/*.md
+var builtinTemplates embed.FS
+
+// Notes `gleaner init --examples` seeds a new vault with
+//
+//go:embed examples/*.md
+var exampleNotes embed.FS
+
+// Marks the picker value of a built-in template, followed by its file name
+const builtinTemplatePrefix = "builtin:"
+
+// embeddedTitle turns the name of an embedded file, such as Daily-note.md,
+// into a title the way parseNoteFilename does
+func embeddedTitle(name string) string {
+	return strings.ReplaceAll(strings.TrimSuffix(name, ".md"), "-", " ")
+}
+
+// templateItems lists the templates for the picker: the vault's template
+// notes first, including any the list's filter hides, then the built-in
+// ones that none of them replaces by title
+func (m model) templateItems() []pickerItem {
+	notes, err := scanNotes()
+	if err != nil && len(notes) == 0 {
+		notes = m.notes
+	}
+	var items []pickerItem
+	own := map[string]bool{}
+	for _, n := range notes {
+		if n.kind == noteTypeTemplate && n.readErr == nil {
+			items = append(items, pickerItem{title: n.title, desc: tr("Template note"), value: n.path})
+			own[strings.ToLower(n.title)] = true
+		}
+	}
+	entries, _ := fs.ReadDir(builtinTemplates, "templates")
+	for _, e := range entries {
+		title := embeddedTitle(e.Name())
+		if !own[strings.ToLower(title)] {
+			items = append(items, pickerItem{title: tr(title), desc: tr("Built in"), value: builtinTemplatePrefix + e.Name()})
+		}
+	}
+	return items
+}
+
+// startFromTemplate asks which template a new note should start from
+func (m model) startFromTemplate() model {
+	return m.openPicker("template", tr("New from template"), m.templateItems())
+}
+
+// templateText returns the text a template starts a note with. A template
+// note's own title and type fields are left out.
+func templateText(value string) (string, error) {
+	if name, ok := strings.CutPrefix(value, builtinTemplatePrefix); ok {
+		data, err := builtinTemplates.ReadFile("templates/" + name)
+		return string(data), err
+	}
+	content, err := readNoteText(value)
+	if err != nil {
+		return "", err
+	}
+	return deleteFrontmatter(deleteFrontmatter(content, "type"), "title"), nil
+}
+
+// templateChosen opens a new note filled in from the chosen template, with
+// the date placeholders filled in and the title left to type. The cursor
+// waits in the body where the template puts $|.
+func (m model) templateChosen(value string) (tea.Model, tea.Cmd) {
+	text, err := templateText(value)
+	if err != nil {
+		m.status = err.Error()
+		return m, nil
+	}
+	body := expandSnippetText(text)
+	before, after, hasCursor := strings.Cut(body, snippetCursor)
+	if !hasCursor {
+		before, after = body, ""
+	}
+
+	m.mode = "new"
+	m.selectedNote = nil
+	m.contentFocus = false
+	m.textInput.Reset()
+	m.titleEntered = false
+	lines := strings.Split(before, "\n")
+	replaceValue(&m.textarea, before+after, len(lines)-1, len([]rune(lines[len(lines)-1])))
+	m.textarea.Blur()
+	return m, m.textInput.Focus()
+}
+
+// runInit implements `gleaner init [--examples]`, which creates the notes
+// directory and with --examples fills a new vault with a welcome note, a
+// cheatsheet and a template note to start from
+func runInit(args []string) error {
+	flags := flag.NewFlagSet("init", flag.ContinueOnError)
+	examples := flags.Bool("examples", false, "add a welcome note, a cheatsheet and an example template")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() > 0 {
+		return errors.New("usage: gleaner init [--examples]")
+	}
+	if readOnly {
+		return errReadOnly
+	}
+	if !*examples {
+		fmt.Printf("Notes are kept in %s\n", notesDir)
+		return nil
+	}
+
+	if !isNoteExt(".md") {
+		return errors.New("the examples are markdown notes, but .md is not among the extensions in the config file")
+	}
+	notes, err := notesForCommand()
+	if err != nil {
+		return err
+	}
+	if len(notes) > 0 {
+		return fmt.Errorf("%s already has notes; examples are only added to an empty vault", notesDir)
+	}
+	entries, err := fs.ReadDir(exampleNotes, "examples")
+	if err != nil {
+		return err
+	}
+	// Ids a second apart keep the notes from clashing
+	now := time.Now().Unix()
+	for i, e := range entries {
+		data, err := exampleNotes.ReadFile("examples/" + e.Name())
+		if err != nil {
+			return err
+		}
+		// The examples are markdown, whatever extension new notes get
+		name := fmt.Sprintf("%d-%s", now-int64(len(entries)-1-i), e.Name())
+		if err := writeNoteFile(filepath.Join(notesDir, name), string(data)); err != nil {
+			return err
+		}
+	}
+	fmt.Printf("Added %d example notes to %s\n", len(entries), notesDir)
+	return nil
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"os"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// Themes that can be chosen with "theme" in the config file
+const (
+	themeHighContrast = "high-contrast"
+	themeNoColor      = "no-color"
+)
+
+// Set by --no-color, NO_COLOR or the no-color theme; styles the interface
+// with bold, underline and reverse video only
+var noColor bool
+
+// Set by the high-contrast theme
+var highContrast bool
+
+// Border colour of the focused pane
+var focusColor lipgloss.TerminalColor = lipgloss.Color("205")
+
+// Bright replacements for the default palette, keeping each colour's role
+// but raising dim greys and muted hues to full brightness
+var highContrastPalette = map[string]string{
+	"22":  "28",
+	"28":  "34",
+	"34":  "40",
+	"40":  "46",
+	"42":  "10",
+	"63":  "15",
+	"75":  "14",
+	"150": "10",
+	"160": "9",
+	"170": "11",
+	"203": "9",
+	"205": "11",
+	"212": "11",
+	"214": "11",
+	"230": "15",
+	"237": "244",
+	"238": "248",
+	"241": "252",
+	"245": "252",
+	"250": "15",
+}
+
+// themedStyles lists the package styles a theme restyles
+func themedStyles() []*lipgloss.Style {
+	styles := []*lipgloss.Style{
+		&splitStyle, &helpStyle, &titleStyle, &contentStyle,
+		&tagBadge, &pinnedBadge, &archivedBadge, &encryptedBadge, &tasksBadge, &errorBadge,
+		&priorityBadge, &lowPriorityBadge, &statusBadge,
+		&diffAddStyle, &diffRemoveStyle, &diffSameStyle, &mergeDiskStyle,
+		&goalFilledStyle, &goalEmptyStyle, &goalDoneStyle,
+		&previewH1Style, &previewHStyle, &previewCodeStyle, &previewQuoteStyle,
+		&previewRuleStyle, &previewLinkStyle, &previewDimStyle,
+		&qrCaptionStyle, &sideTitleStyle, &statsHeadingStyle, &statsLabelStyle,
+		&timelineHeaderStyle, &timelineTimeStyle, &timelineSelectedStyle, &zenCountStyle,
+		&detailsLabelStyle, &checklistCursorStyle, &languageBadge, &bookmarkBadge,
+		&codeKeywordStyle, &codeStringStyle, &codeCommentStyle, &codeNumberStyle,
+		&cardQuestionStyle, &cardSourceStyle, &toastSuccessStyle, &toastErrorStyle, &recordingStyle,
+	}
+	for i := range heatmapLevels {
+		styles = append(styles, &heatmapLevels[i])
+	}
+	return styles
+}
+
+// applyTheme restyles the interface for the configured theme. Without
+// colour, badges are drawn in reverse video and the focused pane gets a
+// heavy border so nothing depends on colour alone.
+func applyTheme(theme string, noColorFlag bool) {
+	noColor = noColorFlag || os.Getenv("NO_COLOR") != "" || theme == themeNoColor
+
+	switch {
+	case noColor:
+		lipgloss.SetColorProfile(termenv.Ascii)
+		for _, badge := range []*lipgloss.Style{&tagBadge, &pinnedBadge, &archivedBadge, &encryptedBadge, &tasksBadge, &errorBadge, &priorityBadge, &statusBadge, &languageBadge, &bookmarkBadge} {
+			*badge = badge.Reverse(true)
+		}
+		diffAddStyle = diffAddStyle.Bold(true)
+		diffRemoveStyle = diffRemoveStyle.Strikethrough(true)
+		timelineSelectedStyle = timelineSelectedStyle.Underline(true)
+
+	case theme == themeHighContrast:
+		highContrast = true
+		for _, style := range themedStyles() {
+			*style = brighten(*style)
+		}
+		focusColor = lipgloss.Color("11")
+	}
+}
+
+// brighten maps a style's colours through the high-contrast palette
+func brighten(style lipgloss.Style) lipgloss.Style {
+	remap := func(c lipgloss.TerminalColor) (lipgloss.TerminalColor, bool) {
+		color, ok := c.(lipgloss.Color)
+		if !ok {
+			return c, false
+		}
+		bright, ok := highContrastPalette[string(color)]
+		return lipgloss.Color(bright), ok
+	}
+	if c, ok := remap(style.GetForeground()); ok {
+		style = style.Foreground(c)
+	}
+	if c, ok := remap(style.GetBackground()); ok {
+		style = style.Background(c)
+	}
+	if c, ok := remap(style.GetBorderTopForeground()); ok {
+		style = style.BorderForeground(c)
+	}
+	return style
+}
+
+// themeDelegate adjusts the notes list styles to the theme
+func themeDelegate(d list.DefaultDelegate) list.DefaultDelegate {
+	switch {
+	case noColor:
+		d.Styles.SelectedTitle = d.Styles.SelectedTitle.Bold(true).Underline(true)
+		d.Styles.SelectedDesc = d.Styles.SelectedDesc.Bold(true)
+	case highContrast:
+		bright := lipgloss.Color("11")
+		d.Styles.NormalTitle = d.Styles.NormalTitle.Foreground(lipgloss.Color("15"))
+		d.Styles.NormalDesc = d.Styles.NormalDesc.Foreground(lipgloss.Color("252"))
+		d.Styles.SelectedTitle = d.Styles.SelectedTitle.Foreground(bright).BorderForeground(bright).Bold(true)
+		d.Styles.SelectedDesc = d.Styles.SelectedDesc.Foreground(bright).BorderForeground(bright)
+		d.Styles.DimmedTitle = d.Styles.DimmedTitle.Foreground(lipgloss.Color("250"))
+		d.Styles.DimmedDesc = d.Styles.DimmedDesc.Foreground(lipgloss.Color("248"))
+	}
+	return d
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Timeline styling
+var (
+	timelineHeaderStyle   = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("212"))
+	timelineTimeStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+	timelineSelectedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("170")).Bold(true)
+)
+
+// timelineRow is a date header or a note in the timeline
+type timelineRow struct {
+	group string // Date group the row belongs to
+	note  *note  // Nil for the group header
+	count int    // Notes in the group, for headers
+}
+
+// timelineGroup names the period a note was created in, relative to now
+func timelineGroup(created, now time.Time) string {
+	today, _ := presetRange("today", now)
+	week, _ := presetRange("this week", now)
+	switch {
+	case !created.Before(today):
+		return tr("Today")
+	case !created.Before(today.AddDate(0, 0, -1)):
+		return tr("Yesterday")
+	case !created.Before(week):
+		return tr("This week")
+	default:
+		return tr(created.Format("January")) + created.Format(" 2006")
+	}
+}
+
+// timelineRows lists the groups, newest first, and the notes of every
+// group that is not collapsed. Only the notes the list's filter lets
+// through are shown.
+func (m model) timelineRows() []timelineRow {
+	var notes []note
+	for _, item := range m.list.VisibleItems() {
+		notes = append(notes, item.(note))
+	}
+	sort.SliceStable(notes, func(i, j int) bool { return notes[i].createdAt > notes[j].createdAt })
+
+	now := localNow()
+	var rows []timelineRow
+	header := -1
+	for i := range notes {
+		group := timelineGroup(noteTime(notes[i].createdAt), now)
+		if header < 0 || rows[header].group != group {
+			rows = append(rows, timelineRow{group: group})
+			header = len(rows) - 1
+		}
+		rows[header].count++
+		if !m.collapsed[group] {
+			rows = append(rows, timelineRow{group: group, note: &notes[i]})
+		}
+	}
+	return rows
+}
+
+// toggleTimeline switches the list pane between the flat list and the timeline
+func (m model) toggleTimeline() model {
+	m.timeline = !m.timeline
+	if m.timeline {
+		// Start on the selected note
+		m.timelineCursor = 0
+		for i, row := range m.timelineRows() {
+			if row.note != nil && m.selectedNote != nil && row.note.path == m.selectedNote.path {
+				m.timelineCursor = i
+			}
+		}
+	}
+	return m
+}
+
+// updateTimeline moves through the timeline and folds its groups
+func (m model) updateTimeline(msg tea.KeyMsg) (model, bool) {
+	rows := m.timelineRows()
+	if len(rows) == 0 {
+		return m, false
+	}
+	m.timelineCursor = min(m.timelineCursor, len(rows)-1)
+	row := rows[m.timelineCursor]
+
+	switch msg.String() {
+	case "up", "k":
+		m.timelineCursor = max(0, m.timelineCursor-1)
+	case "down", "j":
+		m.timelineCursor = min(len(rows)-1, m.timelineCursor+1)
+	case "pgup":
+		m.timelineCursor = max(0, m.timelineCursor-m.list.Height())
+	case "pgdown":
+		m.timelineCursor = min(len(rows)-1, m.timelineCursor+m.list.Height())
+	case "enter", " ", "left", "right", "h", "l":
+		if row.note != nil && (msg.String() == "enter" || msg.String() == " ") {
+			break
+		}
+		fold := !m.collapsed[row.group]
+		if msg.String() == "left" || msg.String() == "h" {
+			fold = true
+		} else if msg.String() == "right" || msg.String() == "l" {
+			fold = false
+		}
+		if m.collapsed == nil {
+			m.collapsed = make(map[string]bool)
+		}
+		m.collapsed[row.group] = fold
+		// Keep the cursor on the group header
+		for i, r := range m.timelineRows() {
+			if r.note == nil && r.group == row.group {
+				m.timelineCursor = i
+			}
+		}
+		return m, true
+	default:
+		return m, false
+	}
+
+	if row := m.timelineRows()[m.timelineCursor]; row.note != nil {
+		n := *row.note
+		m.selectedNote = &n
+		m.textarea.SetValue(noteText(n.path))
+	}
+	return m, true
+}
+
+// timelineView renders the rows around the cursor for the list pane
+func (m model) timelineView(width, height int) string {
+	rows := m.timelineRows()
+	title := m.list.Styles.Title.Render(tr("Timeline"))
+	if len(rows) == 0 {
+		return title + "\n\n" + tr("No notes.")
+	}
+
+	// Keep the cursor inside the visible window
+	height = max(1, height-2)
+	cursor := min(m.timelineCursor, len(rows)-1)
+	top := max(0, min(cursor-height/2, len(rows)-height))
+
+	lines := []string{title, ""}
+	for i := top; i < min(len(rows), top+height); i++ {
+		row := rows[i]
+		var line string
+		if row.note == nil {
+			marker := "▾"
+			if m.collapsed[row.group] {
+				marker = "▸"
+			}
+			line = timelineHeaderStyle.Render(fmt.Sprintf("%s %s (%d)", marker, row.group, row.count))
+		} else {
+			stamp := formatTime(noteTime(row.note.createdAt))
+			name := ansi.Truncate(row.note.title, max(1, width-lipgloss.Width(stamp)-7), "…")
+			if i == cursor {
+				name = timelineSelectedStyle.Render(name)
+			}
+			line = "  " + name + " " + timelineTimeStyle.Render(stamp)
+		}
+		if i == cursor {
+			line = timelineSelectedStyle.Render("│ ") + line
+		} else {
+			line = "  " + line
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
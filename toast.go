@@ -0,0 +1,116 @@
+package main
+
+import (
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// How long toasts stay on screen; errors stay longer so they can be read
+const (
+	toastDuration      = 4 * time.Second
+	errorToastDuration = 10 * time.Second
+)
+
+// Toast styling
+var (
+	toastSuccessStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("42"))
+	toastErrorStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("203")).Bold(true)
+)
+
+// toast is a short-lived notice of how a background operation went, shown
+// above the help line
+type toast struct {
+	text string // One-line summary
+	err  error  // Why the operation failed, nil on success
+	id   int    // Tells toasts apart so an old one's timer leaves a newer one be
+}
+
+// toastExpiredMsg hides a toast once its time is up
+type toastExpiredMsg struct {
+	id int
+}
+
+// errMsg reports a failed file operation, such as saving or deleting a note
+type errMsg struct {
+	summary string // What failed, translated when shown
+	err     error
+	draft   *draft // Unsaved note to put back in the editor, nil when none
+}
+
+// draft is a note whose save failed
+type draft struct {
+	title, content string
+	existing       *note // Note being edited, nil for a new note
+}
+
+// notify shows a toast with the result of an operation, replacing the
+// status message. With an error it is shown as a failure whose full text
+// alt+E opens.
+func (m model) notify(text string, err error) (model, tea.Cmd) {
+	m.toast = toast{text: text, err: err, id: m.toast.id + 1}
+	m.status = ""
+	duration := toastDuration
+	if err != nil {
+		m.lastError = text + "\n\n" + err.Error()
+		duration = errorToastDuration
+	}
+	id := m.toast.id
+	return m, tea.Tick(duration, func(time.Time) tea.Msg { return toastExpiredMsg{id} })
+}
+
+// clearCoveredToast hides the toast when a status message was set since
+// before, so the message replaces it rather than being hidden behind it
+// until the toast expires
+func (m model) clearCoveredToast(before model) model {
+	if m.status != "" && m.status != before.status && m.toast.id == before.toast.id {
+		m.toast = toast{id: m.toast.id}
+	}
+	return m
+}
+
+// toastView renders the toast on one line of the given width. Success and
+// failure are marked by symbol as well as colour.
+func (m model) toastView(width int) string {
+	mark, style := "✓ ", toastSuccessStyle
+	hint := ""
+	if m.toast.err != nil {
+		mark, style = "✗ ", toastErrorStyle
+		hint = " · " + tr("alt+E:Details")
+	}
+	text, _, _ := strings.Cut(m.toast.text, "\n")
+	text = ansi.Truncate(mark+text, max(1, width-lipgloss.Width(hint)), "…")
+	return style.Render(text) + hint
+}
+
+// showErrorDetails opens a popup with the full text of the last error
+func (m model) showErrorDetails() model {
+	if m.lastError == "" {
+		m.status = tr("No errors so far")
+		return m
+	}
+	text := lipgloss.NewStyle().Width(max(20, min(m.width-8, 100))).Render(m.lastError)
+	m.popup = text + "\n\n" + detailsLabelStyle.Render(tr("any key to close"))
+	return m
+}
+
+// restoreDraft reopens the editor on a note whose save failed, so nothing
+// typed is lost
+func (m model) restoreDraft(d draft) model {
+	m.mode = "new"
+	if d.existing != nil {
+		m.mode = "edit"
+	}
+	m.selectedNote = d.existing
+	m.textInput.SetValue(d.title)
+	m.textInput.Blur()
+	m.textarea.SetValue(d.content)
+	m.textarea.Focus()
+	m.titleEntered = true
+	m.contentFocus = false
+	m.status = tr("Your text is still in the editor; press ctrl+s to try again")
+	return m
+}
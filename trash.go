@@ -0,0 +1,343 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Folder in the notes directory deleted notes are moved to. Being hidden,
+// it is not scanned for notes.
+const trashDir = ".trash"
+
+// Days trashed notes are kept when trash_retention_days is not set
+const defaultTrashRetention = 30
+
+// File in the trash recording where each trashed note came from and when
+const trashIndexFile = "index.json"
+
+// trashRecord is what the trash index keeps about a trashed note
+type trashRecord struct {
+	Rel      string    `json:"rel"`      // Where the note was in the vault, with slashes
+	Deleted  time.Time `json:"deleted"`  // When it was trashed
+	Modified time.Time `json:"modified"` // When it was last changed before that
+}
+
+// Serializes changes to the trash index
+var trashIndexMu sync.Mutex
+
+// trashedNote is a note waiting in the trash
+type trashedNote struct {
+	path      string    // Location in the trash
+	stored    string    // Location relative to the trash folder
+	rel       string    // Where the note was in the vault
+	title     string    // Title taken from the file name
+	size      int64     // Bytes on disk
+	trashedAt time.Time // When it was deleted
+}
+
+// trashRetention returns how long trashed notes are kept, or zero when they
+// are kept until purged by hand
+func trashRetention() time.Duration {
+	days := cfg.TrashRetentionDays
+	if days == 0 {
+		days = defaultTrashRetention
+	}
+	if days < 0 {
+		return 0
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// loadTrashIndex reads the trash index, keyed by each note's place in the
+// trash folder with slashes; notes trashed before there was an index have
+// no entry
+func loadTrashIndex() map[string]trashRecord {
+	index := map[string]trashRecord{}
+	if data, err := os.ReadFile(filepath.Join(notesDir, trashDir, trashIndexFile)); err == nil {
+		json.Unmarshal(data, &index)
+	}
+	return index
+}
+
+// updateTrashIndex changes the trash index and saves it
+func updateTrashIndex(update func(map[string]trashRecord)) error {
+	trashIndexMu.Lock()
+	defer trashIndexMu.Unlock()
+	index := loadTrashIndex()
+	update(index)
+	data, err := json.Marshal(index)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(filepath.Join(notesDir, trashDir, trashIndexFile), data, 0644)
+}
+
+// trashNote moves a note into the trash, keeping its folder within the vault
+// so it can be put back. A note trashed earlier from the same place is
+// kept too, the newer one getting a number after its name. The trash index
+// records when the note was deleted, leaving the file's own time as it was.
+func trashNote(path string) error {
+	rel, err := filepath.Rel(notesDir, path)
+	if err != nil {
+		return err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	stored := rel
+	ext := filepath.Ext(rel)
+	for n := 2; ; n++ {
+		if _, err := os.Lstat(filepath.Join(notesDir, trashDir, stored)); errors.Is(err, fs.ErrNotExist) {
+			break
+		}
+		stored = fmt.Sprintf("%s (%d)%s", strings.TrimSuffix(rel, ext), n, ext)
+	}
+	dst := filepath.Join(notesDir, trashDir, stored)
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	if err := os.Rename(path, dst); err != nil {
+		return err
+	}
+	slog.Debug("trash note", "path", path, "to", dst)
+	return updateTrashIndex(func(index map[string]trashRecord) {
+		index[filepath.ToSlash(stored)] = trashRecord{Rel: filepath.ToSlash(rel), Deleted: time.Now(), Modified: info.ModTime()}
+	})
+}
+
+// listTrash returns the trashed notes, most recently deleted first. A note
+// trashed before the trash index was kept is dated by its file's time,
+// which was set when it was deleted.
+func listTrash() ([]trashedNote, error) {
+	root := filepath.Join(notesDir, trashDir)
+	index := loadTrashIndex()
+	var trashed []trashedNote
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if errors.Is(err, fs.ErrNotExist) && path == root {
+			return filepath.SkipDir
+		}
+		if err != nil || d.IsDir() {
+			return err
+		}
+		if _, _, ok := parseNoteFilename(d.Name()); !ok {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		stored, _ := filepath.Rel(root, path)
+		t := trashedNote{path: path, stored: stored, rel: stored, size: info.Size(), trashedAt: info.ModTime()}
+		if record, ok := index[filepath.ToSlash(stored)]; ok {
+			t.rel, t.trashedAt = filepath.FromSlash(record.Rel), record.Deleted
+		}
+		t.title, _, _ = parseNoteFilename(filepath.Base(t.rel))
+		trashed = append(trashed, t)
+		return nil
+	})
+	sort.Slice(trashed, func(i, j int) bool { return trashed[i].trashedAt.After(trashed[j].trashedAt) })
+	return trashed, err
+}
+
+// purgeTrash deletes the trashed notes deleted before cutoff, or all of
+// them when cutoff is zero, returning how many and how many bytes went
+func purgeTrash(cutoff time.Time) (int, int64, error) {
+	trashed, err := listTrash()
+	if err != nil {
+		return 0, 0, err
+	}
+	count, size := 0, int64(0)
+	for _, t := range trashed {
+		if !cutoff.IsZero() && !t.trashedAt.Before(cutoff) {
+			continue
+		}
+		if err := os.Remove(t.path); err != nil {
+			return count, size, err
+		}
+		updateTrashIndex(func(index map[string]trashRecord) { delete(index, filepath.ToSlash(t.stored)) })
+		count++
+		size += t.size
+	}
+	return count, size, nil
+}
+
+// purgeExpired deletes the trashed notes older than the retention period
+func purgeExpired() (int, int64, error) {
+	retention := trashRetention()
+	if retention == 0 || readOnly {
+		return 0, 0, nil
+	}
+	return purgeTrash(time.Now().Add(-retention))
+}
+
+// restoreTrashed puts a trashed note, given by its place in the trash
+// folder, back where it was deleted from, with the modification time it
+// had then
+func restoreTrashed(stored string) (string, error) {
+	record, indexed := loadTrashIndex()[filepath.ToSlash(stored)]
+	rel := stored
+	if indexed {
+		rel = filepath.FromSlash(record.Rel)
+	}
+	src := filepath.Join(notesDir, trashDir, stored)
+	dst := filepath.Join(notesDir, rel)
+	if _, err := os.Stat(dst); err == nil {
+		return "", fmt.Errorf("%s already exists", rel)
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return "", err
+	}
+	if err := os.Rename(src, dst); err != nil {
+		return "", err
+	}
+	if indexed {
+		os.Chtimes(dst, record.Modified, record.Modified)
+		updateTrashIndex(func(index map[string]trashRecord) { delete(index, filepath.ToSlash(stored)) })
+	}
+	content, _ := readNoteFile(dst)
+	recordChange(auditRestore, dst, "", string(content))
+	return dst, nil
+}
+
+// formatSize shows a byte count in B, KB or MB
+func formatSize(size int64) string {
+	switch {
+	case size < 1024:
+		return fmt.Sprintf("%d B", size)
+	case size < 1024*1024:
+		return fmt.Sprintf("%.1f KB", float64(size)/1024)
+	default:
+		return fmt.Sprintf("%.1f MB", float64(size)/(1024*1024))
+	}
+}
+
+// trashSize adds up the size of the trashed notes
+func trashSize(trashed []trashedNote) int64 {
+	var total int64
+	for _, t := range trashed {
+		total += t.size
+	}
+	return total
+}
+
+// openTrash lists the trashed notes with their size and when they will be
+// purged; choosing one puts it back, and the last entry empties the trash
+// once "yes" is typed to confirm it
+func (m model) openTrash() model {
+	purgeExpired()
+	trashed, err := listTrash()
+	if err != nil {
+		m.status = err.Error()
+		return m
+	}
+	if len(trashed) == 0 {
+		m.status = tr("The trash is empty")
+		return m
+	}
+
+	now := time.Now()
+	var items []pickerItem
+	for _, t := range trashed {
+		desc := trf("Deleted %s", formatTime(t.trashedAt)) + " · " + formatSize(t.size)
+		if retention := trashRetention(); retention > 0 {
+			if days := int(t.trashedAt.Add(retention).Sub(now).Hours() / 24); days > 0 {
+				desc += " · " + trf("purged in %d days", days)
+			} else {
+				desc += " · " + tr("purged within a day")
+			}
+		}
+		items = append(items, pickerItem{title: t.title, desc: desc, value: t.stored})
+	}
+	items = append(items, pickerItem{title: tr("Empty trash now"), desc: trf("Delete %d notes for good", len(trashed)), value: ""})
+	return m.openPicker("trash", trf("Trash (%s)", formatSize(trashSize(trashed))), items)
+}
+
+// trashChosen restores the chosen note, or asks before emptying the trash
+func (m model) trashChosen(value string) (tea.Model, tea.Cmd) {
+	if readOnly {
+		m.status = errReadOnly.Error()
+		return m, nil
+	}
+	if value == "" {
+		return m.openPrompt("confirm-empty-trash", tr("Type yes to delete every trashed note for good"), "")
+	}
+	path, err := restoreTrashed(value)
+	if err != nil {
+		m.status = err.Error()
+		return m, nil
+	}
+	title, _, _ := parseNoteFilename(filepath.Base(path))
+	m.status = trf("Restored %s", title)
+	return m, loadNotes
+}
+
+// confirmEmptyTrash empties the trash when the answer is yes
+func (m model) confirmEmptyTrash(answer string) (tea.Model, tea.Cmd) {
+	if !answeredYes(answer) {
+		m.status = tr("Nothing deleted")
+		return m, nil
+	}
+	count, size, err := purgeTrash(time.Time{})
+	if err != nil {
+		m.status = err.Error()
+		return m, nil
+	}
+	m.status = trf("Purged %d notes (%s)", count, formatSize(size))
+	return m, nil
+}
+
+// runTrash implements `gleaner trash [--purge]`, listing the trashed notes
+// and their size, or with --purge deleting them all now. Notes past the
+// retention period are purged first.
+func runTrash(args []string) error {
+	flags := flag.NewFlagSet("trash", flag.ContinueOnError)
+	purge := flags.Bool("purge", false, "delete every trashed note now")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() > 0 {
+		return errors.New("usage: gleaner trash [--purge]")
+	}
+	if *purge && readOnly {
+		return errReadOnly
+	}
+
+	count, size, err := purgeExpired()
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		fmt.Printf("Purged %d expired notes (%s)\n", count, formatSize(size))
+	}
+	if *purge {
+		count, size, err := purgeTrash(time.Time{})
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Purged %d notes (%s)\n", count, formatSize(size))
+		return nil
+	}
+
+	trashed, err := listTrash()
+	if err != nil {
+		return err
+	}
+	for _, t := range trashed {
+		fmt.Printf("%s  %8s  %s\n", t.trashedAt.Format("2006-01-02 15:04"), formatSize(t.size), t.rel)
+	}
+	fmt.Printf("%d notes, %s\n", len(trashed), formatSize(trashSize(trashed)))
+	return nil
+}
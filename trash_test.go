@@ -0,0 +1,188 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeTrashNote writes a note into the vault modified at the given time
+func writeTrashNote(t *testing.T, rel, content string, modified time.Time) string {
+	t.Helper()
+	path := filepath.Join(notesDir, rel)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, modified, modified); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestTrashKeepsEveryCopy(t *testing.T) {
+	old := notesDir
+	notesDir = t.TempDir()
+	t.Cleanup(func() { notesDir = old })
+	then := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+
+	const rel = "work/1700000000-Plan.md"
+	for _, content := range []string{"first\n", "second\n", "third\n"} {
+		if err := trashNote(writeTrashNote(t, rel, content, then)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	trashed, err := listTrash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(trashed) != 3 {
+		t.Fatalf("%d notes in the trash, want 3", len(trashed))
+	}
+	stored := map[string]bool{}
+	for _, tr := range trashed {
+		stored[filepath.ToSlash(tr.stored)] = true
+		if tr.rel != filepath.FromSlash(rel) || tr.title != "Plan" {
+			t.Errorf("%s is listed as %s titled %q", tr.stored, tr.rel, tr.title)
+		}
+		if time.Since(tr.trashedAt) > time.Minute {
+			t.Errorf("%s dated %v, not when it was deleted", tr.stored, tr.trashedAt)
+		}
+	}
+	for _, want := range []string{"work/1700000000-Plan.md", "work/1700000000-Plan (2).md", "work/1700000000-Plan (3).md"} {
+		if !stored[want] {
+			t.Errorf("no %s in the trash: %v", want, stored)
+		}
+	}
+
+	// Restoring puts the note back with the time it was last changed
+	path, err := restoreTrashed(filepath.FromSlash("work/1700000000-Plan (2).md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path != filepath.Join(notesDir, rel) {
+		t.Errorf("restored to %s", path)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "second\n" {
+		t.Errorf("restored %q, want the second copy", data)
+	}
+	if info, err := os.Stat(path); err != nil || !info.ModTime().Equal(then) {
+		t.Errorf("restored note modified at %v, want %v", info.ModTime(), then)
+	}
+	if _, err := restoreTrashed(filepath.FromSlash("work/1700000000-Plan (3).md")); err == nil {
+		t.Error("restored over a note already there")
+	}
+	if _, ok := loadTrashIndex()["work/1700000000-Plan (2).md"]; ok {
+		t.Error("restored note left in the trash index")
+	}
+}
+
+func TestTrashWithoutIndex(t *testing.T) {
+	old := notesDir
+	notesDir = t.TempDir()
+	t.Cleanup(func() { notesDir = old })
+
+	// Notes trashed before the index was kept are dated by their file
+	deleted := time.Now().Add(-40 * 24 * time.Hour)
+	writeTrashNote(t, filepath.Join(trashDir, "1700000000-Old.md"), "old\n", deleted)
+	writeTrashNote(t, filepath.Join(trashDir, "notes.txt~"), "not a note\n", deleted)
+
+	trashed, err := listTrash()
+	if err != nil || len(trashed) != 1 {
+		t.Fatalf("listed %d notes, %v", len(trashed), err)
+	}
+	if tr := trashed[0]; tr.rel != "1700000000-Old.md" || tr.title != "Old" || !tr.trashedAt.Equal(deleted) {
+		t.Errorf("listed %+v", tr)
+	}
+	if _, err := restoreTrashed("1700000000-Old.md"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(notesDir, "1700000000-Old.md")); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestPurgeTrash(t *testing.T) {
+	oldDir, oldCfg := notesDir, cfg
+	t.Cleanup(func() { notesDir, cfg = oldDir, oldCfg })
+	notesDir, cfg = t.TempDir(), config{TrashRetentionDays: 30}
+
+	now := time.Now()
+	writeTrashNote(t, filepath.Join(trashDir, "1700000000-Expired.md"), "12345", now)
+	writeTrashNote(t, filepath.Join(trashDir, "1700000001-Recent.md"), "12", now)
+	updateTrashIndex(func(index map[string]trashRecord) {
+		index["1700000000-Expired.md"] = trashRecord{Rel: "1700000000-Expired.md", Deleted: now.Add(-31 * 24 * time.Hour)}
+		index["1700000001-Recent.md"] = trashRecord{Rel: "1700000001-Recent.md", Deleted: now.Add(-29 * 24 * time.Hour)}
+	})
+
+	count, size, err := purgeExpired()
+	if err != nil || count != 1 || size != 5 {
+		t.Errorf("purged %d notes of %d bytes, %v; want the expired one", count, size, err)
+	}
+	if _, ok := loadTrashIndex()["1700000000-Expired.md"]; ok {
+		t.Error("purged note left in the trash index")
+	}
+
+	cfg.TrashRetentionDays = -1
+	if count, _, _ := purgeExpired(); count != 0 {
+		t.Errorf("purged %d notes while they are kept for good", count)
+	}
+	if count, _, _ := purgeTrash(time.Time{}); count != 1 {
+		t.Errorf("emptying the trash purged %d notes, want 1", count)
+	}
+}
+
+// startTrashDriver opens the interface on a vault holding one note
+func startTrashDriver(t *testing.T) *driver {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+	t.Setenv("XDG_DATA_HOME", filepath.Join(home, ".local", "share"))
+	oldDir, oldCfg := notesDir, cfg
+	t.Cleanup(func() { notesDir, cfg = oldDir, oldCfg })
+	notesDir, cfg = t.TempDir(), config{}
+	writeTrashNote(t, "1700000000-Old-idea.md", "maybe later\n", time.Now())
+
+	d := newDriver(initialModel(), 100, 30)
+	if err := d.expect("Old idea", false); err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+func TestTrashAndRestoreFromTheList(t *testing.T) {
+	d := startTrashDriver(t)
+	must(t, d, d.press("ctrl+d"))
+	must(t, d, d.expect("Old idea", true))
+	must(t, d, d.press("alt+D"))
+	must(t, d, d.expect("Empty trash now", false))
+	must(t, d, d.press("enter"))
+	must(t, d, d.expect("Restored Old idea", false))
+	if data, err := os.ReadFile(filepath.Join(notesDir, "1700000000-Old-idea.md")); string(data) != "maybe later\n" {
+		t.Errorf("restored note is %q, %v", data, err)
+	}
+}
+
+func TestEmptyTrashAsksFirst(t *testing.T) {
+	d := startTrashDriver(t)
+	must(t, d, d.press("ctrl+d", "alt+D", "down", "enter"))
+	must(t, d, d.expect("Type yes to delete every trashed note for good", false))
+	d.typeText("no")
+	must(t, d, d.press("enter"))
+	must(t, d, d.expect("Nothing deleted", false))
+	if trashed, _ := listTrash(); len(trashed) != 1 {
+		t.Fatalf("%d notes in the trash, want 1", len(trashed))
+	}
+
+	must(t, d, d.press("alt+D", "down", "enter"))
+	d.typeText("yes")
+	must(t, d, d.press("enter"))
+	must(t, d, d.expect("Purged 1 notes", false))
+	if trashed, _ := listTrash(); len(trashed) != 0 {
+		t.Errorf("%d notes left in the trash", len(trashed))
+	}
+}
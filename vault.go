@@ -0,0 +1,115 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// vaultConfig describes a named vault and the settings it overrides
+type vaultConfig struct {
+	Dir          string `json:"dir"`                     // Notes directory of the vault
+	BackupDir    string `json:"backup_dir,omitempty"`    // Overrides the global backup directory
+	BackupFormat string `json:"backup_format,omitempty"` // Overrides the global archive format
+	ReadOnly     bool   `json:"read_only,omitempty"`     // Disallow changes to this vault
+}
+
+var (
+	// Settings from the config file before any vault overrides are applied
+	baseCfg config
+
+	// Name of the vault currently open, empty when using a plain directory
+	activeVault string
+)
+
+// vaultNames returns the configured vault names in a stable order
+func vaultNames() []string {
+	names := make([]string, 0, len(baseCfg.Vaults))
+	for name := range baseCfg.Vaults {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// useVault makes the named vault current, applying its settings over the
+// global ones
+func useVault(name string) error {
+	v, ok := baseCfg.Vaults[name]
+	if !ok {
+		return fmt.Errorf("no vault named %q in config", name)
+	}
+	if v.Dir == "" {
+		return fmt.Errorf("vault %q has no dir", name)
+	}
+
+	cfg = baseCfg
+	if v.BackupDir != "" {
+		cfg.BackupDir = v.BackupDir
+	}
+	if v.BackupFormat != "" {
+		cfg.BackupFormat = v.BackupFormat
+	}
+	notesDir = expandHome(v.Dir)
+	readOnly = readOnlyFlag || baseCfg.ReadOnly || v.ReadOnly
+	activeVault = name
+	return nil
+}
+
+// vaultPickerItems lists the configured vaults for the picker
+func vaultPickerItems() []pickerItem {
+	var items []pickerItem
+	for _, name := range vaultNames() {
+		title := name
+		if name == activeVault {
+			title += " (current)"
+		}
+		items = append(items, pickerItem{
+			title: title,
+			desc:  expandHome(baseCfg.Vaults[name].Dir),
+			value: name,
+		})
+	}
+	return items
+}
+
+// openVaultPicker shows the vault switcher
+func (m model) openVaultPicker() model {
+	return m.openPicker("vault", tr("Vaults"), vaultPickerItems())
+}
+
+// switchVault opens another vault and reloads the notes list
+func (m model) switchVault(name string) (tea.Model, tea.Cmd) {
+	if err := useVault(name); err != nil {
+		m.status = err.Error()
+		return m, nil
+	}
+	var warning string
+	if !readOnly {
+		var err error
+		if warning, err = ensureNotesDir(); err != nil {
+			return m.notify(err.Error(), err)
+		}
+	}
+
+	m.mode = "list"
+	m.selectedNote = nil
+	m.textInput.Reset()
+	m.textarea.Reset()
+	m.list.ResetFilter()
+	m.list.Title = listTitle()
+	m.status = trf("Opened vault %s", name)
+	if warning != "" {
+		m.status = warning
+	}
+	return m, loadNotes
+}
+
+// listTitle names the notes list after the open vault
+func listTitle() string {
+	if activeVault == "" {
+		return tr("Notes")
+	}
+	return tr("Notes") + " · " + activeVault
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestUseVault(t *testing.T) {
+	oldDir, oldCfg, oldBase, oldVault, oldReadOnly := notesDir, cfg, baseCfg, activeVault, readOnly
+	t.Cleanup(func() { notesDir, cfg, baseCfg, activeVault, readOnly = oldDir, oldCfg, oldBase, oldVault, oldReadOnly })
+	work, home := t.TempDir(), t.TempDir()
+	baseCfg = config{
+		BackupFormat: formatZip,
+		Vaults: map[string]vaultConfig{
+			"work":    {Dir: work, BackupDir: filepath.Join(work, "backups"), BackupFormat: formatTarGz, ReadOnly: true},
+			"home":    {Dir: home},
+			"nowhere": {},
+		},
+	}
+
+	if err := useVault("work"); err != nil {
+		t.Fatal(err)
+	}
+	if notesDir != work || cfg.BackupDir != filepath.Join(work, "backups") || cfg.BackupFormat != formatTarGz || !readOnly || activeVault != "work" {
+		t.Errorf("work vault opened as %s, %+v, read-only %v", notesDir, cfg, readOnly)
+	}
+	if err := useVault("home"); err != nil {
+		t.Fatal(err)
+	}
+	if notesDir != home || cfg.BackupDir != "" || cfg.BackupFormat != formatZip || readOnly || activeVault != "home" {
+		t.Errorf("home vault kept settings of the last one: %s, %+v, read-only %v", notesDir, cfg, readOnly)
+	}
+	for _, name := range []string{"nowhere", "missing"} {
+		if err := useVault(name); err == nil {
+			t.Errorf("useVault(%q) succeeded", name)
+		}
+	}
+	if activeVault != "home" || notesDir != home {
+		t.Errorf("failed switch left %s open at %s", activeVault, notesDir)
+	}
+
+	items := vaultPickerItems()
+	if len(items) != 3 || items[0].value != "home" || items[0].title != "home (current)" || items[2].value != "work" {
+		t.Errorf("picker lists %+v", items)
+	}
+}
+
+func TestSwitchVault(t *testing.T) {
+	oldDir, oldCfg, oldBase, oldVault, oldReadOnly := notesDir, cfg, baseCfg, activeVault, readOnly
+	t.Cleanup(func() { notesDir, cfg, baseCfg, activeVault, readOnly = oldDir, oldCfg, oldBase, oldVault, oldReadOnly })
+	dir := filepath.Join(t.TempDir(), "journal")
+	baseCfg = config{Vaults: map[string]vaultConfig{"journal": {Dir: dir}}}
+
+	m := initialModel()
+	m.mode = "pick"
+	next, cmd := m.switchVault("journal")
+	m = next.(model)
+	if m.mode != "list" || m.list.Title != "Notes · journal" || cmd == nil {
+		t.Errorf("after switching: mode %q, title %q, reload %v", m.mode, m.list.Title, cmd != nil)
+	}
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		t.Errorf("vault directory not created: %v", err)
+	}
+}
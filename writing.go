@@ -0,0 +1,142 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// wordLog holds the words written per day, keyed by notes directory and then
+// by day
+type wordLog map[string]map[string]int
+
+// Serializes updates to the word log from concurrent saves
+var wordLogMu sync.Mutex
+
+// wordLogPath returns the file the word log is kept in
+func wordLogPath() string {
+	return filepath.Join(configDir(), "words.json")
+}
+
+// loadWordLog reads the word log, returning an empty log when there is none
+func loadWordLog() wordLog {
+	log := make(wordLog)
+	if data, err := os.ReadFile(wordLogPath()); err == nil {
+		json.Unmarshal(data, &log)
+	}
+	return log
+}
+
+// recordWords adds the words a save added to today's count for the vault.
+// Deleting text does not reduce the count, and frontmatter is not writing.
+func recordWords(before, after string) {
+	_, beforeBody := parseFrontmatter(before)
+	_, afterBody := parseFrontmatter(after)
+	added := wordCount(afterBody) - wordCount(beforeBody)
+	if added <= 0 {
+		return
+	}
+
+	wordLogMu.Lock()
+	defer wordLogMu.Unlock()
+	log := loadWordLog()
+	if log[notesDir] == nil {
+		log[notesDir] = make(map[string]int)
+	}
+	log[notesDir][dayKey(localNow())] += added
+
+	if data, err := json.Marshal(log); err == nil && os.MkdirAll(configDir(), 0755) == nil {
+		os.WriteFile(wordLogPath(), data, 0644)
+	}
+}
+
+// goalMet reports whether a day's words count towards the streak: any
+// writing at all, or reaching the daily goal when one is set
+func goalMet(words int) bool {
+	if cfg.DailyWordGoal > 0 {
+		return words >= cfg.DailyWordGoal
+	}
+	return words > 0
+}
+
+// writingStreak returns the number of consecutive days up to today on which
+// the goal was met. Today only breaks the streak once it is over.
+func writingStreak(days map[string]int, now time.Time) int {
+	day := now
+	if !goalMet(days[dayKey(day)]) {
+		day = day.AddDate(0, 0, -1)
+	}
+	streak := 0
+	for goalMet(days[dayKey(day)]) {
+		streak++
+		day = day.AddDate(0, 0, -1)
+	}
+	return streak
+}
+
+// longestStreak returns the longest run of days on which the goal was met
+func longestStreak(days map[string]int) int {
+	longest := 0
+	for key, words := range days {
+		day, err := time.ParseInLocation("2006-01-02", key, displayZone)
+		// Only count from the first day of each run
+		if err != nil || !goalMet(words) || goalMet(days[dayKey(day.AddDate(0, 0, -1))]) {
+			continue
+		}
+		run := 0
+		for goalMet(days[dayKey(day)]) {
+			run++
+			day = day.AddDate(0, 0, 1)
+		}
+		longest = max(longest, run)
+	}
+	return longest
+}
+
+// writingProgress summarizes today's words and the streak for the status bar
+func writingProgress(days map[string]int, now time.Time) string {
+	today := days[dayKey(now)]
+	if cfg.DailyWordGoal <= 0 && today == 0 && len(days) == 0 {
+		return ""
+	}
+
+	progress := trf("Today %d words", today)
+	if cfg.DailyWordGoal > 0 {
+		progress = trf("Today %d/%d words", today, cfg.DailyWordGoal)
+		if today >= cfg.DailyWordGoal {
+			progress += " ✓"
+		}
+	}
+	if streak := writingStreak(days, now); streak > 0 {
+		progress += " · " + trf("%d-day streak", streak)
+	}
+	return progress
+}
+
+// renderWordHistory charts the words written over the past two weeks
+func renderWordHistory(days map[string]int, now time.Time) string {
+	const shown, barWidth = 14, 30
+	most := max(1, cfg.DailyWordGoal)
+	for i := 0; i < shown; i++ {
+		most = max(most, days[dayKey(now.AddDate(0, 0, -i))])
+	}
+
+	var rows []string
+	for i := shown - 1; i >= 0; i-- {
+		day := now.AddDate(0, 0, -i)
+		words := days[dayKey(day)]
+		bar := heatmapLevels[len(heatmapLevels)-1].Render(strings.Repeat("█", words*barWidth/most))
+		if !goalMet(words) {
+			bar = heatmapLevels[1].Render(strings.Repeat(heatmapCell(2), words*barWidth/most))
+		}
+		rows = append(rows, statsLabelStyle.Render(tr(day.Format("Mon"))+" "+tr(day.Format("Jan"))+day.Format(" 02")+"  ")+bar+fmt.Sprintf(" %d", words))
+	}
+	rows = append(rows, "",
+		statsLabelStyle.Render(tr("Current streak")+" ")+trf("%d days", writingStreak(days, now))+"   "+
+			statsLabelStyle.Render(tr("Longest streak")+" ")+trf("%d days", longestStreak(days)))
+	return strings.Join(rows, "\n")
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"syscall"
+)
+
+// dataDir returns the directory gleaner keeps notes and backups in by
+// default: $XDG_DATA_HOME/gleaner, or ~/.local/share/gleaner when that is
+// unset. macOS and Windows have no such convention, so it is empty there
+// and the home folder is used instead. In portable mode it is the folder
+// next to the executable.
+func dataDir() string {
+	if portableDir != "" {
+		return portableDir
+	}
+	if runtime.GOOS == "windows" || runtime.GOOS == "darwin" {
+		return ""
+	}
+	dir := os.Getenv("XDG_DATA_HOME")
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(homeDir(), ".local", "share")
+	}
+	return filepath.Join(dir, "gleaner")
+}
+
+// dataPath returns where the named data directory goes by default. Until
+// it exists, one left at legacy by an older gleaner is used instead, for
+// example while the vault is read-only and cannot be moved.
+func dataPath(name, legacy string) string {
+	dir := dataDir()
+	if dir == "" {
+		return legacy
+	}
+	path := filepath.Join(dir, name)
+	if _, err := os.Lstat(path); err != nil && portableDir == "" {
+		if _, err := os.Lstat(legacy); err == nil {
+			return legacy
+		}
+	}
+	return path
+}
+
+// Where gleaner kept notes and backups before following the XDG base
+// directories, and still does on macOS and Windows
+func legacyNotesDir() string  { return filepath.Join(homeDir(), ".notes") }
+func legacyBackupDir() string { return filepath.Join(homeDir(), ".notes-backups") }
+
+// defaultNotesDir returns the notes directory used when none is configured
+func defaultNotesDir() string {
+	return dataPath("notes", legacyNotesDir())
+}
+
+// defaultBackupDir returns the backup directory used when none is configured
+func defaultBackupDir() string {
+	return dataPath("backups", legacyBackupDir())
+}
+
+// migrateDataDirs moves notes and backups from ~/.notes and ~/.notes-backups
+// to the data directory, unless the config file points elsewhere. Nothing
+// moves when explicitDir is set, as the notes were named with --dir or
+// GLEANER_DIR for this run and the legacy folders may not be the user's
+// usual ones. A symlink is left at each old place so scripts and other
+// tools still find them. It returns a line saying what moved, if anything.
+func migrateDataDirs(explicitDir bool) (string, error) {
+	if explicitDir {
+		return "", nil
+	}
+	var moved []string
+	var dirs [][2]string
+	if baseCfg.NotesDir == "" {
+		dirs = append(dirs, [2]string{"notes", legacyNotesDir()})
+	}
+	if baseCfg.BackupDir == "" {
+		dirs = append(dirs, [2]string{"backups", legacyBackupDir()})
+	}
+	for _, d := range dirs {
+		target, err := migrateDataDir(d[0], d[1])
+		if err != nil {
+			return "", fmt.Errorf("cannot move %s to the data directory: %w", d[1], err)
+		}
+		if target != "" {
+			slog.Info("moved data directory", "from", d[1], "to", target)
+			moved = append(moved, trf("%s to %s", d[1], target))
+		}
+	}
+	if len(moved) == 0 {
+		return "", nil
+	}
+	return trf("Moved %s", strings.Join(moved, ", ")), nil
+}
+
+// migrateDataDir moves the legacy directory to its place in the data
+// directory, returning the new path, or "" when there was nothing to move
+func migrateDataDir(name, legacy string) (string, error) {
+	dir := dataDir()
+	if dir == "" || portableDir != "" {
+		return "", nil
+	}
+	target := filepath.Join(dir, name)
+	if _, err := os.Lstat(target); err == nil {
+		return "", nil
+	}
+	stayed := filepath.Join(dir, "."+name+"-not-moved")
+	if _, err := os.Lstat(stayed); err == nil {
+		return "", nil
+	}
+	info, err := os.Lstat(legacy)
+	if err != nil {
+		return "", nil
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+
+	// A link, say to a synced drive, is copied rather than moved, since a
+	// relative link would point somewhere else from its new place
+	if info.Mode()&os.ModeSymlink != 0 {
+		real, err := filepath.EvalSymlinks(legacy)
+		if err != nil {
+			return "", err
+		}
+		return target, os.Symlink(real, target)
+	}
+
+	// A folder on another file system cannot be renamed into place, and
+	// copying a whole vault at launch is too slow; it stays where it is, and
+	// a marker stops the move being tried, and warned about, every launch
+	if err := os.Rename(legacy, target); err != nil {
+		if errors.Is(err, syscall.EXDEV) {
+			if markErr := os.WriteFile(stayed, []byte(legacy+"\n"), 0644); markErr == nil {
+				return "", fmt.Errorf("%w; it stays where it is and will not be moved again", err)
+			}
+		}
+		return "", err
+	}
+	if err := os.Symlink(target, legacy); err != nil {
+		slog.Warn("could not link the old data directory", "path", legacy, "err", err)
+	}
+	return target, nil
+}
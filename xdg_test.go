@@ -0,0 +1,91 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// useHome gives the test a home folder of its own, with no XDG variables
+// set, on a system that follows the XDG base directories
+func useHome(t *testing.T) string {
+	t.Helper()
+	if runtime.GOOS == "windows" || runtime.GOOS == "darwin" {
+		t.Skip("no XDG base directories on " + runtime.GOOS)
+	}
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("XDG_DATA_HOME", "")
+	oldPortable, oldBase := portableDir, baseCfg
+	t.Cleanup(func() { portableDir, baseCfg = oldPortable, oldBase })
+	portableDir, baseCfg = "", config{}
+	return home
+}
+
+func TestDataDirs(t *testing.T) {
+	home := useHome(t)
+	if got, want := defaultNotesDir(), filepath.Join(home, ".local", "share", "gleaner", "notes"); got != want {
+		t.Errorf("notes in %s, want %s", got, want)
+	}
+	if got, want := configDir(), filepath.Join(home, ".config", "gleaner"); got != want {
+		t.Errorf("config in %s, want %s", got, want)
+	}
+
+	data := filepath.Join(home, "data")
+	t.Setenv("XDG_DATA_HOME", data)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, "conf"))
+	if got, want := defaultBackupDir(), filepath.Join(data, "gleaner", "backups"); got != want {
+		t.Errorf("backups in %s, want %s", got, want)
+	}
+	if got, want := configDir(), filepath.Join(home, "conf", "gleaner"); got != want {
+		t.Errorf("config in %s, want %s", got, want)
+	}
+
+	// The specification says relative paths are to be ignored
+	t.Setenv("XDG_DATA_HOME", "data")
+	if got, want := dataDir(), filepath.Join(home, ".local", "share", "gleaner"); got != want {
+		t.Errorf("data in %s, want %s", got, want)
+	}
+}
+
+func TestMigrateDataDirs(t *testing.T) {
+	home := useHome(t)
+	legacy := filepath.Join(home, ".notes")
+	if err := os.MkdirAll(legacy, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(legacy, "1700000000-Plan.md"), []byte("plan\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// Until it moves, the notes are found where they are
+	if got := defaultNotesDir(); got != legacy {
+		t.Errorf("notes in %s before moving, want %s", got, legacy)
+	}
+
+	if line, err := migrateDataDirs(true); line != "" || err != nil {
+		t.Errorf("moved with --dir given: %q, %v", line, err)
+	}
+	line, err := migrateDataDirs(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	target := filepath.Join(home, ".local", "share", "gleaner", "notes")
+	if !strings.Contains(line, legacy+" to "+target) {
+		t.Errorf("reported %q", line)
+	}
+	if got := defaultNotesDir(); got != target {
+		t.Errorf("notes in %s after moving, want %s", got, target)
+	}
+	if data, err := os.ReadFile(filepath.Join(target, "1700000000-Plan.md")); string(data) != "plan\n" {
+		t.Errorf("moved note is %q, %v", data, err)
+	}
+	if link, err := os.Readlink(legacy); link != target || err != nil {
+		t.Errorf("old place links to %q, %v", link, err)
+	}
+	if line, err := migrateDataDirs(false); line != "" || err != nil {
+		t.Errorf("moved a second time: %q, %v", line, err)
+	}
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Column width of the writing area in zen mode unless configured
+const defaultZenWidth = 72
+
+// Word count styling in zen mode
+var zenCountStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+
+// zenWidth returns the writing column width for zen mode
+func zenWidth() int {
+	if cfg.ZenWidth > 0 {
+		return cfg.ZenWidth
+	}
+	return defaultZenWidth
+}
+
+// wordCount counts whitespace-separated words
+func wordCount(text string) int {
+	return len(strings.Fields(text))
+}
+
+// toggleZen enters or leaves distraction-free writing mode
+func (m model) toggleZen() model {
+	m.zen = !m.zen
+	if m.zen {
+		m.textarea.Prompt = ""
+	} else {
+		m.textarea.Prompt = editorPrompt()
+	}
+	if m.mode == "list" {
+		m = m.focusContent(m.zen || m.hideList)
+	}
+	return m.resize()
+}
+
+// zenView renders only the editor, or the scratchpad when it is open,
+// centered, with the word count of what is shown underneath
+func (m model) zenView() string {
+	column, text := m.textarea.View(), m.textarea.Value()
+	if m.mode == "scratch" {
+		column, text = m.scratch.View(), m.scratch.Value()
+	} else if m.mode == "new" || m.mode == "edit" {
+		column = lipgloss.JoinVertical(lipgloss.Left, m.textInput.View(), "", column)
+	}
+	count := zenCountStyle.Render(trf("%d words", wordCount(text)))
+	if words, goal := noteGoal(text); goal > 0 && m.mode != "scratch" {
+		count = zenCountStyle.Render(trf("%d/%d words", words, goal))
+	}
+	column = lipgloss.JoinVertical(lipgloss.Center, column, "", count)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, column)
+}
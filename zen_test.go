@@ -0,0 +1,52 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestZenViewCountsWhatIsShown(t *testing.T) {
+	m := initialModel()
+	m.width, m.height = 100, 30
+	m.zen = true
+	m.textarea.SetValue("---\ngoal: 10\n---\none two three")
+	m.scratch.SetValue("just two")
+
+	tests := []struct {
+		mode, want string
+	}{
+		{"list", "3/10 words"},
+		{"edit", "3/10 words"},
+		{"scratch", "2 words"},
+	}
+	for _, tt := range tests {
+		m.mode = tt.mode
+		if view := m.zenView(); !strings.Contains(view, tt.want) {
+			t.Errorf("%s: count is not %q:\n%s", tt.mode, tt.want, view)
+		}
+	}
+
+	m.mode = "list"
+	m.textarea.SetValue("no goal here")
+	if view := m.zenView(); !strings.Contains(view, "3 words") {
+		t.Errorf("count is not 3 words:\n%s", view)
+	}
+}
+
+func TestWordCount(t *testing.T) {
+	tests := []struct {
+		text string
+		want int
+	}{
+		{"", 0},
+		{"  \n\t ", 0},
+		{"one", 1},
+		{"one  two\nthree\tfour", 4},
+		{"don't stop-me now", 3},
+	}
+	for _, tt := range tests {
+		if got := wordCount(tt.text); got != tt.want {
+			t.Errorf("wordCount(%q) = %d, want %d", tt.text, got, tt.want)
+		}
+	}
+}